@@ -3,7 +3,17 @@ module github.com/nxneeraj/hx-hawks
 go 1.20 // Or your preferred Go version, e.g., 1.21, 1.22
 
 require (
+	github.com/andybalholm/cascadia v1.3.2
 	github.com/fatih/color v1.15.0 // Using a slighly newer version, adjust if needed
 	github.com/google/uuid v1.3.1 // Using a slightly newer version, adjust if needed
-	// github.com/gorilla/mux v1.8.0 // Uncomment if you decide to use Gorilla Mux later
+	go.etcd.io/bbolt v1.3.8
+	golang.org/x/net v0.17.0
+	golang.org/x/text v0.13.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/mattn/go-colorable v0.1.13 // indirect
+	github.com/mattn/go-isatty v0.0.17 // indirect
+	golang.org/x/sys v0.13.0 // indirect
 )
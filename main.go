@@ -1,35 +1,175 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"runtime"
+	"strings"
+	"syscall"
 
 	
 	"github.com/nxneeraj/hx-hawks/pkg/api"
+	"github.com/nxneeraj/hx-hawks/pkg/bench"
+	"github.com/nxneeraj/hx-hawks/pkg/campaign"
 	"github.com/nxneeraj/hx-hawks/pkg/config"
+	"github.com/nxneeraj/hx-hawks/pkg/diff"
+	"github.com/nxneeraj/hx-hawks/pkg/importer"
+	"github.com/nxneeraj/hx-hawks/pkg/output"
+	"github.com/nxneeraj/hx-hawks/pkg/remote"
+	"github.com/nxneeraj/hx-hawks/pkg/rules"
 	"github.com/nxneeraj/hx-hawks/pkg/scanner"
+	"github.com/nxneeraj/hx-hawks/pkg/types"
 	"github.com/nxneeraj/hx-hawks/pkg/utils"
 )
 
 func main() {
-	// Utilize max CPU cores
-	runtime.GOMAXPROCS(runtime.NumCPU())
+	// Use the CPUs the container allows, not every core of the host; an
+	// explicit GOMAXPROCS environment variable wins
+	if os.Getenv("GOMAXPROCS") == "" {
+		runtime.GOMAXPROCS(utils.AvailableCPUs())
+	}
 
-	fmt.Println(`
+	// --silent keeps stdout for vulnerable URLs, so it is checked before
+	// anything is printed
+	if !silentRequested(os.Args[1:]) {
+		fmt.Println(`
     Hx-H.A.W.K.S - High Accuracy Web Keywords Scanner
     -------------------------------------------------
     `)
+	}
+
+	// Root context: SIGINT/SIGTERM cancel the scan, API server, remote stream
+	// and output writers alike
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		// Partial results are written after the first signal; a second one
+		// kills the process right away
+		<-ctx.Done()
+		stop()
+	}()
+
+	// "scan" is the default subcommand: `hx-hawks scan ...` == `hx-hawks ...`
+	if len(os.Args) > 1 && os.Args[1] == "scan" {
+		os.Args = append(os.Args[:1], os.Args[2:]...)
+	}
+
+	// --- Bench Mode ---
+	if len(os.Args) > 1 && os.Args[1] == "bench" {
+		benchCfg, err := config.ParseBenchFlags(os.Args[2:])
+		if err != nil {
+			log.Fatalf("[-] %v", err)
+		}
+		report, err := bench.Run(benchCfg)
+		if err != nil {
+			log.Fatalf("[-] Benchmark failed: %v", err)
+		}
+		report.Print(os.Stdout)
+		return
+	}
+
+	// --- Diff Mode (exit status gates CI on regressions) ---
+	if len(os.Args) > 1 && os.Args[1] == "diff" {
+		diffCfg, err := config.ParseDiffFlags(os.Args[2:])
+		if err != nil {
+			log.Printf("[-] %v", err)
+			os.Exit(config.DiffExitError)
+		}
+		os.Exit(runDiff(diffCfg))
+	}
 
-	cfg := config.ParseFlags()
+	// --- Campaign Mode (group runs, combined reporting and diffing) ---
+	if len(os.Args) > 1 && os.Args[1] == "campaign" {
+		campaignCfg, err := config.ParseCampaignFlags(os.Args[2:])
+		if err != nil {
+			log.Printf("[-] %v", err)
+			os.Exit(config.DiffExitError)
+		}
+		os.Exit(runCampaign(campaignCfg))
+	}
+
+	// --- Import Mode (convert other scanners' output into hx-hawks reports) ---
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		importCfg, err := config.ParseImportFlags(os.Args[2:])
+		if err != nil {
+			log.Fatalf("[-] %v", err)
+		}
+		var results []types.ScanResult
+		for _, path := range importCfg.Inputs {
+			imported, err := importer.ImportFile(path, importCfg.Format)
+			if err != nil {
+				log.Fatalf("[-] Import failed: %v", err)
+			}
+			log.Printf("[+] Imported %d results from %s", len(imported), path)
+			results = append(results, imported...)
+		}
+		if err := output.WriteResultsToFile(ctx, importCfg.OutputConfig(), results); err != nil {
+			log.Fatalf("[-] Writing imported results failed: %v", err)
+		}
+		return
+	}
+
+	// --- Verify Mode (does a report correspond to these targets and rules?) ---
+	if len(os.Args) > 1 && os.Args[1] == "verify" {
+		verifyCfg, err := config.ParseVerifyFlags(os.Args[2:])
+		if err != nil {
+			log.Printf("[-] %v", err)
+			os.Exit(config.VerifyExitError)
+		}
+		os.Exit(runVerify(verifyCfg))
+	}
+
+	cfg, err := config.ParseFlags()
+	if err != nil {
+		log.Fatalf("[-] %v", err)
+	}
+	if cfg.Silent {
+		log.SetOutput(output.ErrorsOnly(os.Stderr))
+		output.SetSilent(true)
+	}
+	if cfg.NoColor {
+		output.DisableColor()
+	}
+	if err := output.LoadTheme(cfg.Theme); err != nil {
+		log.Fatalf("[-] Invalid --theme: %v", err)
+	}
+	output.SetPlainLog(cfg.PlainLog)
+	if err := output.SetTemplate(cfg.OutputTemplate); err != nil {
+		log.Fatalf("[-] Invalid -o-template: %v", err)
+	}
+	if cfg.MaxCPUs > 0 && cfg.MaxCPUs < runtime.GOMAXPROCS(0) {
+		runtime.GOMAXPROCS(cfg.MaxCPUs)
+		log.Printf("[+] Using %d of %d CPUs (--max-cpus)", cfg.MaxCPUs, runtime.NumCPU())
+	} else if procs := runtime.GOMAXPROCS(0); procs < runtime.NumCPU() {
+		log.Printf("[+] Using %d of %d CPUs (container CPU quota or GOMAXPROCS)", procs, runtime.NumCPU())
+	}
+	if len(cfg.Resolvers) > 0 {
+		utils.SetResolvers(cfg.Resolvers)
+		log.Printf("[+] Resolving hosts through %s", strings.Join(cfg.Resolvers, ", "))
+	}
+	if len(cfg.Resolve) > 0 {
+		utils.SetOverrides(cfg.Resolve)
+		log.Printf("[+] %d hosts pinned with --resolve", len(cfg.Resolve))
+	}
 
 	// --- API Mode ---
 	if cfg.API {
-		api.StartServer(cfg.APIPort)
+		api.StartServer(ctx, cfg)
 		os.Exit(0) // Exit after server setup/shutdown
 	}
 
+	// --- Remote Mode (reattach needs no local input) ---
+	if cfg.Remote != "" && cfg.Attach != "" {
+		if err := remote.Run(ctx, cfg, nil); err != nil {
+			log.Fatalf("[-] Remote scan failed: %v", err)
+		}
+		return
+	}
+
 	// --- CLI Mode ---
 	log.Println("[+] Starting CLI mode.")
 
@@ -37,23 +177,265 @@ func main() {
     if cfg.InputFile == "" {
         log.Fatal("[-] Input file (-f) is required for CLI mode.")
     }
-    if len(cfg.Keywords) == 0 {
-         log.Fatal("[-] Keywords (--ck) are required for CLI mode.")
+    if len(cfg.Keywords) == 0 && len(cfg.Rules) == 0 && !cfg.Entropy {
+         log.Fatal("[-] Keywords (--ck), rules (--rules/--recipe) or --entropy are required for CLI mode.")
     }
 
 	// Read URLs from input file
-	urls, err := utils.ReadLines(cfg.InputFile)
+	targets, err := utils.ReadLines(cfg.InputFile)
 	if err != nil {
-		log.Fatalf("[-] Error reading input file '%s': %v", cfg.InputFile, err)
+		log.Fatalf("[-] %v", err)
+	}
+
+	// Submit to a remote API server instead of scanning locally; it probes
+	// the scheme of bare hosts itself, from where it scans
+	if cfg.Remote != "" {
+		if err := remote.Run(ctx, cfg, targets); err != nil {
+			log.Fatalf("[-] Remote scan failed: %v", err)
+		}
+		return
+	}
+
+	// Record exactly what is scanned so reports can be verified against it later
+	cfg.Inputs = scanner.Digest(targets, cfg.Keywords, cfg.KeywordScope, cfg.Rules)
+	log.Printf("[+] Inputs: targets sha256:%s, rules sha256:%s", cfg.Inputs.TargetsSHA256, cfg.Inputs.RulesSHA256)
+
+	// Bare hosts (example.com) are scanned over https, or http if that's all they speak
+	urls := scanner.ProbeSchemes(ctx, cfg, targets)
+
+	// Paths listed in robots.txt and sitemaps (--sitemap) are scanned like input URLs
+	if cfg.Sitemap {
+		urls = append(urls, scanner.SeedFromSitemaps(ctx, cfg, urls)...)
+	}
+
+	// Rules with probe paths (e.g. recipes) add targets for every base URL
+	urls = rules.ExpandTargets(urls, cfg.Rules)
+	// Virtual host mode: every target once per Host name
+	if len(cfg.VHosts) > 0 {
+		urls = scanner.ExpandVHosts(urls, cfg.VHosts)
+		log.Printf("[+] Virtual host mode: %d Host names, %d requests", len(cfg.VHosts), len(urls))
+	}
+	// Spread requests across hosts when the input is grouped by domain
+	if cfg.Shuffle {
+		urls = scanner.Shuffle(urls)
+	}
+	if cfg.Interleave {
+		urls = scanner.InterleaveByHost(urls)
 	}
 
-	if len(urls) == 0 {
-		log.Fatalf("[-] No valid URLs found in input file: %s", cfg.InputFile)
+	// Measure network conditions on a sample before the real scan
+	if cfg.Calibrate {
+		calibration := scanner.Calibrate(ctx, cfg, urls, cfg.CalibrateSample)
+		calibration.Log()
+		if cfg.CalibrateApply {
+			calibration.Apply(cfg)
+			log.Println("[+] Applied calibrated settings.")
+		}
+	}
+
+	if cfg.StoreResponses != "" {
+		if err := os.MkdirAll(cfg.StoreResponses, 0o755); err != nil {
+			log.Fatalf("[-] Creating --store-responses directory: %v", err)
+		}
 	}
 
 	// Create and run the scanner
 	scan := scanner.NewScanner(cfg)
-	_ = scan.Run(urls) // Results are processed and saved within Run()
+	if cfg.ResumeFile != "" {
+		if urls, err = scan.Resume(cfg.ResumeFile, urls); err != nil {
+			log.Fatalf("[-] %v", err)
+		}
+	}
+	results := scan.Run(ctx, urls) // Results are processed and saved within Run()
+	if ctx.Err() != nil {
+		// Partial runs are kept out of campaigns, where they'd look like regressions
+		log.Printf("[!] Scan interrupted; output files hold the %d results collected.", len(results))
+		os.Exit(130)
+	}
+
+	// Keep the run in its campaign for combined reporting and diffing
+	if cfg.Campaign != "" {
+		run, err := campaign.NewStore(cfg.CampaignDir).Record(cfg.Campaign, cfg.CampaignLabel, results)
+		if err != nil {
+			log.Printf("[!] Could not record run in campaign %s: %v", cfg.Campaign, err)
+		} else {
+			log.Printf("[+] Recorded as run %s of campaign %s", run.ID, cfg.Campaign)
+		}
+	}
 
 	log.Println("[+] Hx-H.A.W.K.S scan complete.")
 } // Removed the trailing '0' here
+
+// runVerify checks the input digest recorded in a report against the digest
+// of the given targets and rules and returns the process exit code.
+func runVerify(cfg *config.VerifyConfig) int {
+	results, err := diff.Load(cfg.Report)
+	if err != nil {
+		log.Printf("[-] Reading report: %v", err)
+		return config.VerifyExitError
+	}
+	var recorded *types.InputDigest
+	for _, r := range results {
+		if r.Inputs == nil {
+			continue
+		}
+		if recorded != nil && *r.Inputs != *recorded {
+			log.Printf("[-] %s mixes results from scans with different inputs", cfg.Report)
+			return config.VerifyExitMismatch
+		}
+		recorded = r.Inputs
+	}
+	if recorded == nil {
+		log.Printf("[-] %s records no input digest (empty, imported, or written with --fields without inputs)", cfg.Report)
+		return config.VerifyExitError
+	}
+
+	targets, err := utils.ReadLines(cfg.Scan.InputFile)
+	if err != nil {
+		log.Printf("[-] %v", err)
+		return config.VerifyExitError
+	}
+	actual := scanner.Digest(targets, cfg.Scan.Keywords, cfg.Scan.KeywordScope, cfg.Scan.Rules)
+
+	code := config.VerifyExitMatch
+	if actual.TargetsSHA256 == recorded.TargetsSHA256 {
+		log.Printf("[+] Targets match (sha256:%s)", actual.TargetsSHA256)
+	} else {
+		log.Printf("[-] Targets differ: report sha256:%s, %s sha256:%s", recorded.TargetsSHA256, cfg.Scan.InputFile, actual.TargetsSHA256)
+		code = config.VerifyExitMismatch
+	}
+	if actual.RulesSHA256 == recorded.RulesSHA256 {
+		log.Printf("[+] Keywords and rules match (sha256:%s)", actual.RulesSHA256)
+	} else {
+		log.Printf("[-] Keywords/rules differ: report sha256:%s, given sha256:%s", recorded.RulesSHA256, actual.RulesSHA256)
+		code = config.VerifyExitMismatch
+	}
+	if code == config.VerifyExitMatch {
+		log.Printf("[+] %s was produced from these inputs.", cfg.Report)
+	}
+	return code
+}
+
+// runDiff compares two reports, writes the changes file if requested and
+// returns the process exit code.
+func runDiff(cfg *config.DiffConfig) int {
+	oldResults, err := diff.Load(cfg.OldReport)
+	if err != nil {
+		log.Printf("[-] Reading old report: %v", err)
+		return config.DiffExitError
+	}
+	newResults, err := diff.Load(cfg.NewReport)
+	if err != nil {
+		log.Printf("[-] Reading new report: %v", err)
+		return config.DiffExitError
+	}
+
+	report := diff.Compare(oldResults, newResults)
+	report.Old, report.New = cfg.OldReport, cfg.NewReport
+	return finishDiff(report, cfg.ChangesFile, cfg.FailOn)
+}
+
+// finishDiff prints a comparison, writes the changes file if requested and
+// returns the exit code for the --fail-on policy.
+func finishDiff(report *diff.Report, changesFile, failOn string) int {
+	report.Print(os.Stdout)
+
+	if changesFile != "" {
+		if err := report.WriteFile(changesFile); err != nil {
+			log.Printf("[-] Writing changes file: %v", err)
+			return config.DiffExitError
+		}
+		log.Printf("[+] Changes written to: %s", changesFile)
+	}
+	if report.ShouldFail(failOn) {
+		return config.DiffExitRegressed
+	}
+	return config.DiffExitClean
+}
+
+// runCampaign executes `hx-hawks campaign <action>` and returns the exit code.
+func runCampaign(cfg *config.CampaignConfig) int {
+	store := campaign.NewStore(cfg.Dir)
+
+	switch cfg.Action {
+	case "list":
+		names, err := store.List()
+		if err != nil {
+			log.Printf("[-] Listing campaigns: %v", err)
+			return config.DiffExitError
+		}
+		for _, name := range names {
+			runs, _ := store.Runs(name)
+			fmt.Printf("%-32s %d runs\n", name, len(runs))
+		}
+
+	case "show":
+		summary, err := store.Summary(cfg.Name)
+		if err != nil {
+			log.Printf("[-] %v", err)
+			return config.DiffExitError
+		}
+		summary.Print(os.Stdout)
+		if cfg.Output != "" {
+			data, _ := json.MarshalIndent(summary, "", "  ")
+			if err := os.WriteFile(cfg.Output, append(data, '\n'), 0644); err != nil {
+				log.Printf("[-] Writing campaign report: %v", err)
+				return config.DiffExitError
+			}
+			log.Printf("[+] Campaign report saved to: %s", cfg.Output)
+		}
+
+	case "add":
+		for _, path := range cfg.Files {
+			results, err := diff.Load(path)
+			if err != nil {
+				log.Printf("[-] %v", err)
+				return config.DiffExitError
+			}
+			run, err := store.Record(cfg.Name, cfg.Label, results)
+			if err != nil {
+				log.Printf("[-] Recording %s: %v", path, err)
+				return config.DiffExitError
+			}
+			log.Printf("[+] Recorded %s as run %s of campaign %s (%d results)", path, run.ID, cfg.Name, run.Total)
+		}
+
+	case "diff":
+		runs, err := store.Runs(cfg.Name)
+		if err != nil {
+			log.Printf("[-] %v", err)
+			return config.DiffExitError
+		}
+		from, to, err := campaign.Pick(runs, cfg.From, cfg.To)
+		if err != nil {
+			log.Printf("[-] %v", err)
+			return config.DiffExitError
+		}
+		oldResults, err := store.Results(cfg.Name, runs[from].ID)
+		if err != nil {
+			log.Printf("[-] %v", err)
+			return config.DiffExitError
+		}
+		newResults, err := store.Results(cfg.Name, runs[to].ID)
+		if err != nil {
+			log.Printf("[-] %v", err)
+			return config.DiffExitError
+		}
+		report := diff.Compare(oldResults, newResults)
+		report.Old, report.New = runs[from].ID, runs[to].ID
+		return finishDiff(report, cfg.ChangesFile, cfg.FailOn)
+	}
+	return config.DiffExitClean
+}
+
+// silentRequested reports whether args turn on --silent, before the flags
+// are parsed.
+func silentRequested(args []string) bool {
+	for _, arg := range args {
+		switch strings.TrimLeft(arg, "-") {
+		case "silent", "silent=true", "silent=1":
+			return strings.HasPrefix(arg, "-")
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/nxneeraj/hx-hawks/pkg/config"
+)
+
+// keyNameContextKey carries the name of the API key a request was made with.
+type keyNameContextKey struct{}
+
+// KeyAuth requires one of a set of API keys on every request (--api-key,
+// --api-keys-file). Clients send it as "Authorization: Bearer <key>", in an
+// X-API-Key header, or as ?api_key= where they can't set headers (browser
+// EventSource and WebSocket). A nil *KeyAuth lets every request through.
+type KeyAuth struct {
+	Limiter *RateLimiter // Limits failed attempts by IP, so keys can't be guessed at full speed (nil = unlimited)
+
+	names  []string
+	hashes [][sha256.Size]byte // SHA-256 of each key, compared in constant time
+}
+
+// NewKeyAuth returns the authenticator for keys, or nil when there are none.
+func NewKeyAuth(keys []config.APIKey) *KeyAuth {
+	if len(keys) == 0 {
+		return nil
+	}
+	a := &KeyAuth{}
+	for _, k := range keys {
+		a.names = append(a.names, k.Name)
+		a.hashes = append(a.hashes, sha256.Sum256([]byte(k.Key)))
+	}
+	return a
+}
+
+// Middleware rejects requests without a valid key with 401, except signed
+// download links (/scan/download/), which carry their own signature, health
+// probes and the API description.
+func (a *KeyAuth) Middleware(next http.Handler) http.Handler {
+	if a == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/scan/download/") || probePath(r.URL.Path) || docsPath(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		key := requestKey(r)
+		name, ok := a.lookup(key)
+		if !ok {
+			if a.Limiter.limit(w, r) {
+				return
+			}
+			reason := "invalid API key"
+			if key == "" {
+				reason = "missing API key"
+			}
+			log.Printf("[API] Rejected %s %s from %s: %s", r.Method, r.URL.Path, r.RemoteAddr, reason)
+			w.Header().Set("WWW-Authenticate", `Bearer realm="hx-hawks"`)
+			http.Error(w, "Unauthorized: "+reason, http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), keyNameContextKey{}, name)))
+	})
+}
+
+// lookup returns the name of key, checking every key so the time taken
+// doesn't reveal which one matched.
+func (a *KeyAuth) lookup(key string) (string, bool) {
+	if key == "" {
+		return "", false
+	}
+	sum := sha256.Sum256([]byte(key))
+	name, found := "", false
+	for i, h := range a.hashes {
+		if subtle.ConstantTimeCompare(sum[:], h[:]) == 1 && !found {
+			name, found = a.names[i], true
+		}
+	}
+	return name, found
+}
+
+// requestKey returns the API key sent with r.
+func requestKey(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); len(auth) > 7 && strings.EqualFold(auth[:7], "Bearer ") {
+		return strings.TrimSpace(auth[7:])
+	}
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return key
+	}
+	return r.URL.Query().Get("api_key")
+}
+
+// byKey describes who made r for the logs: " (key <name>)", or "" without
+// authentication.
+func byKey(r *http.Request) string {
+	if name, ok := r.Context().Value(keyNameContextKey{}).(string); ok {
+		return " (key " + name + ")"
+	}
+	return ""
+}
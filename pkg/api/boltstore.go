@@ -0,0 +1,206 @@
+package api
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+var (
+	jobsBucket      = []byte("jobs")      // Job ID -> StoredJob JSON
+	resultsBucket   = []byte("results")   // Job ID -> bucket of result index -> ScanResult JSON
+	templatesBucket = []byte("templates") // Template ID -> JobTemplate JSON
+	schedulesBucket = []byte("schedules") // Schedule ID -> JobSchedule JSON
+)
+
+// BoltStore is a JobStore in a single BoltDB file (--api-db).
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// OpenBoltStore opens (creating if needed) the store at path. It fails after
+// a second if another server holds the file.
+func OpenBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{jobsBucket, resultsBucket, templatesBucket, schedulesBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("initializing %s: %w", path, err)
+	}
+	return &BoltStore{db: db}, nil
+}
+
+// SaveJob implements JobStore.
+func (s *BoltStore) SaveJob(job *StoredJob, results []types.ScanResult) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if len(results) > 0 {
+			b, err := tx.Bucket(resultsBucket).CreateBucketIfNotExists([]byte(job.Status.JobID))
+			if err != nil {
+				return err
+			}
+			first := job.Results - len(results)
+			for i := range results {
+				value, err := json.Marshal(&results[i])
+				if err != nil {
+					return err
+				}
+				if err := b.Put(resultKey(first+i), value); err != nil {
+					return err
+				}
+			}
+		}
+		return tx.Bucket(jobsBucket).Put([]byte(job.Status.JobID), data)
+	})
+}
+
+// LoadJobs implements JobStore.
+func (s *BoltStore) LoadJobs() ([]*StoredJob, error) {
+	var jobs []*StoredJob
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(jobsBucket).ForEach(func(k, v []byte) error {
+			job := &StoredJob{}
+			if err := json.Unmarshal(v, job); err != nil {
+				return fmt.Errorf("job %s: %w", k, err)
+			}
+			jobs = append(jobs, job)
+			return nil
+		})
+	})
+	return jobs, err
+}
+
+// LoadResults implements JobStore.
+func (s *BoltStore) LoadResults(jobID string, from int) ([]types.ScanResult, error) {
+	var results []types.ScanResult
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(resultsBucket).Bucket([]byte(jobID))
+		if b == nil {
+			return nil // No results, or the job was deleted
+		}
+		c := b.Cursor()
+		for k, v := c.Seek(resultKey(from)); k != nil; k, v = c.Next() {
+			var result types.ScanResult
+			if err := json.Unmarshal(v, &result); err != nil {
+				return fmt.Errorf("job %s result %d: %w", jobID, binary.BigEndian.Uint64(k), err)
+			}
+			results = append(results, result)
+		}
+		return nil
+	})
+	return results, err
+}
+
+// DeleteJob implements JobStore.
+func (s *BoltStore) DeleteJob(jobID string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(resultsBucket).DeleteBucket([]byte(jobID)); err != nil && err != bolt.ErrBucketNotFound {
+			return err
+		}
+		return tx.Bucket(jobsBucket).Delete([]byte(jobID))
+	})
+}
+
+// SaveTemplate implements JobStore.
+func (s *BoltStore) SaveTemplate(tmpl *types.JobTemplate) error {
+	data, err := json.Marshal(tmpl)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(templatesBucket).Put([]byte(tmpl.ID), data)
+	})
+}
+
+// LoadTemplates implements JobStore.
+func (s *BoltStore) LoadTemplates() ([]*types.JobTemplate, error) {
+	var templates []*types.JobTemplate
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(templatesBucket).ForEach(func(k, v []byte) error {
+			tmpl := &types.JobTemplate{}
+			if err := json.Unmarshal(v, tmpl); err != nil {
+				return fmt.Errorf("template %s: %w", k, err)
+			}
+			templates = append(templates, tmpl)
+			return nil
+		})
+	})
+	return templates, err
+}
+
+// DeleteTemplate implements JobStore.
+func (s *BoltStore) DeleteTemplate(id string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(templatesBucket).Delete([]byte(id))
+	})
+}
+
+// SaveSchedule implements JobStore.
+func (s *BoltStore) SaveSchedule(sched *types.JobSchedule) error {
+	data, err := json.Marshal(sched)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(schedulesBucket).Put([]byte(sched.ID), data)
+	})
+}
+
+// LoadSchedules implements JobStore.
+func (s *BoltStore) LoadSchedules() ([]*types.JobSchedule, error) {
+	var schedules []*types.JobSchedule
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(schedulesBucket).ForEach(func(k, v []byte) error {
+			sched := &types.JobSchedule{}
+			if err := json.Unmarshal(v, sched); err != nil {
+				return fmt.Errorf("schedule %s: %w", k, err)
+			}
+			schedules = append(schedules, sched)
+			return nil
+		})
+	})
+	return schedules, err
+}
+
+// DeleteSchedule implements JobStore.
+func (s *BoltStore) DeleteSchedule(id string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(schedulesBucket).Delete([]byte(id))
+	})
+}
+
+// Close implements JobStore.
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+// String describes the store in logs.
+func (s *BoltStore) String() string {
+	return fmt.Sprintf("BoltDB %s", s.db.Path())
+}
+
+// resultKey is the key of a job's i-th result: big-endian, so cursor order
+// is result order.
+func resultKey(i int) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(i))
+	return key
+}
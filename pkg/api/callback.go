@@ -0,0 +1,223 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+const (
+	callbackQueueSize = 1024 // Result events waiting for delivery; more are dropped
+	callbackRetries   = 3    // Retries of a failed delivery (network errors, 429, 5xx)
+	callbackRetryWait = time.Second
+)
+
+// callbackClient delivers every callback.
+var callbackClient = &http.Client{Timeout: 15 * time.Second}
+
+// callback POSTs a job's events to its callback_url: a "result" event per
+// result when asked for (callback_results), then a "complete" event with
+// the final status. Events are delivered in order by one goroutine, so a
+// slow receiver never holds up the scan; result events beyond
+// callbackQueueSize are dropped and counted in the "complete" event.
+type callback struct {
+	url     string
+	secret  []byte        // HMAC key of X-Hawks-Signature; nil = unsigned
+	results string        // "" (completion only), "vulnerable" or "all"
+	request *http.Request // Host the job was started on, for its download link
+
+	events   chan types.CallbackPayload // Closed once the job finishes
+	dropped  int                        // Guarded by the manager's mu
+	complete *types.CallbackPayload     // Set before events is closed
+}
+
+// ValidateCallback checks the callback fields of a scan request.
+func ValidateCallback(req types.ScanRequest) error {
+	if req.CallbackURL == "" {
+		if req.CallbackResults != "" || req.CallbackSecret != "" {
+			return errors.New("callback_results and callback_secret need a callback_url")
+		}
+		return nil
+	}
+	u, err := url.Parse(req.CallbackURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("callback_url must be an http(s) URL, got %q", req.CallbackURL)
+	}
+	switch req.CallbackResults {
+	case "", "vulnerable", "all":
+		return nil
+	default:
+		return fmt.Errorf("callback_results must be vulnerable or all, got %q", req.CallbackResults)
+	}
+}
+
+// SetCallback starts delivering a job's events to the callback of req
+// (validated by ValidateCallback), signed with req.CallbackSecret or else
+// CallbackSecret. r is the request that started the job.
+func (m *ScanManager) SetCallback(jobID string, req types.ScanRequest, r *http.Request) {
+	if req.CallbackURL == "" {
+		return
+	}
+	cb := &callback{
+		url:     req.CallbackURL,
+		results: req.CallbackResults,
+		request: &http.Request{Host: r.Host, TLS: r.TLS},
+		events:  make(chan types.CallbackPayload, callbackQueueSize),
+	}
+	if secret := req.CallbackSecret; secret != "" {
+		cb.secret = []byte(secret)
+	} else if m.CallbackSecret != "" {
+		cb.secret = []byte(m.CallbackSecret)
+	}
+	m.mu.Lock()
+	m.callbacks[jobID] = cb
+	m.mu.Unlock()
+	go cb.run(m.ctx, jobID)
+}
+
+// callbackResult queues the "result" event of a job's index-th (1-based)
+// result, if its callback wants it. Callers hold m.mu.
+func (m *ScanManager) callbackResult(jobID string, index int, result types.ScanResult) {
+	cb, ok := m.callbacks[jobID]
+	if !ok || cb.results == "" || (cb.results == "vulnerable" && !result.IsVulnerable) {
+		return
+	}
+	result.ResponseBody = "" // Receivers fetch bodies from /scan/result if they need them
+	select {
+	case cb.events <- types.CallbackPayload{Event: "result", JobID: jobID, Index: index, Result: &result}:
+	default:
+		cb.dropped++
+	}
+}
+
+// finishCallback queues the "complete" event of a finished job. Callers
+// hold m.mu.
+func (m *ScanManager) finishCallback(jobID string) {
+	cb, ok := m.callbacks[jobID]
+	job, exists := m.jobs[jobID]
+	if !ok || !exists {
+		return
+	}
+	delete(m.callbacks, jobID)
+	status := statusCopy(job)
+	if m.Links != nil {
+		link, expires := m.Links.Link(cb.request, jobID, time.Now())
+		status.DownloadURL, status.DownloadExpires = link, &expires
+	}
+	cb.complete = &types.CallbackPayload{Event: "complete", JobID: jobID, Status: status, Dropped: cb.dropped}
+	close(cb.events)
+}
+
+// run delivers the events of a job until its "complete" event is sent, or
+// the server shuts down.
+func (cb *callback) run(ctx context.Context, jobID string) {
+	for ev := range cb.events {
+		cb.send(ctx, jobID, ev)
+	}
+	if cb.complete != nil {
+		cb.send(ctx, jobID, *cb.complete)
+	}
+}
+
+// send delivers one event, retrying network errors, 429 and 5xx answers
+// with exponential backoff (or the receiver's Retry-After).
+func (cb *callback) send(ctx context.Context, jobID string, ev types.CallbackPayload) {
+	ev.SentAt = time.Now().UTC()
+	body, err := json.Marshal(ev)
+	if err != nil {
+		log.Printf("[API Job %s] Encoding %s callback: %v", jobID, ev.Event, err)
+		return
+	}
+	wait := callbackRetryWait
+	for attempt := 0; ; attempt++ {
+		retryAfter, err := cb.post(ctx, jobID, ev.Event, body)
+		if err == nil {
+			return
+		}
+		if retryAfter < 0 || attempt == callbackRetries || ctx.Err() != nil {
+			log.Printf("[API Job %s] Delivering %s callback to %s failed: %v", jobID, ev.Event, callbackHost(cb.url), err)
+			return
+		}
+		if retryAfter > wait {
+			wait = retryAfter
+		}
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+		}
+		wait *= 2
+	}
+}
+
+// post makes one delivery attempt. On failure it returns how long the
+// receiver asked to wait (0 if it didn't), or -1 if retrying won't help.
+func (cb *callback) post(ctx context.Context, jobID, event string, body []byte) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cb.url, bytes.NewReader(body))
+	if err != nil {
+		return -1, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Hx-H.A.W.K.S Scanner (github.com/nxneeraj/hx-hawks)")
+	req.Header.Set("X-Hawks-Event", event)
+	req.Header.Set("X-Hawks-Job", jobID)
+	req.Header.Set("X-Hawks-Timestamp", timestamp)
+	if cb.secret != nil {
+		req.Header.Set("X-Hawks-Signature", "sha256="+signCallback(cb.secret, timestamp, body))
+	}
+	resp, err := callbackClient.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err // The URL may carry a token
+		}
+		return 0, err
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	resp.Body.Close()
+	switch {
+	case resp.StatusCode < 300:
+		return 0, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		secs, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+		after := time.Duration(secs) * time.Second
+		if after > time.Minute {
+			after = time.Minute
+		}
+		return after, fmt.Errorf("receiver answered %s", resp.Status)
+	default:
+		return -1, fmt.Errorf("receiver answered %s", resp.Status)
+	}
+}
+
+// signCallback returns the hex HMAC-SHA256 of "<timestamp>.<body>", which
+// receivers recompute to check X-Hawks-Signature (and reject old
+// timestamps to stop replays).
+func signCallback(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// callbackHost names a callback in logs without its path or query, which
+// may carry a token.
+func callbackHost(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil {
+		return u.Scheme + "://" + u.Host
+	}
+	return "callback"
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/nxneeraj/hx-hawks/pkg/campaign"
+	"github.com/nxneeraj/hx-hawks/pkg/diff"
+)
+
+// CampaignsHandler lists the campaigns known to the server.
+// GET /campaigns
+func (h *APIHandler) CampaignsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.Manager.ListCampaigns())
+}
+
+// CampaignHandler serves a campaign's combined report and run comparison.
+// GET /campaigns/{id}
+// GET /campaigns/{id}/diff?from={jobID}&to={jobID} (default: last two finished jobs)
+func (h *APIHandler) CampaignHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/campaigns/"), "/"), "/")
+	if parts[0] == "" || len(parts) > 2 || (len(parts) == 2 && parts[1] != "diff") {
+		http.NotFound(w, r)
+		return
+	}
+	name := parts[0]
+
+	runs, results, err := h.Manager.CampaignRuns(name)
+	if errors.Is(err, errCampaignNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+
+	var body interface{}
+	if len(parts) == 1 {
+		body = campaign.Summarize(name, runs, results)
+	} else {
+		from, to, err := campaign.Pick(runs, r.URL.Query().Get("from"), r.URL.Query().Get("to"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		report := diff.Compare(results[from], results[to])
+		report.Old, report.New = runs[from].ID, runs[to].ID
+		body = report
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
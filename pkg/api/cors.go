@@ -0,0 +1,73 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+)
+
+// corsMaxAge is how long (seconds) browsers may cache a preflight answer.
+const corsMaxAge = "600"
+
+// CORS lets browser frontends on other origins call the API
+// (--api-cors-origins). A nil *CORS adds no headers, so browsers keep
+// blocking cross-origin calls.
+type CORS struct {
+	Origins []string // Allowed origins, e.g. https://ui.example.com; "*" allows any
+	Methods []string // Methods allowed in preflight answers
+	Headers []string // Request headers allowed in preflight answers
+}
+
+// NewCORS returns the CORS policy for origins, or nil when there are none.
+func NewCORS(origins, methods, headers []string) *CORS {
+	if len(origins) == 0 {
+		return nil
+	}
+	return &CORS{Origins: origins, Methods: methods, Headers: headers}
+}
+
+// Middleware adds CORS headers for allowed origins and answers their
+// preflight requests itself, before authentication: browsers don't send
+// credentials with a preflight. Preflights from other origins get 403.
+func (c *CORS) Middleware(next http.Handler) http.Handler {
+	if c == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r) // Not a cross-origin browser request
+			return
+		}
+		w.Header().Add("Vary", "Origin")
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+		if !c.allowed(origin) {
+			if preflight {
+				http.Error(w, "Origin not allowed", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r) // No CORS headers: the browser withholds the answer
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		if preflight {
+			w.Header().Set("Access-Control-Allow-Methods", strings.Join(c.Methods, ", "))
+			w.Header().Set("Access-Control-Allow-Headers", strings.Join(c.Headers, ", "))
+			w.Header().Set("Access-Control-Max-Age", corsMaxAge)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Access-Control-Expose-Headers", "Content-Disposition, X-Total-Count")
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allowed reports whether origin may call the API.
+func (c *CORS) allowed(origin string) bool {
+	for _, o := range c.Origins {
+		if o == "*" || strings.EqualFold(o, origin) {
+			return true
+		}
+	}
+	return false
+}
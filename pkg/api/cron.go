@@ -0,0 +1,137 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronMacros are the shorthand schedules accepted besides five fields.
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronExpr is a parsed cron expression: "minute hour day-of-month month
+// day-of-week" with *, lists, ranges and steps (e.g. "*/15 9-17 * * 1-5"),
+// evaluated in UTC.
+type cronExpr struct {
+	minute, hour, dom, month, dow uint64 // Bit i set: value i matches
+	anyDay                        bool   // Day of month or of week is "*"
+}
+
+// parseCron parses a cron expression or macro.
+func parseCron(expr string) (*cronExpr, error) {
+	expr = strings.TrimSpace(expr)
+	if macro, ok := cronMacros[strings.ToLower(expr)]; ok {
+		expr = macro
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("want 5 fields (minute hour day-of-month month day-of-week) or a macro like @daily, got %q", expr)
+	}
+	c := &cronExpr{anyDay: fields[2] == "*" || fields[4] == "*"}
+	bounds := []struct {
+		name     string
+		min, max int
+		set      *uint64
+	}{
+		{"minute", 0, 59, &c.minute},
+		{"hour", 0, 23, &c.hour},
+		{"day of month", 1, 31, &c.dom},
+		{"month", 1, 12, &c.month},
+		{"day of week", 0, 7, &c.dow},
+	}
+	for i, b := range bounds {
+		set, err := parseCronField(fields[i], b.min, b.max)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", b.name, err)
+		}
+		*b.set = set
+	}
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1 // 7 is Sunday too
+	}
+	if c.next(time.Now()).IsZero() {
+		return nil, errors.New("expression never matches")
+	}
+	return c, nil
+}
+
+// parseCronField parses one comma-separated field into a bit set.
+func parseCronField(field string, min, max int) (uint64, error) {
+	var set uint64
+	for _, item := range strings.Split(field, ",") {
+		rng, stepStr, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepStr); err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step %q", stepStr)
+			}
+		}
+		lo, hi := min, max
+		if rng != "*" {
+			loStr, hiStr, isRange := strings.Cut(rng, "-")
+			var err error
+			if lo, err = strconv.Atoi(loStr); err != nil {
+				return 0, fmt.Errorf("invalid value %q", loStr)
+			}
+			hi = lo
+			if isRange {
+				if hi, err = strconv.Atoi(hiStr); err != nil {
+					return 0, fmt.Errorf("invalid value %q", hiStr)
+				}
+			} else if hasStep {
+				hi = max // "5/15" means 5-max/15
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q is outside %d-%d", item, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	return set, nil
+}
+
+// next returns the first matching minute after t, or the zero time if
+// there is none within five years (e.g. "0 0 30 2 *").
+func (c *cronExpr) next(t time.Time) time.Time {
+	t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case c.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !c.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+		case c.hour&(1<<uint(t.Hour())) == 0:
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case c.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// dayMatches applies cron's day rule: when both day fields are restricted,
+// either may match.
+func (c *cronExpr) dayMatches(t time.Time) bool {
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.anyDay {
+		return dom && dow
+	}
+	return dom || dow
+}
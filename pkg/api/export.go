@@ -0,0 +1,94 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/nxneeraj/hx-hawks/pkg/config"
+	"github.com/nxneeraj/hx-hawks/pkg/output"
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+// ResultSink receives a finished job, results included, before it leaves the
+// in-memory store (server shutdown or job deletion).
+type ResultSink interface {
+	Export(ctx context.Context, job *types.JobStatus) error
+}
+
+// DirSink writes each job through the CLI output writers into a directory:
+// <jobID>.json (full JSON report, honouring --fields) and <jobID>.txt
+// (vulnerable URLs).
+type DirSink struct {
+	Dir    string
+	Fields []string
+}
+
+// Export implements ResultSink.
+func (s *DirSink) Export(ctx context.Context, job *types.JobStatus) error {
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return err
+	}
+	base := filepath.Join(s.Dir, job.JobID)
+	cfg := &config.Config{
+		OutputFile:    base + ".txt",
+		OutputAllJSON: base + ".json",
+		Fields:        s.Fields,
+	}
+	return output.WriteResultsToFile(ctx, cfg, job.Results)
+}
+
+// String describes the sink in logs.
+func (s *DirSink) String() string {
+	return fmt.Sprintf("directory %s", s.Dir)
+}
+
+// exportJob hands a finished job to every sink once. Running jobs and jobs
+// already exported are skipped; it reports whether the job was exported.
+func (m *ScanManager) exportJob(ctx context.Context, jobID string) bool {
+	m.mu.Lock()
+	job, exists := m.jobs[jobID]
+	if !exists || len(m.Sinks) == 0 || m.exported[jobID] || !jobFinished(job.Status) {
+		m.mu.Unlock()
+		return false
+	}
+	m.exported[jobID] = true
+	m.mu.Unlock()
+
+	results, _, status, _, err := m.ResultsSince(jobID, 0)
+	if err != nil {
+		log.Printf("[API] Exporting job %s failed: %v", jobID, err)
+		return false
+	}
+	snapshot := *status
+	snapshot.Results = results
+
+	for _, sink := range m.Sinks {
+		if err := sink.Export(ctx, &snapshot); err != nil {
+			log.Printf("[API] Exporting job %s to %v failed: %v", jobID, sink, err)
+		}
+	}
+	return true
+}
+
+// Flush exports every finished job that has not been exported yet and
+// returns how many were. Called on server shutdown so results aren't lost
+// with the in-memory store.
+func (m *ScanManager) Flush(ctx context.Context) int {
+	m.mu.RLock()
+	ids := make([]string, 0, len(m.jobs))
+	for id := range m.jobs {
+		ids = append(ids, id)
+	}
+	m.mu.RUnlock()
+
+	flushed := 0
+	for _, id := range ids {
+		if m.exportJob(ctx, id) {
+			flushed++
+		}
+	}
+	return flushed
+}
@@ -3,8 +3,12 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -12,8 +16,12 @@ import (
 	
 	"github.com/nxneeraj/hx-hawks/pkg/config"
 	"github.com/nxneeraj/hx-hawks/pkg/httpclient"
+	"github.com/nxneeraj/hx-hawks/pkg/notify"
+	"github.com/nxneeraj/hx-hawks/pkg/output"
+	"github.com/nxneeraj/hx-hawks/pkg/rules"
 	"github.com/nxneeraj/hx-hawks/pkg/scanner"
 	"github.com/nxneeraj/hx-hawks/pkg/types"
+	"github.com/nxneeraj/hx-hawks/pkg/utils"
 
 	// Use gorilla/mux or stick to net/http's default mux
 	// "github.com/gorilla/mux"
@@ -21,7 +29,11 @@ import (
 
 // APIHandler holds dependencies for API endpoints.
 type APIHandler struct {
-	Manager *ScanManager
+	Manager         *ScanManager
+	MaxRequestBytes int64 // Largest JSON request body accepted (0 = unlimited)
+	MaxUploadBytes  int64 // Largest target list accepted by /scan/upload (0 = unlimited)
+	MaxThreads      int   // Most workers a job may ask for; larger requests are lowered (0 = unlimited)
+	CORS            *CORS // Origins allowed to open /ws/scan besides the API's own (nil = none)
 }
 
 // NewAPIHandler creates a new handler instance.
@@ -38,185 +50,303 @@ func (h *APIHandler) StartScanHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var requestBody struct {
-		URLs       []string `json:"urls"`
-		Keywords   []string `json:"keywords"`
-		TimeoutSec int      `json:"timeout_sec"`
-		Threads    int      `json:"threads"`
-		DelayMs    int      `json:"delay_ms"`
-		Verbose    bool     `json:"verbose"` // Allow setting verbose for API scan
-		// Add other relevant config options if needed (duration, etc.)
+	var requestBody types.ScanRequest
+	if !h.decodeBody(w, r, &requestBody, false) {
+		return
 	}
+	h.startScan(w, r, requestBody)
+}
 
-	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+// decodeBody decodes r's JSON body, of at most MaxRequestBytes, into v. It
+// answers 413 for larger bodies and 400 for invalid ones, returning false;
+// an empty body is accepted when optional is set.
+func (h *APIHandler) decodeBody(w http.ResponseWriter, r *http.Request, v interface{}, optional bool) bool {
+	defer r.Body.Close()
+	if h.MaxRequestBytes > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, h.MaxRequestBytes)
+	}
+	err := json.NewDecoder(r.Body).Decode(v)
+	var tooLarge *http.MaxBytesError
+	switch {
+	case err == nil, optional && errors.Is(err, io.EOF):
+		return true
+	case errors.As(err, &tooLarge):
+		log.Printf("[API] Rejected %s %s from %s: body over %d bytes%s", r.Method, r.URL.Path, r.RemoteAddr, tooLarge.Limit, byKey(r))
+		http.Error(w, fmt.Sprintf("Request body too large (limit %d bytes)", tooLarge.Limit), http.StatusRequestEntityTooLarge)
+	default:
 		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
-		return
 	}
-	defer r.Body.Close()
+	return false
+}
 
+// startScan validates a scan request, creates its job and runs it in the
+// background, answering 202 with the job ID. It returns the job ID, or "" if
+// the request was rejected (the error response is already written).
+func (h *APIHandler) startScan(w http.ResponseWriter, r *http.Request, requestBody types.ScanRequest) string {
+	if requestBody.TargetList != "" {
+		listed, err := h.Manager.TargetList(requestBody.TargetList)
+		if err != nil {
+			http.Error(w, "target_list: "+err.Error(), http.StatusBadRequest)
+			return ""
+		}
+		requestBody.URLs = append(listed, requestBody.URLs...)
+	}
 	if len(requestBody.URLs) == 0 {
 		http.Error(w, "URLs list cannot be empty", http.StatusBadRequest)
-		return
+		return ""
 	}
-	if len(requestBody.Keywords) == 0 {
+	if len(requestBody.Keywords) == 0 && len(requestBody.Recipes) == 0 && len(requestBody.Selectors) == 0 && len(requestBody.JSONPaths) == 0 && !requestBody.Entropy {
 		http.Error(w, "Keywords list cannot be empty", http.StatusBadRequest)
-		return
+		return ""
 	}
 
-	// --- Create a config specifically for this API scan ---
-	apiConfig := &config.Config{
-		// InputFile not used in API mode directly like this
-		Keywords:    requestBody.Keywords,
-		KeywordsRaw: strings.Join(requestBody.Keywords, ","), // Store raw for consistency if needed
-		Threads:     10,                                       // Default
-		Timeout:     10 * time.Second,                         // Default
-		Delay:       0 * time.Millisecond,                     // Default
-		Verbose:     requestBody.Verbose,                      // Use value from request
-		// API specific fields
-		API:     true,
-		APIPort: 0, // Not relevant for the scan job itself
-	}
-	// Override defaults with request values
-	if requestBody.Threads > 0 {
-		apiConfig.Threads = requestBody.Threads
+	// Same defaults and checks as the command-line flags
+	apiConfig, err := config.FromScanRequest(requestBody)
+	if err != nil {
+		http.Error(w, optionError(err), http.StatusBadRequest)
+		return ""
 	}
-	if requestBody.TimeoutSec > 0 {
-		apiConfig.Timeout = time.Duration(requestBody.TimeoutSec) * time.Second
-	} else if requestBody.TimeoutSec == 0 {
-        // Allow 0 for very fast checks, but usually default is better
-		apiConfig.Timeout = 10 * time.Second // Ensure a default if 0 or negative provided inappropriately
-        log.Println("[API] Timeout defaulting to 10s for job")
+	if h.MaxThreads > 0 && apiConfig.Threads > h.MaxThreads {
+		log.Printf("[API] Lowering threads from %d to the server maximum %d%s", apiConfig.Threads, h.MaxThreads, byKey(r))
+		apiConfig.Threads = h.MaxThreads
 	}
-	if requestBody.DelayMs >= 0 {
-		apiConfig.Delay = time.Duration(requestBody.DelayMs) * time.Millisecond
+	if err = ValidateCallback(requestBody); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return ""
 	}
 
 	// Validate URLs (basic check)
-	validURLs := []string{}
-	for _, u := range requestBody.URLs {
-		trimmed := strings.TrimSpace(u)
-		if trimmed != "" && (strings.HasPrefix(trimmed, "http://") || strings.HasPrefix(trimmed, "https://")) {
-			validURLs = append(validURLs, trimmed)
-		} else {
-			log.Printf("[API] Skipping invalid URL format from request: %s", u)
-		}
-	}
+	validURLs := validateURLs(requestBody.URLs, true)
 	if len(validURLs) == 0 {
 		http.Error(w, "No valid URLs provided in the list", http.StatusBadRequest)
-		return
+		return ""
+	}
+	if h.overURLQuota(w, len(validURLs)) {
+		return ""
+	}
+
+	apiConfig.Inputs = scanner.Digest(validURLs, apiConfig.Keywords, apiConfig.KeywordScope, apiConfig.Rules)
+	// Bare hosts (example.com) are scanned over https, or http if that's all they speak
+	validURLs = scanner.ProbeSchemes(r.Context(), apiConfig, validURLs)
+	validURLs = rules.ExpandTargets(validURLs, apiConfig.Rules)
+	if len(apiConfig.VHosts) > 0 && h.overURLQuota(w, len(validURLs)*len(apiConfig.VHosts)) {
+		return ""
+	}
+	validURLs = scanner.ExpandVHosts(validURLs, apiConfig.VHosts)
+	if apiConfig.Shuffle {
+		validURLs = scanner.Shuffle(validURLs)
+	}
+	if apiConfig.Interleave {
+		validURLs = scanner.InterleaveByHost(validURLs)
+	}
+	if h.overURLQuota(w, len(validURLs)) {
+		return ""
 	}
 
 	// Create a job ID
-	jobID := h.Manager.CreateJob(len(validURLs))
-	log.Printf("[API] Created Scan Job ID: %s for %d URLs", jobID, len(validURLs))
+	jobID := h.Manager.CreateJob(len(validURLs), apiConfig.Threads)
+	log.Printf("[API] Created Scan Job ID: %s for %d URLs%s", jobID, len(validURLs), byKey(r))
+	h.Manager.SetInputs(jobID, apiConfig.Inputs)
+	h.Manager.SetCallback(jobID, requestBody, r)
+	if requestBody.Campaign != "" {
+		h.Manager.AssignCampaign(jobID, requestBody.Campaign, requestBody.Label)
+		log.Printf("[API] Job %s belongs to campaign %s", jobID, requestBody.Campaign)
+	}
 
-	// --- Start the scan in a background goroutine ---
-	go func(jobID string, cfg *config.Config, urlsToScan []string) {
-		log.Printf("[API Job %s] Starting scan...", jobID)
+	// Cancelled with the job (POST /scan/cancel/{id}) or on server shutdown
+	scanCtx, cancel := h.Manager.JobContext(jobID)
+
+	// --- Start the scan in the background once a scan slot is free ---
+	run := func(jobID string, cfg *config.Config, urlsToScan []string) {
+		defer h.Manager.trackGoroutine(jobID)()
+		// Job progress and worker logs also go to the job's own log (GET /scan/logs/{id})
+		logger := h.Manager.JobLogger(jobID)
+		cfg.Logger = logger
+		logger.Printf("[API Job %s] Starting scan...", jobID)
 		// Mark as running immediately
 		err := h.Manager.UpdateJobStatus(jobID, "Running", nil)
 		if err != nil {
-			log.Printf("[API Job %s] Failed to set status to Running: %v", jobID, err)
+			logger.Printf("[API Job %s] Failed to set status to Running: %v", jobID, err)
 			// If we can't even update the status, something is wrong, bail out?
 			return
 		}
 
 		// Create HTTP client and necessary channels
-		client := httpclient.NewClient(cfg.Timeout)
+		client := httpclient.NewClient(cfg)
+
+		// Output targets of the request: results go to es_url and findings
+		// to the notify providers as they are collected
+		var es *output.ESIndexer
+		if cfg.ESURL != "" {
+			if es, err = output.NewESIndexer(cfg.ESURL, cfg.ESIndex, cfg.Fields); err != nil {
+				logger.Printf("[API Job %s] Failed to start Elasticsearch indexing: %v", jobID, err)
+			}
+		}
+		notifier := notify.NewDispatcher(cfg.Notifiers, cfg.NotifyEvents, cfg.NotifyBatch, cfg.NotifyInterval)
+		summary := notify.Summary{Started: time.Now().UTC()}
+
 		urlChan := make(chan string, cfg.Threads)
 		resultChan := make(chan types.ScanResult, cfg.Threads)
 		var wg sync.WaitGroup
-		scanCtx, cancel := context.WithCancel(context.Background()) // Use cancellable context
-		defer cancel()                                             // Ensure cancellation
+		defer cancel() // Ensure cancellation
+
+		// Build the keyword automaton and rules once for all workers
+		engine := rules.NewEngine(cfg.Keywords, cfg.Rules)
+		engine.KeywordScope = cfg.KeywordScope
+
+		// Shared body-hash index for dedupe_responses
+		var seen *scanner.ResponseIndex
+		if cfg.DedupeResponses {
+			seen = scanner.NewResponseIndex()
+		}
+
+		// Heartbeats and stall diagnostics go to the job log
+		monitor := scanner.NewMonitor()
+		monitorCtx, stopMonitor := context.WithCancel(scanCtx)
+		defer stopMonitor()
+		go func() {
+			defer h.Manager.trackGoroutine(jobID)()
+			monitor.Run(monitorCtx, logger, cfg.Heartbeat, cfg.StallTimeout, cfg.StallAbort)
+		}()
 
 		// Start workers
 		wg.Add(cfg.Threads)
 		for i := 0; i < cfg.Threads; i++ {
 			go func(workerID int) {
 				defer wg.Done()
+				defer h.Manager.trackGoroutine(jobID)()
 				// Use the scanner.Worker directly
-				scanner.Worker(scanCtx, workerID, client, cfg.Keywords, cfg.Delay, urlChan, resultChan, cfg.Verbose)
+				scanner.Worker(scanCtx, workerID, client, cfg, engine, seen, monitor, h.Manager.util, urlChan, resultChan)
 			}(i + 1)
 		}
 
-		// Feed URLs
+		// Feed URLs from the job queue, which POST /scan/{id}/targets can extend while running
+		queue := scanner.NewQueue(urlsToScan)
+		h.Manager.AttachQueue(jobID, queue, cfg.Rules)
+		var seeds []string
+		if cfg.Sitemap {
+			// Queued before feeding starts, so the job can't look finished in between
+			seeds = scanner.SeedFromSitemaps(scanCtx, cfg, urlsToScan)
+			if queued := h.Manager.QueueLinks(jobID, seeds); queued < len(seeds) {
+				logger.Printf("[API Job %s] Dropped %d seeded URLs over the job URL limit", jobID, len(seeds)-queued)
+			}
+		}
+		var crawler *scanner.Crawler
+		if cfg.Crawl {
+			crawler = scanner.NewCrawler(cfg.CrawlDepth, cfg.CrawlMaxPages, cfg.CrawlScope, cfg.CrawlExclude, append(urlsToScan, seeds...))
+		}
 		go func() {
-		feedLoop:
-			for _, u := range urlsToScan {
-				select {
-				case urlChan <- u:
-				case <-scanCtx.Done(): // Check context if channel blocks
-                    log.Printf("[API Job %s] Context cancelled during URL feed", jobID)
-					break feedLoop
-				}
+			defer h.Manager.trackGoroutine(jobID)()
+			if !queue.Feed(scanCtx, urlChan) { // Closes urlChan to signal workers no more URLs
+				logger.Printf("[API Job %s] Context cancelled during URL feed", jobID)
 			}
-			close(urlChan) // Signal workers no more URLs
-            log.Printf("[API Job %s] Finished feeding URLs", jobID)
+            logger.Printf("[API Job %s] Finished feeding URLs", jobID)
 		}()
 
 		// Collect results and update manager
         collectorDone := make(chan struct{}) // Signal channel for collector completion
 		go func() {
             defer close(collectorDone) // Signal completion when this goroutine exits
+			defer h.Manager.trackGoroutine(jobID)()
+			cancelled := scanCtx.Done()
         collectLoop:
 			for {
 				select {
 				case result, ok := <-resultChan:
 					if !ok {
-                        log.Printf("[API Job %s] Result channel closed", jobID)
+                        logger.Printf("[API Job %s] Result channel closed", jobID)
 						break collectLoop // Channel closed, workers are done
 					}
+					if crawler != nil {
+						// Queue links before the ack, so the queue never looks drained in between
+						if links := crawler.Discover(result); len(links) > 0 {
+							h.Manager.QueueLinks(jobID, links)
+						}
+					}
+					queue.Ack() // Lets the queue know when the job has gone idle
+					if !result.Filtered {
+						if err := es.Write(result); err != nil {
+							logger.Printf("[API Job %s] Failed to index %s: %v", jobID, result.URL, err)
+						}
+						notifier.Notify(result)
+						summary.Add(result)
+					}
 					err := h.Manager.AddResult(jobID, result)
 					if err != nil {
-						log.Printf("[API Job %s] Error adding result: %v. Stopping collection.", jobID, err)
+						logger.Printf("[API Job %s] Error adding result: %v. Stopping collection.", jobID, err)
                         // If we can't add results, maybe cancel the scan context?
                         cancel() // Cancel the scan if adding result fails critically
 						break collectLoop
 					}
-                case <-scanCtx.Done():
-                    log.Printf("[API Job %s] Context cancelled during result collection", jobID)
-                    break collectLoop // Exit if context cancelled
+                case <-cancelled:
+                    // Keep draining until the workers have exited and closed resultChan,
+                    // so results already sent are still stored
+                    logger.Printf("[API Job %s] Context cancelled during result collection, collecting results in flight", jobID)
+                    cancelled = nil
 				}
 			}
-            log.Printf("[API Job %s] Finished collecting results", jobID)
+            logger.Printf("[API Job %s] Finished collecting results", jobID)
 		}()
 
 		// Wait for all workers to finish
-        log.Printf("[API Job %s] Waiting for workers...", jobID)
+        logger.Printf("[API Job %s] Waiting for workers...", jobID)
 		wg.Wait()
-        log.Printf("[API Job %s] Workers finished.", jobID)
+		stopMonitor()
+        logger.Printf("[API Job %s] Workers finished.", jobID)
 
         // Close result channel *after* workers are done (signals collector)
         close(resultChan)
 
         // Wait for the collector to process all results from the closed channel
         <-collectorDone // Wait until collector signals it's done
-        log.Printf("[API Job %s] Result collector finished processing.", jobID)
+        logger.Printf("[API Job %s] Result collector finished processing.", jobID)
+		if es != nil {
+			closeCtx, cancelClose := context.WithTimeout(context.Background(), time.Minute)
+			if err := es.Close(closeCtx); err != nil {
+				logger.Printf("[API Job %s] Elasticsearch: gave up sending the last documents: %v", jobID, err)
+			}
+			cancelClose()
+			indexed, failed := es.Stats()
+			logger.Printf("[API Job %s] Elasticsearch: %d results indexed into %s, %d failed", jobID, indexed, cfg.ESIndex, failed)
+		}
+		if notifier != nil {
+			// The last batch and the summary go out even when the job was cancelled
+			summary.Finished, summary.Interrupted = time.Now().UTC(), scanCtx.Err() != nil
+			closeCtx, cancelClose := context.WithTimeout(context.Background(), 30*time.Second)
+			notifier.Close(closeCtx, &summary)
+			cancelClose()
+			logger.Printf("[API Job %s] Notifications: %s", jobID, notifier.Report())
+		}
 
 
 		// Mark job as completed (unless already marked as Error by AddResult failure)
 		// Check current status before overwriting
 		currentStatus, _ := h.Manager.GetJobStatus(jobID)
-		if currentStatus != nil && currentStatus.Status != "Error" {
+		if currentStatus != nil && currentStatus.Status == "Cancelled" {
+			logger.Printf("[API Job %s] Scan cancelled after %d of %d URLs.", jobID, currentStatus.ProcessedURLs, currentStatus.TotalURLs)
+			_ = h.Manager.UpdateJobStatus(jobID, "Completed", nil) // Keeps Cancelled, ends the job log
+		} else if currentStatus != nil && currentStatus.Status != "Error" {
 			_ = h.Manager.UpdateJobStatus(jobID, "Completed", nil)
-			log.Printf("[API Job %s] Scan marked as completed.", jobID)
+			logger.Printf("[API Job %s] Scan marked as completed.", jobID)
 		} else if currentStatus != nil {
-            log.Printf("[API Job %s] Scan finished with status: %s", jobID, currentStatus.Status)
+            logger.Printf("[API Job %s] Scan finished with status: %s", jobID, currentStatus.Status)
         } else {
-            log.Printf("[API Job %s] Scan finished, but job status was unexpectedly nil.", jobID)
+            logger.Printf("[API Job %s] Scan finished, but job status was unexpectedly nil.", jobID)
         }
 
 
-	}(jobID, apiConfig, validURLs) // Pass copies or necessary values
+	}
+	h.Manager.Schedule(jobID, requestBody.Priority, func() { run(jobID, apiConfig, validURLs) })
 
 	// Respond with the Job ID
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusAccepted) // 202 Accepted - job started
 	json.NewEncoder(w).Encode(map[string]string{"job_id": jobID})
+	return jobID
 }
 
 // ScanStatusHandler returns the status of a specific scan job.
-// GET /scan/status/{id}
+// GET /scan/status/{id}[?hosts=true]
 func (h *APIHandler) ScanStatusHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
@@ -246,18 +376,44 @@ func (h *APIHandler) ScanStatusHandler(w http.ResponseWriter, r *http.Request) {
 		http.NotFound(w, r) // 404 if job ID doesn't exist
 		return
 	}
+	if r.URL.Query().Get("hosts") == "true" {
+		status.Hosts, _ = h.Manager.GetJobHosts(jobID)
+	}
+	if h.Manager.Links != nil && jobFinished(status.Status) {
+		link, expires := h.Manager.Links.Link(r, jobID, time.Now())
+		status.DownloadURL, status.DownloadExpires = link, &expires
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(status)
 }
 
-// ScanResultHandler returns the final results of a completed scan job.
-// GET /scan/result/{id}
+// ScanResultHandler returns the results of a scan job, as JSON or, with
+// ?format=, rendered by the CLI output writers. Until the job finishes it
+// answers 202 with the results so far, marked "partial". Large jobs can
+// be fetched a page at a time with ?offset= and ?limit=, counted after the
+// tier, vulnerable and host filters.
+// GET /scan/result/{id}[?tier=vulnerable|interesting|safe|error][&vulnerable=true][&host=example.com][&offset=0][&limit=500][&format=csv|txt|html|jsonl|md|junit][&fields=url,status]
 func (h *APIHandler) ScanResultHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
 		return
 	}
+	filter, err := parseResultFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if _, ok := output.RenderFormats[format]; !ok && format != "" && format != "json" {
+		http.Error(w, "format must be json, csv, txt, html, jsonl, md or junit", http.StatusBadRequest)
+		return
+	}
+	fields, err := types.ParseFields(r.URL.Query().Get("fields"))
+	if err != nil {
+		http.Error(w, "fields: "+err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	// Extract job ID (same as status handler)
 	pathPrefix := "/scan/result/"
@@ -276,27 +432,33 @@ func (h *APIHandler) ScanResultHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	*/
 
-	// First, check the status to see if it's finished
-	status, err := h.Manager.GetJobStatus(jobID) // Use GetJobStatus first
-	if err != nil {
+	// Fetch the results together with the status they belong to; only the
+	// requested page is copied
+	results, matched, status, err := h.Manager.ResultPage(jobID, filter)
+	if errors.Is(err, errJobNotFound) {
 		http.NotFound(w, r) // 404 if job ID doesn't exist
 		return
-	}
-
-	if status.Status != "Completed" && status.Status != "Error" {
-		// Not finished, maybe return status code 202 Accepted or 400 Bad Request?
-		// Let's return 202 with the current status.
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusAccepted) // Indicate still processing
-		json.NewEncoder(w).Encode(status)  // Return status info
+	} else if err != nil {
+		http.Error(w, "Failed to retrieve results: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+	// A job that hasn't finished answers 202 with the results collected so
+	// far, so dashboards can show findings live
+	partial := !jobFinished(status.Status)
+	code := http.StatusOK
+	if partial {
+		code = http.StatusAccepted
+	}
 
-	// If completed or errored, fetch the actual results
-	results, err := h.Manager.GetJobResults(jobID) // Now get results (returns a copy)
-	if err != nil {
-		// Should not happen if GetJobStatus succeeded, but check anyway
-		http.Error(w, "Failed to retrieve results for completed job: "+err.Error(), http.StatusInternalServerError)
+	w.Header().Set("X-Total-Count", strconv.Itoa(matched))
+	if format != "" && format != "json" {
+		rendering := output.RenderFormats[format]
+		w.Header().Set("Content-Type", rendering.ContentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", jobID+"."+rendering.Ext))
+		w.WriteHeader(code)
+		if err := output.Render(w, format, results, fields); err != nil {
+			log.Printf("[API] Rendering job %s results as %s failed: %v", jobID, format, err)
+		}
 		return
 	}
 
@@ -305,13 +467,210 @@ func (h *APIHandler) ScanResultHandler(w http.ResponseWriter, r *http.Request) {
 	// Let's return the full JobStatus object for consistency, but with the Results array populated.
 	jobWithResults := status       // Start with the status we already fetched
 	jobWithResults.Results = results // Add the results copy
+	jobWithResults.ResultsMatched = &matched
+	jobWithResults.Partial = partial
+	if next := filter.offset + len(results); filter.limit > 0 && next < matched {
+		jobWithResults.NextOffset = next
+	}
 
+	w.WriteHeader(code)
 	json.NewEncoder(w).Encode(jobWithResults)
 }
 
-// --- Placeholder for WebSocket/SSE ---
-// func (h *APIHandler) ScanStreamHandler(w http.ResponseWriter, r *http.Request) {
-//     // Implementation for real-time updates would go here
-//     // Needs WebSocket or SSE library/logic
-//     http.Error(w, "Streaming Not Implemented", http.StatusNotImplemented)
-// }
+// CancelJobHandler stops a running or pending job. Results collected so far
+// are kept and served by the result endpoint; a job that has already
+// finished answers 409.
+// POST /scan/cancel/{id}
+func (h *APIHandler) CancelJobHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	jobID := strings.TrimPrefix(r.URL.Path, "/scan/cancel/")
+	if jobID == "" || strings.Contains(jobID, "/") {
+		http.Error(w, "Invalid or missing Job ID in URL path", http.StatusBadRequest)
+		return
+	}
+
+	status, err := h.Manager.CancelJob(jobID)
+	switch {
+	case errors.Is(err, errJobNotFound):
+		http.NotFound(w, r)
+		return
+	case errors.Is(err, errJobFinished):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	log.Printf("[API Job %s] Cancelled after %d of %d URLs%s", jobID, status.ProcessedURLs, status.TotalURLs, byKey(r))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+// validTiers are the result tiers accepted by ?tier=.
+var validTiers = map[string]bool{"vulnerable": true, "interesting": true, "safe": true, "error": true}
+
+// resultTier classifies a result as vulnerable, interesting (a lead worth a
+// manual look), safe or error.
+func resultTier(result types.ScanResult) string {
+	switch {
+	case result.Error != "":
+		return "error"
+	case result.IsVulnerable:
+		return "vulnerable"
+	case len(result.Interesting) > 0:
+		return "interesting"
+	default:
+		return "safe"
+	}
+}
+
+// ScanJobHandler serves per-job sub-resources under /scan/{id}/.
+// POST /scan/{id}/targets
+func (h *APIHandler) ScanJobHandler(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/scan/"), "/"), "/")
+	if len(parts) != 2 || parts[0] == "" {
+		http.NotFound(w, r)
+		return
+	}
+	jobID, action := parts[0], parts[1]
+
+	switch action {
+	case "targets":
+		h.addTargets(w, r, jobID)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// addTargets appends URLs to a running job's queue.
+// Body: {"urls": ["http://...", "https://..."]}
+func (h *APIHandler) addTargets(w http.ResponseWriter, r *http.Request, jobID string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var requestBody struct {
+		URLs []string `json:"urls"`
+	}
+	if !h.decodeBody(w, r, &requestBody, false) {
+		return
+	}
+
+	validURLs := validateURLs(requestBody.URLs, false)
+	if len(validURLs) == 0 {
+		http.Error(w, "No valid URLs provided in the list", http.StatusBadRequest)
+		return
+	}
+
+	added, total, err := h.Manager.AddTargets(jobID, validURLs)
+	switch {
+	case errors.Is(err, errJobNotFound):
+		http.NotFound(w, r)
+		return
+	case errors.Is(err, errJobNotAccepting):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case errors.Is(err, errURLQuotaExceeded):
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	log.Printf("[API Job %s] Added %d targets (total %d)%s", jobID, added, total, byKey(r))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(types.AddTargetsResponse{JobID: jobID, Added: added, TotalURLs: total})
+}
+
+// ScanLogsHandler returns the log lines of a job as plain text. With
+// ?follow=true the response stays open and streams new lines until the job ends.
+// GET /scan/logs/{id}
+func (h *APIHandler) ScanLogsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	jobID := strings.TrimPrefix(r.URL.Path, "/scan/logs/")
+	if jobID == "" || strings.Contains(jobID, "/") {
+		http.Error(w, "Invalid or missing Job ID in URL path", http.StatusBadRequest)
+		return
+	}
+	jobLog, err := h.Manager.GetJobLog(jobID)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	follow := r.URL.Query().Get("follow") == "true"
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	rc := http.NewResponseController(w)
+	if follow {
+		// Following outlives the server's write timeout
+		_ = rc.SetWriteDeadline(time.Time{})
+	}
+
+	seq := 0
+	for {
+		lines, next, closed, changed := jobLog.Since(seq)
+		seq = next
+		for _, line := range lines {
+			if _, err := io.WriteString(w, line+"\n"); err != nil {
+				return
+			}
+		}
+		if !follow || closed {
+			return
+		}
+		_ = rc.Flush()
+
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// optionError describes an invalid scan option by its request field, e.g.
+// "Invalid retries: must be between 0 and 10, got 20".
+func optionError(err error) string {
+	var flagErr *config.FlagError
+	if errors.As(err, &flagErr) && flagErr.Field != "" {
+		return "Invalid " + flagErr.Field + ": " + flagErr.Err.Error()
+	}
+	return err.Error()
+}
+
+// overURLQuota answers 413 and returns true when n URLs are more than one
+// job may scan (--max-job-urls).
+func (h *APIHandler) overURLQuota(w http.ResponseWriter, n int) bool {
+	if h.Manager.MaxJobURLs > 0 && n > h.Manager.MaxJobURLs {
+		http.Error(w, fmt.Sprintf("Too many URLs for one job (%d > %d)", n, h.Manager.MaxJobURLs), http.StatusRequestEntityTooLarge)
+		return true
+	}
+	return false
+}
+
+// validateURLs trims the given URLs and keeps only http(s) ones, and bare
+// hosts (utils.IsBareHost) if bareHosts is set; the caller probes their scheme.
+func validateURLs(urls []string, bareHosts bool) []string {
+	validURLs := []string{}
+	for _, u := range urls {
+		trimmed := strings.TrimSpace(u)
+		if trimmed != "" && (strings.HasPrefix(trimmed, "http://") || strings.HasPrefix(trimmed, "https://") || bareHosts && utils.IsBareHost(trimmed)) {
+			validURLs = append(validURLs, trimmed)
+		} else {
+			log.Printf("[API] Skipping invalid URL format from request: %s", u)
+		}
+	}
+	return validURLs
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// HealthStatus is returned by /healthz and /readyz.
+type HealthStatus struct {
+	Status        string    `json:"status"`      // "ok", "ready" or "shutting down"
+	ActiveJobs    int       `json:"active_jobs"` // Pending and running jobs
+	UptimeSeconds float64   `json:"uptime_seconds"`
+	Started       time.Time `json:"started"`
+}
+
+// probePath reports whether path is a health probe, which load balancers and
+// Kubernetes call without an API key or a rate limit.
+func probePath(path string) bool {
+	return path == "/healthz" || path == "/readyz"
+}
+
+// health reports the manager's active jobs and uptime under status.
+func (m *ScanManager) health(status string) HealthStatus {
+	m.mu.RLock()
+	active := 0
+	for _, job := range m.jobs {
+		if !jobFinished(job.Status) {
+			active++
+		}
+	}
+	m.mu.RUnlock()
+	return HealthStatus{
+		Status:        status,
+		ActiveJobs:    active,
+		UptimeSeconds: time.Since(m.started).Seconds(),
+		Started:       m.started,
+	}
+}
+
+// HealthzHandler answers 200 while the server process is up (liveness).
+// GET /healthz
+func (h *APIHandler) HealthzHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeHealth(w, http.StatusOK, h.Manager.health("ok"))
+}
+
+// ReadyzHandler answers 200 while the server accepts scans, and 503 once it
+// is shutting down so load balancers stop sending it work (readiness).
+// GET /readyz
+func (h *APIHandler) ReadyzHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.Manager.ctx.Err() != nil {
+		writeHealth(w, http.StatusServiceUnavailable, h.Manager.health("shutting down"))
+		return
+	}
+	writeHealth(w, http.StatusOK, h.Manager.health("ready"))
+}
+
+func writeHealth(w http.ResponseWriter, code int, status HealthStatus) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(status)
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"log"
+	"time"
+)
+
+// maxJanitorInterval is the longest the janitor sleeps between sweeps.
+const maxJanitorInterval = time.Minute
+
+// EvictExpired deletes the jobs that finished more than JobTTL before now,
+// exporting them to the sinks first, and returns how many were evicted.
+func (m *ScanManager) EvictExpired(now time.Time) int {
+	if m.JobTTL <= 0 {
+		return 0
+	}
+	m.mu.RLock()
+	var expired []string
+	for id, job := range m.jobs {
+		if jobFinished(job.Status) && job.EndTime != nil && now.Sub(*job.EndTime) > m.JobTTL {
+			expired = append(expired, id)
+		}
+	}
+	m.mu.RUnlock()
+
+	evicted := 0
+	for _, id := range expired {
+		if m.DeleteJob(id) == nil {
+			evicted++
+		}
+	}
+	return evicted
+}
+
+// evictPeriodically runs the job janitor (--api-job-ttl) until stop is closed.
+func evictPeriodically(m *ScanManager, stop <-chan struct{}) {
+	interval := m.JobTTL
+	if interval > maxJanitorInterval {
+		interval = maxJanitorInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			if n := m.EvictExpired(now); n > 0 {
+				log.Printf("[API] Evicted %d jobs finished more than %s ago", n, m.JobTTL)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"bytes"
+	"sync"
+)
+
+// defaultJobLogLines is the number of log lines kept per job.
+const defaultJobLogLines = 1000
+
+// JobLog is a bounded ring buffer of a job's log lines. It implements
+// io.Writer so it can back a *log.Logger; readers can follow new lines.
+type JobLog struct {
+	mu      sync.Mutex
+	lines   []string
+	start   int // Index of the oldest line in lines
+	total   int // Lines ever written; the sequence number of the next line
+	partial []byte
+	closed  bool
+	changed chan struct{} // Closed and replaced on every write/close
+}
+
+// NewJobLog creates a log that keeps the last capacity lines.
+func NewJobLog(capacity int) *JobLog {
+	if capacity <= 0 {
+		capacity = defaultJobLogLines
+	}
+	return &JobLog{lines: make([]string, 0, capacity), changed: make(chan struct{})}
+}
+
+// Write appends complete lines from p; a trailing partial line is kept until its newline arrives.
+func (l *JobLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	data := append(l.partial, p...)
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			break
+		}
+		l.appendLine(string(data[:i]))
+		data = data[i+1:]
+	}
+	l.partial = append([]byte(nil), data...)
+	l.notify()
+	return len(p), nil
+}
+
+// appendLine stores a line, overwriting the oldest once full. Caller holds mu.
+func (l *JobLog) appendLine(line string) {
+	if len(l.lines) < cap(l.lines) {
+		l.lines = append(l.lines, line)
+	} else {
+		l.lines[l.start] = line
+		l.start = (l.start + 1) % len(l.lines)
+	}
+	l.total++
+}
+
+// notify wakes followers. Caller holds mu.
+func (l *JobLog) notify() {
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
+
+// Close marks the log as finished (the job ended); followers stop after the last line.
+func (l *JobLog) Close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return
+	}
+	if len(l.partial) > 0 {
+		l.appendLine(string(l.partial))
+		l.partial = nil
+	}
+	l.closed = true
+	l.notify()
+}
+
+// Since returns the buffered lines with sequence number >= seq (older lines
+// that were overwritten are skipped), the sequence number to ask for next,
+// whether the log is closed, and a channel closed on the next change.
+func (l *JobLog) Since(seq int) ([]string, int, bool, <-chan struct{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	oldest := l.total - len(l.lines)
+	if seq < oldest {
+		seq = oldest
+	}
+	var out []string
+	for s := seq; s < l.total; s++ {
+		out = append(out, l.lines[(l.start+s-oldest)%len(l.lines)])
+	}
+	return out, l.total, l.closed, l.changed
+}
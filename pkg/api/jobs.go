@@ -0,0 +1,107 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+// Page sizes of GET /scan/jobs.
+const (
+	defaultJobsPerPage = 50
+	maxJobsPerPage     = 500
+)
+
+// jobStates are the job statuses accepted by ?status=.
+var jobStates = []string{"Pending", "Queued", "Running", "Completed", "Error", "Cancelled"}
+
+// ListJobsHandler lists job summaries (status without results), oldest
+// first, optionally only those in one state.
+// GET /scan/jobs[?status=Running][&page=1][&per_page=50]
+func (h *APIHandler) ListJobsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	q := r.URL.Query()
+
+	status := ""
+	if s := q.Get("status"); s != "" {
+		for _, state := range jobStates {
+			if strings.EqualFold(s, state) {
+				status = state
+			}
+		}
+		if status == "" {
+			http.Error(w, "status must be one of "+strings.Join(jobStates, ", "), http.StatusBadRequest)
+			return
+		}
+	}
+	page, err := queryInt(q.Get("page"), 1)
+	if err != nil || page < 1 {
+		http.Error(w, "page must be a positive number", http.StatusBadRequest)
+		return
+	}
+	perPage, err := queryInt(q.Get("per_page"), defaultJobsPerPage)
+	if err != nil || perPage < 1 || perPage > maxJobsPerPage {
+		http.Error(w, "per_page must be between 1 and "+strconv.Itoa(maxJobsPerPage), http.StatusBadRequest)
+		return
+	}
+
+	jobs := h.Manager.ListJobs(status)
+	list := types.JobList{Jobs: []types.JobStatus{}, Total: len(jobs), Page: page, PerPage: perPage}
+	if start := (page - 1) * perPage; start < len(jobs) {
+		end := start + perPage
+		if end > len(jobs) {
+			end = len(jobs)
+		}
+		list.Jobs = jobs[start:end]
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// DeleteJobHandler removes a finished job and its results from the server,
+// exporting it to --api-export-dir first. Unfinished jobs answer 409.
+// DELETE /scan/job/{id}
+func (h *APIHandler) DeleteJobHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	jobID := strings.TrimPrefix(r.URL.Path, "/scan/job/")
+	if jobID == "" || strings.Contains(jobID, "/") {
+		http.Error(w, "Invalid or missing Job ID in URL path", http.StatusBadRequest)
+		return
+	}
+
+	err := h.Manager.DeleteJob(jobID)
+	switch {
+	case errors.Is(err, errJobNotFound):
+		http.NotFound(w, r)
+		return
+	case errors.Is(err, errJobUnfinished):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	log.Printf("[API Job %s] Deleted%s", jobID, byKey(r))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// queryInt parses an integer query parameter, returning def when it is absent.
+func queryInt(value string, def int) (int, error) {
+	if value == "" {
+		return def, nil
+	}
+	return strconv.Atoi(value)
+}
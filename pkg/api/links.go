@@ -0,0 +1,130 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	errLinkInvalid = errors.New("invalid download link signature")
+	errLinkExpired = errors.New("download link has expired")
+)
+
+// LinkSigner signs expiring result-download links (--api-link-ttl). A link
+// grants access to one job's results until it expires, so it can be shared,
+// e.g. in a completion webhook, without opening the rest of the API.
+type LinkSigner struct {
+	secret  []byte
+	TTL     time.Duration // Lifetime of a new link
+	BaseURL string        // Public URL of the server; "" = taken from the request
+}
+
+// NewLinkSigner returns a signer for links valid for ttl. An empty secret is
+// replaced by a random one, which invalidates the links on restart.
+func NewLinkSigner(secret string, ttl time.Duration, baseURL string) (*LinkSigner, error) {
+	if ttl <= 0 {
+		return nil, fmt.Errorf("link lifetime must be positive, got %s", ttl)
+	}
+	key := []byte(secret)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("generating link secret: %w", err)
+		}
+	}
+	return &LinkSigner{secret: key, TTL: ttl, BaseURL: strings.TrimRight(baseURL, "/")}, nil
+}
+
+// Link returns the signed download URL of a job and when it expires. r is the
+// request the link is returned in, used for the host when BaseURL is unset.
+func (s *LinkSigner) Link(r *http.Request, jobID string, now time.Time) (string, time.Time) {
+	expires := now.Add(s.TTL).Truncate(time.Second)
+	exp := strconv.FormatInt(expires.Unix(), 10)
+	q := url.Values{"expires": {exp}, "sig": {s.sign(jobID, exp)}}
+	return s.base(r) + "/scan/download/" + url.PathEscape(jobID) + "?" + q.Encode(), expires
+}
+
+// Verify checks the expires/sig query parameters of a download link for jobID.
+func (s *LinkSigner) Verify(jobID, expires, sig string, now time.Time) error {
+	if !hmac.Equal([]byte(sig), []byte(s.sign(jobID, expires))) {
+		return errLinkInvalid
+	}
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return errLinkInvalid
+	}
+	if now.After(time.Unix(unix, 0)) {
+		return errLinkExpired
+	}
+	return nil
+}
+
+// sign returns the hex HMAC-SHA256 of a job ID and expiry.
+func (s *LinkSigner) sign(jobID, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(jobID + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// base returns BaseURL, or the scheme and host the request was sent to.
+// Without either (jobs started by a schedule) links are relative.
+func (s *LinkSigner) base(r *http.Request) string {
+	if s.BaseURL != "" || r == nil || r.Host == "" {
+		return s.BaseURL
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// DownloadHandler serves a finished job's results to holders of a signed link.
+// GET /scan/download/{id}?expires=<unix>&sig=<hmac>
+func (h *APIHandler) DownloadHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	jobID := strings.TrimPrefix(r.URL.Path, "/scan/download/")
+	if jobID == "" || strings.Contains(jobID, "/") {
+		http.Error(w, "Invalid or missing Job ID in URL path", http.StatusBadRequest)
+		return
+	}
+	// Check the signature before revealing whether the job exists
+	q := r.URL.Query()
+	if err := h.Manager.Links.Verify(jobID, q.Get("expires"), q.Get("sig"), time.Now()); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	status, err := h.Manager.GetJobStatus(jobID)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	if !jobFinished(status.Status) {
+		http.Error(w, "Job has not finished", http.StatusConflict)
+		return
+	}
+	results, err := h.Manager.GetJobResults(jobID)
+	if err != nil {
+		http.Error(w, "Failed to retrieve results for completed job: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	status.Results = results
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", jobID+".json"))
+	json.NewEncoder(w).Encode(status)
+}
@@ -1,29 +1,184 @@
 package api
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/nxneeraj/hx-hawks/pkg/campaign"
+	"github.com/nxneeraj/hx-hawks/pkg/rules"
+	"github.com/nxneeraj/hx-hawks/pkg/scanner"
 	"github.com/nxneeraj/hx-hawks/pkg/types" 
 )
 
+var (
+	errJobNotFound      = errors.New("job not found")
+	errJobNotAccepting  = errors.New("job is no longer accepting targets")
+	errJobFinished      = errors.New("job has already finished")
+	errJobUnfinished    = errors.New("job has not finished; cancel it first")
+	errURLQuotaExceeded = errors.New("per-job URL quota exceeded")
+	errCampaignNotFound = errors.New("campaign not found")
+	errTemplateNotFound = errors.New("template not found")
+)
+
 // ScanManager manages active and completed scan jobs.
 type ScanManager struct {
-	jobs map[string]*types.JobStatus
-	mu   sync.RWMutex // Protects access to the jobs map
+	jobs   map[string]*types.JobStatus
+	queues map[string]*jobQueue // URL queues of running jobs
+	cancels map[string]context.CancelFunc // Scan context cancel funcs of unfinished jobs
+	changed map[string]chan struct{} // Per job, closed and replaced when a result is added or the status changes
+	logs   map[string]*JobLog   // Per-job log buffers
+	campaigns map[string][]string // Campaign -> job IDs, oldest first
+	templates map[string]*types.JobTemplate // Stored job definitions (POST /scan/templates)
+	targetLists map[string]*targetList // Uploaded URL lists (POST /scan/upload)
+	schedules map[string]*types.JobSchedule // Recurring scans (POST /schedules)
+	callbacks map[string]*callback // Webhooks of unfinished jobs (callback_url)
+	hosts  map[string]map[string]*types.HostSummary // Job -> host -> rollup, updated per result
+	ctx    context.Context // Parent of every job's scan context, cancelled on shutdown
+	started time.Time     // When the server started, for /healthz uptime
+	exported map[string]bool // Jobs already handed to the sinks
+	util   *scanner.Utilization // Worker time across all jobs, for /stats
+	metrics *jobMetrics // Counters since startup, for /metrics
+	resultBytes int64   // Approximate size of the results in memory, for /stats
+	goroutines map[string]int // Live scan goroutines of each job, for /stats
+	running int           // Scans holding a slot (see Schedule)
+	waiting []*queuedScan // "Queued" jobs, in start order (by priority)
+	stored map[string]*storedState // What of each job is in the Store (only with a Store)
+	mu     sync.RWMutex // Protects access to the jobs and queues maps
+	storeMu sync.Mutex  // Orders Store writes, so a deleted job isn't written back
+	storeClosed bool    // Store closed on shutdown; guarded by storeMu
+
+	MaxJobURLs int          // Maximum URLs a single job may scan (0 = unlimited)
+	JobTTL     time.Duration // How long finished jobs are kept before the janitor evicts them (0 = forever)
+	Sinks      []ResultSink // Receive finished jobs on shutdown or deletion
+	Links      *LinkSigner  // Signs result-download links; nil = no links
+	MaxConcurrentScans int  // Scans run at once; later jobs wait as "Queued" (0 = unlimited)
+	Store      JobStore     // Keeps jobs across restarts; nil = memory only
+	CallbackSecret string   // Signs callbacks of jobs without their own callback_secret
 }
 
-// NewScanManager creates a new manager.
-func NewScanManager() *ScanManager {
+// jobQueue is the live URL queue of a running job plus the rules used to
+// expand newly added targets with probe paths.
+type jobQueue struct {
+	queue *scanner.Queue
+	rules []rules.Rule
+}
+
+// NewScanManager creates a new manager whose jobs stop when ctx is cancelled.
+func NewScanManager(ctx context.Context) *ScanManager {
 	return &ScanManager{
-		jobs: make(map[string]*types.JobStatus),
+		ctx:    ctx,
+		started: time.Now().UTC(),
+		jobs:   make(map[string]*types.JobStatus),
+		queues: make(map[string]*jobQueue),
+		cancels: make(map[string]context.CancelFunc),
+		changed: make(map[string]chan struct{}),
+		logs:   make(map[string]*JobLog),
+		exported: make(map[string]bool),
+		util:     scanner.NewUtilization(),
+		metrics:  newJobMetrics(),
+		campaigns: make(map[string][]string),
+		templates: make(map[string]*types.JobTemplate),
+		targetLists: make(map[string]*targetList),
+		schedules:   make(map[string]*types.JobSchedule),
+		callbacks:   make(map[string]*callback),
+		hosts:     make(map[string]map[string]*types.HostSummary),
+		stored:    make(map[string]*storedState),
+		goroutines: make(map[string]int),
+	}
+}
+
+// JobLogger returns a logger that writes to the server log and to the job's
+// own log buffer (see JobLog).
+func (m *ScanManager) JobLogger(jobID string) *log.Logger {
+	m.mu.RLock()
+	jl, ok := m.logs[jobID]
+	m.mu.RUnlock()
+	if !ok {
+		return log.Default()
+	}
+	return log.New(io.MultiWriter(log.Writer(), jl), "", log.LstdFlags)
+}
+
+// GetJobLog returns the log buffer of a job.
+func (m *ScanManager) GetJobLog(jobID string) (*JobLog, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	jl, ok := m.logs[jobID]
+	if !ok {
+		return nil, errJobNotFound
+	}
+	return jl, nil
+}
+
+// AttachQueue registers the URL queue of a running job so targets can be added to it.
+func (m *ScanManager) AttachQueue(jobID string, q *scanner.Queue, jobRules []rules.Rule) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.queues[jobID] = &jobQueue{queue: q, rules: jobRules}
+}
+
+// AddTargets appends URLs to a running job's queue, expanding them with the
+// job's rule probe paths and enforcing MaxJobURLs. It returns the number of
+// URLs queued and the job's new total.
+func (m *ScanManager) AddTargets(jobID string, urls []string) (int, int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, exists := m.jobs[jobID]
+	if !exists {
+		return 0, 0, errJobNotFound
+	}
+	jq, ok := m.queues[jobID]
+	if !ok || (job.Status != "Running" && job.Status != "Pending") {
+		return 0, job.TotalURLs, errJobNotAccepting
+	}
+
+	expanded := rules.ExpandTargets(urls, jq.rules)
+	if m.MaxJobURLs > 0 && job.TotalURLs+len(expanded) > m.MaxJobURLs {
+		return 0, job.TotalURLs, fmt.Errorf("%w: %d queued + %d new > %d", errURLQuotaExceeded, job.TotalURLs, len(expanded), m.MaxJobURLs)
 	}
+	if err := jq.queue.Add(expanded); err != nil {
+		return 0, job.TotalURLs, errJobNotAccepting
+	}
+	job.TotalURLs += len(expanded)
+	return len(expanded), job.TotalURLs, nil
 }
 
-// CreateJob initializes a new scan job.
-func (m *ScanManager) CreateJob(totalURLs int) string {
+// QueueLinks appends links found by the crawl to a running job's queue, as
+// is (no probe-path expansion). Links beyond MaxJobURLs are dropped; it
+// returns the number queued.
+func (m *ScanManager) QueueLinks(jobID string, links []string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, exists := m.jobs[jobID]
+	jq, ok := m.queues[jobID]
+	if !exists || !ok {
+		return 0
+	}
+	if m.MaxJobURLs > 0 && job.TotalURLs+len(links) > m.MaxJobURLs {
+		room := m.MaxJobURLs - job.TotalURLs
+		if room < 0 {
+			room = 0
+		}
+		links = links[:room]
+	}
+	if len(links) == 0 || jq.queue.Add(links) != nil {
+		return 0
+	}
+	job.TotalURLs += len(links)
+	return len(links)
+}
+
+// CreateJob initializes a new scan job that will run with the given number of workers.
+func (m *ScanManager) CreateJob(totalURLs, threads int) string {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -32,14 +187,79 @@ func (m *ScanManager) CreateJob(totalURLs int) string {
 		JobID:          jobID,
 		Status:         "Pending",
 		TotalURLs:      totalURLs,
+		Threads:        threads,
 		ProcessedURLs:  0,
 		VulnerableURLs: 0,
 		StartTime:      time.Now().UTC(),
-		Results:        make([]types.ScanResult, 0, totalURLs), // Pre-allocate slice
+		Results:        make([]types.ScanResult, 0), // Grows as results arrive, however many URLs were asked for
+	}
+	m.logs[jobID] = NewJobLog(defaultJobLogLines)
+	m.changed[jobID] = make(chan struct{})
+	m.metrics.created++
+	if m.Store != nil {
+		m.stored[jobID] = &storedState{}
 	}
 	return jobID
 }
 
+// JobContext returns the scan context of a job, cancelled by CancelJob or
+// when the server shuts down.
+func (m *ScanManager) JobContext(jobID string) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cancels[jobID] = cancel
+	return ctx, cancel
+}
+
+// CancelJob stops an unfinished job: its scan context is cancelled and it is
+// marked "Cancelled". Results collected so far are kept; those of requests
+// still in flight are dropped. Queued jobs are taken off the queue.
+func (m *ScanManager) CancelJob(jobID string) (*types.JobStatus, error) {
+	m.mu.Lock()
+	job, exists := m.jobs[jobID]
+	if !exists {
+		m.mu.Unlock()
+		return nil, errJobNotFound
+	}
+	if jobFinished(job.Status) {
+		m.mu.Unlock()
+		return nil, errJobFinished
+	}
+	job.Status = "Cancelled"
+	now := time.Now().UTC()
+	job.EndTime = &now
+	delete(m.queues, jobID)
+	if cancel, ok := m.cancels[jobID]; ok {
+		cancel()
+		delete(m.cancels, jobID)
+	}
+	if m.unqueue(jobID) {
+		if jl, ok := m.logs[jobID]; ok {
+			jl.Close() // No scan goroutine will end it
+		}
+	}
+	m.notifyJob(jobID)
+	m.metrics.finished[job.Status]++
+	m.finishCallback(jobID)
+	m.mu.Unlock()
+	return m.GetJobStatus(jobID)
+}
+
+// jobFinished reports whether a job status is final.
+func jobFinished(status string) bool {
+	return status == "Completed" || status == "Error" || status == "Cancelled"
+}
+
+// SetInputs records the digest of a job's targets and rules.
+func (m *ScanManager) SetInputs(jobID string, inputs *types.InputDigest) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if job, exists := m.jobs[jobID]; exists {
+		job.Inputs = inputs
+	}
+}
+
 // UpdateJobStatus updates the status fields of a job.
 func (m *ScanManager) UpdateJobStatus(jobID, status string, err error) error {
 	m.mu.Lock()
@@ -47,13 +267,21 @@ func (m *ScanManager) UpdateJobStatus(jobID, status string, err error) error {
 
 	job, exists := m.jobs[jobID]
 	if !exists {
-		return errors.New("job not found")
+		return errJobNotFound
 	}
 
-	// Don't revert status from Completed or Error
-	if job.Status == "Completed" || job.Status == "Error" {
+	// Don't revert status from Completed, Error or Cancelled
+	if job.Status == "Cancelled" && (status == "Completed" || status == "Error") {
+		// The scan goroutine has wound down after CancelJob
+		if jl, ok := m.logs[jobID]; ok {
+			jl.Close()
+		}
+		return nil
+	}
+	if jobFinished(job.Status) {
 		return nil // Or log a warning
 	}
+	defer m.notifyJob(jobID)
 
 
 	job.Status = status
@@ -64,6 +292,13 @@ func (m *ScanManager) UpdateJobStatus(jobID, status string, err error) error {
 	if status == "Completed" || status == "Error" {
 		now := time.Now().UTC()
 		job.EndTime = &now
+		delete(m.queues, jobID) // Finished jobs no longer accept targets
+		delete(m.cancels, jobID)
+		if jl, ok := m.logs[jobID]; ok {
+			jl.Close() // Ends any ?follow=true readers
+		}
+		m.metrics.finished[job.Status]++
+		m.finishCallback(jobID)
 	}
 	return nil
 }
@@ -75,19 +310,35 @@ func (m *ScanManager) AddResult(jobID string, result types.ScanResult) error {
 
 	job, exists := m.jobs[jobID]
 	if !exists {
-		return errors.New("job not found")
+		return errJobNotFound
 	}
 	// Only add results if the job is still considered running or pending
 	if job.Status == "Running" || job.Status == "Pending" {
-		job.Results = append(job.Results, result)
+		defer m.notifyJob(jobID)
 		job.ProcessedURLs++
+		if job.StatusCodes == nil {
+			job.StatusCodes = make(map[string]int)
+		}
+		job.StatusCodes[scanner.StatusKey(result)]++
+		m.rollUpHost(jobID, result)
+		m.metrics.observe(result)
+		if result.Filtered {
+			return nil // Counted as processed, but filtered responses are not stored
+		}
+		job.Results = append(job.Results, result)
+		m.resultBytes += resultSize(&result)
+		m.callbackResult(jobID, len(job.Results), result)
 		if result.IsVulnerable {
 			job.VulnerableURLs++
+		} else if len(result.Interesting) > 0 {
+			job.InterestingURLs++
 		}
 		// Update status to running if it was pending and hasn't hit an error
 		if job.Status == "Pending" && job.Error == "" {
 			job.Status = "Running"
 		}
+	} else if job.Status == "Cancelled" {
+		return nil // Requests still in flight when the job was cancelled
 	} else {
         // Job might be completed or errored out already
         return errors.New("cannot add result to job in status: " + job.Status)
@@ -103,50 +354,362 @@ func (m *ScanManager) GetJobStatus(jobID string) (*types.JobStatus, error) {
 
 	job, exists := m.jobs[jobID]
 	if !exists {
-		return nil, errors.New("job not found")
-	}
-
-	// Return a copy without the full results slice for status checks
-	statusCopy := &types.JobStatus{
-		JobID:          job.JobID,
-		Status:         job.Status,
-		TotalURLs:      job.TotalURLs,
-		ProcessedURLs:  job.ProcessedURLs,
-		VulnerableURLs: job.VulnerableURLs,
-		StartTime:      job.StartTime,
-		EndTime:        job.EndTime,
-		Error:          job.Error,
+		return nil, errJobNotFound
+	}
+	return statusCopy(job), nil
+}
+
+// ListJobs returns the status of every job (without results) whose status
+// is status, or of all jobs when status is empty, oldest first.
+func (m *ScanManager) ListJobs(status string) []types.JobStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	list := make([]types.JobStatus, 0, len(m.jobs))
+	for _, job := range m.jobs {
+		if status == "" || job.Status == status {
+			list = append(list, *statusCopy(job))
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].StartTime.Equal(list[j].StartTime) {
+			return list[i].StartTime.Before(list[j].StartTime)
+		}
+		return list[i].JobID < list[j].JobID
+	})
+	return list
+}
+
+// statusCopy returns a copy of a job's status without the full results
+// slice, for status checks. Callers hold m.mu.
+func statusCopy(job *types.JobStatus) *types.JobStatus {
+	return &types.JobStatus{
+		JobID:           job.JobID,
+		Status:          job.Status,
+		TotalURLs:       job.TotalURLs,
+		Threads:         job.Threads,
+		Campaign:        job.Campaign,
+		Label:           job.Label,
+		Priority:        job.Priority,
+		ProcessedURLs:   job.ProcessedURLs,
+		VulnerableURLs:  job.VulnerableURLs,
+		InterestingURLs: job.InterestingURLs,
+		StatusCodes:     copyCounts(job.StatusCodes),
+		StartTime:       job.StartTime,
+		EndTime:         job.EndTime,
+		Error:           job.Error,
+		Inputs:          job.Inputs,
 		// Results field intentionally omitted
 	}
+}
 
-	return statusCopy, nil
+// copyCounts returns a copy of a status-code histogram so callers can't race with updates.
+func copyCounts(counts map[string]int) map[string]int {
+	if counts == nil {
+		return nil
+	}
+	out := make(map[string]int, len(counts))
+	for k, v := range counts {
+		out[k] = v
+	}
+	return out
 }
 
 // GetJobResults retrieves the full results of a completed job.
 func (m *ScanManager) GetJobResults(jobID string) ([]types.ScanResult, error) {
+	// Optionally check if the job is completed before returning results
+	// if job.Status != "Completed" && job.Status != "Error" {
+	// 	return nil, errors.New("job not yet completed")
+	// }
+
+	// Returns a copy of the results slice to prevent external modification
+	results, _, _, _, err := m.ResultsSince(jobID, 0)
+	if err != nil {
+		return nil, err
+	}
+	if results == nil {
+		results = []types.ScanResult{}
+	}
+	return results, nil
+}
+
+// DeleteJob removes a finished job and its results. It is exported to the
+// sinks first.
+func (m *ScanManager) DeleteJob(jobID string) error {
 	m.mu.RLock()
+	job, exists := m.jobs[jobID]
+	finished := exists && jobFinished(job.Status)
+	m.mu.RUnlock()
+	if !exists {
+		return errJobNotFound
+	}
+	if !finished {
+		return errJobUnfinished
+	}
+
+	m.exportJob(context.Background(), jobID)
+	m.mu.Lock()
+	if job, ok := m.jobs[jobID]; ok {
+		m.dropResults(job)
+	}
+	delete(m.jobs, jobID)
+	delete(m.exported, jobID)
+	delete(m.queues, jobID)
+	delete(m.hosts, jobID)
+	if cancel, ok := m.cancels[jobID]; ok {
+		cancel() // Releases the scan context
+		delete(m.cancels, jobID)
+	}
+	if job != nil && job.Campaign != "" {
+		m.removeFromCampaign(job.Campaign, jobID)
+	}
+	if jl, ok := m.logs[jobID]; ok {
+		jl.Close()
+		delete(m.logs, jobID)
+	}
+	m.notifyJob(jobID) // Ends streams of the job
+	delete(m.changed, jobID)
+	_, stored := m.stored[jobID]
+	delete(m.stored, jobID)
+	m.mu.Unlock()
+
+	if stored {
+		m.storeMu.Lock()
+		defer m.storeMu.Unlock()
+		if err := m.Store.DeleteJob(jobID); err != nil {
+			log.Printf("[API] Deleting job %s from %v failed: %v", jobID, m.Store, err)
+		}
+	}
+	return nil
+}
+
+// ResultsSince returns the results of a job from index from on, the index
+// to ask for next, the job's status (without results) and a channel closed
+// on the job's next change: a result added, a status change or deletion.
+// Results released to the Store are read from it.
+func (m *ScanManager) ResultsSince(jobID string, from int) ([]types.ScanResult, int, *types.JobStatus, <-chan struct{}, error) {
+	m.mu.RLock()
+	job, exists := m.jobs[jobID]
+	if !exists {
+		m.mu.RUnlock()
+		return nil, 0, nil, nil, errJobNotFound
+	}
+	status, changed := statusCopy(job), m.changed[jobID]
+	if from < 0 {
+		from = 0
+	}
+	if st, ok := m.stored[jobID]; ok && st.released {
+		// Finished: what's stored won't change, so read it without the lock
+		total := st.results
+		m.mu.RUnlock()
+		if from >= total {
+			return nil, total, status, changed, nil
+		}
+		results, err := m.Store.LoadResults(jobID, from)
+		if err != nil {
+			return nil, 0, nil, nil, fmt.Errorf("loading results: %w", err)
+		}
+		return results, from + len(results), status, changed, nil
+	}
 	defer m.mu.RUnlock()
+	var results []types.ScanResult
+	if from < len(job.Results) {
+		results = append(results, job.Results[from:]...)
+	} else {
+		from = len(job.Results)
+	}
+	return results, from + len(results), status, changed, nil
+}
+
+// notifyJob wakes the readers waiting on a job's changes. Callers hold m.mu.
+func (m *ScanManager) notifyJob(jobID string) {
+	if ch, ok := m.changed[jobID]; ok {
+		close(ch)
+		m.changed[jobID] = make(chan struct{})
+	}
+}
 
+// AssignCampaign records a job as a run of a campaign.
+func (m *ScanManager) AssignCampaign(jobID, name, label string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	job, exists := m.jobs[jobID]
 	if !exists {
-		return nil, errors.New("job not found")
+		return errJobNotFound
 	}
+	job.Campaign, job.Label = name, label
+	m.campaigns[name] = append(m.campaigns[name], jobID)
+	return nil
+}
 
-	// Optionally check if the job is completed before returning results
-	// if job.Status != "Completed" && job.Status != "Error" {
-	// 	return nil, errors.New("job not yet completed")
-	// }
+// ListCampaigns returns every campaign with its job IDs.
+func (m *ScanManager) ListCampaigns() []types.CampaignInfo {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make([]types.CampaignInfo, 0, len(m.campaigns))
+	for name, jobs := range m.campaigns {
+		out = append(out, types.CampaignInfo{Campaign: name, Jobs: append([]string{}, jobs...)})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Campaign < out[j].Campaign })
+	return out
+}
 
-    // Return a copy of the results slice to prevent external modification
-    resultsCopy := make([]types.ScanResult, len(job.Results))
-    copy(resultsCopy, job.Results)
+// CampaignRuns returns the finished jobs of a campaign as runs, with their
+// results (results[i] belongs to runs[i]). Jobs still running are skipped.
+func (m *ScanManager) CampaignRuns(name string) ([]campaign.Run, [][]types.ScanResult, error) {
+	m.mu.RLock()
+	jobIDs, ok := m.campaigns[name]
+	jobIDs = append([]string(nil), jobIDs...)
+	m.mu.RUnlock()
+	if !ok {
+		return nil, nil, errCampaignNotFound
+	}
+	var runs []campaign.Run
+	var results [][]types.ScanResult
+	for _, id := range jobIDs {
+		jobResults, _, job, _, err := m.ResultsSince(id, 0)
+		if err != nil || !jobFinished(job.Status) {
+			continue // Deleted meanwhile, unreadable, or still running
+		}
+		runs = append(runs, campaign.NewRun(job.JobID, job.Label, job.StartTime, jobResults))
+		results = append(results, jobResults)
+	}
+	return runs, results, nil
+}
 
-	return resultsCopy, nil
+// removeFromCampaign drops a deleted job from its campaign. Callers hold m.mu.
+func (m *ScanManager) removeFromCampaign(name, jobID string) {
+	jobs := m.campaigns[name]
+	for i, id := range jobs {
+		if id == jobID {
+			jobs = append(jobs[:i], jobs[i+1:]...)
+			break
+		}
+	}
+	if len(jobs) == 0 {
+		delete(m.campaigns, name)
+		return
+	}
+	m.campaigns[name] = jobs
 }
 
-// DeleteJob removes a job (optional cleanup).
-func (m *ScanManager) DeleteJob(jobID string) {
+// SaveTemplate stores a job template and returns its new ID.
+func (m *ScanManager) SaveTemplate(tmpl types.JobTemplate) string {
+	tmpl.ID = uuid.New().String()
+	tmpl.Created = time.Now()
+	tmpl.Runs = nil
 	m.mu.Lock()
-	defer m.mu.Unlock()
-	delete(m.jobs, jobID)
+	m.templates[tmpl.ID] = &tmpl
+	m.mu.Unlock()
+	m.storeTemplate(&tmpl)
+	return tmpl.ID
+}
+
+// GetTemplate returns a copy of a stored template.
+func (m *ScanManager) GetTemplate(id string) (*types.JobTemplate, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	tmpl, ok := m.templates[id]
+	if !ok {
+		return nil, errTemplateNotFound
+	}
+	tmplCopy := *tmpl
+	tmplCopy.Runs = append([]string(nil), tmpl.Runs...)
+	return &tmplCopy, nil
+}
+
+// ListTemplates returns every stored template, oldest first.
+func (m *ScanManager) ListTemplates() []types.JobTemplate {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	list := make([]types.JobTemplate, 0, len(m.templates))
+	for _, tmpl := range m.templates {
+		tmplCopy := *tmpl
+		tmplCopy.Runs = append([]string(nil), tmpl.Runs...)
+		list = append(list, tmplCopy)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Created.Before(list[j].Created) })
+	return list
+}
+
+// RecordTemplateRun remembers that jobID was started from a template.
+func (m *ScanManager) RecordTemplateRun(id, jobID string) {
+	m.mu.Lock()
+	tmpl, ok := m.templates[id]
+	var tmplCopy types.JobTemplate
+	if ok {
+		tmpl.Runs = append(tmpl.Runs, jobID)
+		tmplCopy = *tmpl
+		tmplCopy.Runs = append([]string(nil), tmpl.Runs...)
+	}
+	m.mu.Unlock()
+	if ok {
+		m.storeTemplate(&tmplCopy)
+	}
+}
+
+// DeleteTemplate removes a template; jobs started from it are kept.
+func (m *ScanManager) DeleteTemplate(id string) {
+	m.mu.Lock()
+	delete(m.templates, id)
+	m.mu.Unlock()
+	if m.Store != nil {
+		if err := m.Store.DeleteTemplate(id); err != nil {
+			log.Printf("[API] Deleting template %s from %v failed: %v", id, m.Store, err)
+		}
+	}
+}
+
+// storeTemplate writes a template to the Store, if there is one.
+func (m *ScanManager) storeTemplate(tmpl *types.JobTemplate) {
+	if m.Store == nil {
+		return
+	}
+	if err := m.Store.SaveTemplate(tmpl); err != nil {
+		log.Printf("[API] Storing template %s in %v failed: %v", tmpl.ID, m.Store, err)
+	}
+}
+
+// rollUpHost counts a result towards its host's summary. Results that were
+// redirected count for the host originally requested. Callers hold m.mu.
+func (m *ScanManager) rollUpHost(jobID string, result types.ScanResult) {
+	host := resultHost(result)
+	hosts := m.hosts[jobID]
+	if hosts == nil {
+		hosts = make(map[string]*types.HostSummary)
+		m.hosts[jobID] = hosts
+	}
+	summary := hosts[host]
+	if summary == nil {
+		summary = &types.HostSummary{Host: host}
+		hosts[host] = summary
+	}
+	summary.Scanned++
+	if result.Error != "" {
+		summary.Errors++
+	}
+	if result.IsVulnerable && !result.Filtered {
+		summary.Vulnerable++
+		summary.Severity = types.MaxSeverity(summary.Severity, result.Severity)
+	}
+}
+
+// GetJobHosts returns the per-host rollup of a job, sorted by host.
+func (m *ScanManager) GetJobHosts(jobID string) ([]types.HostSummary, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if _, ok := m.jobs[jobID]; !ok {
+		return nil, errJobNotFound
+	}
+	return m.hostList(jobID), nil
+}
+
+// hostList returns a copy of a job's host rollup, sorted by host. Callers
+// hold m.mu.
+func (m *ScanManager) hostList(jobID string) []types.HostSummary {
+	list := make([]types.HostSummary, 0, len(m.hosts[jobID]))
+	for _, summary := range m.hosts[jobID] {
+		list = append(list, *summary)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Host < list[j].Host })
+	return list
 }
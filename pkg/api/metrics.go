@@ -0,0 +1,137 @@
+package api
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+// requestDurationBuckets are the upper bounds, in seconds, of the
+// hawks_request_duration_seconds histogram.
+var requestDurationBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// jobMetrics counts scan activity since the server started, for /metrics.
+// Guarded by the manager's mu.
+type jobMetrics struct {
+	created     int
+	finished    map[string]int // By final status
+	scanned     int
+	vulnerable  int
+	durations   []int // Per bucket of requestDurationBuckets (not cumulative), then +Inf
+	durationSum float64
+}
+
+func newJobMetrics() *jobMetrics {
+	return &jobMetrics{
+		finished:  make(map[string]int),
+		durations: make([]int, len(requestDurationBuckets)+1),
+	}
+}
+
+// observe counts a scanned URL.
+func (jm *jobMetrics) observe(result types.ScanResult) {
+	jm.scanned++
+	if result.IsVulnerable {
+		jm.vulnerable++
+	}
+	i := sort.SearchFloat64s(requestDurationBuckets, result.RequestDuration)
+	jm.durations[i]++
+	jm.durationSum += result.RequestDuration
+}
+
+// copy returns a snapshot of the counters.
+func (jm *jobMetrics) copy() *jobMetrics {
+	c := *jm
+	c.finished = copyCounts(jm.finished)
+	c.durations = append([]int(nil), jm.durations...)
+	return &c
+}
+
+// MetricsHandler exposes the manager's counters and stats in the Prometheus
+// text format.
+// GET /metrics
+func (h *APIHandler) MetricsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	h.Manager.mu.RLock()
+	jm := h.Manager.metrics.copy()
+	h.Manager.mu.RUnlock()
+	stats := h.Manager.Stats()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	pw := promWriter{bufio.NewWriter(w)}
+	pw.metric("hawks_jobs_created_total", "counter", "Scan jobs created since the server started.")
+	pw.sample("hawks_jobs_created_total", "", float64(jm.created))
+	pw.metric("hawks_jobs_finished_total", "counter", "Scan jobs finished since the server started, by final status.")
+	for _, status := range []string{"Completed", "Error", "Cancelled"} {
+		pw.sample("hawks_jobs_finished_total", label("status", status), float64(jm.finished[status]))
+	}
+	pw.metric("hawks_jobs", "gauge", "Jobs held by the server, by state.")
+	for _, state := range sortedKeys(stats.JobsByState) {
+		pw.sample("hawks_jobs", label("state", state), float64(stats.JobsByState[state]))
+	}
+	pw.metric("hawks_urls_scanned_total", "counter", "URLs scanned by API jobs, including failed and filtered requests.")
+	pw.sample("hawks_urls_scanned_total", "", float64(jm.scanned))
+	pw.metric("hawks_vulnerable_results_total", "counter", "Results marked vulnerable.")
+	pw.sample("hawks_vulnerable_results_total", "", float64(jm.vulnerable))
+
+	pw.metric("hawks_request_duration_seconds", "histogram", "Duration of scan requests.")
+	cumulative := 0
+	for i, le := range requestDurationBuckets {
+		cumulative += jm.durations[i]
+		pw.sample("hawks_request_duration_seconds_bucket", label("le", strconv.FormatFloat(le, 'g', -1, 64)), float64(cumulative))
+	}
+	cumulative += jm.durations[len(requestDurationBuckets)]
+	pw.sample("hawks_request_duration_seconds_bucket", label("le", "+Inf"), float64(cumulative))
+	pw.sample("hawks_request_duration_seconds_sum", "", jm.durationSum)
+	pw.sample("hawks_request_duration_seconds_count", "", float64(cumulative))
+
+	pw.metric("hawks_worker_seconds_total", "counter", "Worker time across all jobs, by phase.")
+	pw.sample("hawks_worker_seconds_total", label("phase", "network"), stats.Workers.NetworkSeconds)
+	pw.sample("hawks_worker_seconds_total", label("phase", "matching"), stats.Workers.MatchingSeconds)
+	pw.sample("hawks_worker_seconds_total", label("phase", "sending"), stats.Workers.SendingSeconds)
+	pw.sample("hawks_worker_seconds_total", label("phase", "delay"), stats.Workers.DelaySeconds)
+	pw.metric("hawks_results_in_memory", "gauge", "Results held in memory.")
+	pw.sample("hawks_results_in_memory", "", float64(stats.ResultsInMemory))
+	pw.metric("go_goroutines", "gauge", "Number of goroutines that currently exist.")
+	pw.sample("go_goroutines", "", float64(stats.Goroutines))
+	pw.metric("go_memstats_heap_alloc_bytes", "gauge", "Number of heap bytes allocated and still in use.")
+	pw.sample("go_memstats_heap_alloc_bytes", "", float64(stats.HeapAllocBytes))
+	pw.Flush()
+}
+
+// promWriter writes the Prometheus text exposition format.
+type promWriter struct {
+	*bufio.Writer
+}
+
+// metric starts a metric family.
+func (pw promWriter) metric(name, kind, help string) {
+	fmt.Fprintf(pw, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// sample writes one sample; labels is "" or built by label.
+func (pw promWriter) sample(name, labels string, value float64) {
+	fmt.Fprintf(pw, "%s%s %s\n", name, labels, strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+// label formats a single {name="value"} label set.
+func label(name, value string) string {
+	return "{" + name + "=" + strconv.Quote(value) + "}"
+}
+
+// sortedKeys returns the keys of counts in order, so scrapes are stable.
+func sortedKeys(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
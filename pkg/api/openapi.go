@@ -0,0 +1,299 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nxneeraj/hx-hawks/pkg/campaign"
+	"github.com/nxneeraj/hx-hawks/pkg/diff"
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+// apiOperation documents one route for /openapi.json. Request and response
+// schemas are derived from the Go types the handlers decode and encode, so
+// they follow every field added to them.
+type apiOperation struct {
+	Method      string
+	Path        string
+	Summary     string
+	Params      []apiParam
+	Body        interface{} // JSON request body (zero value of its type); nil = none
+	Status      int         // Success status (default 200)
+	Response    interface{} // JSON response (zero value of its type); nil = ContentType or no body
+	ContentType string      // Non-JSON response, e.g. text/plain
+	Public      bool        // Served without an API key
+}
+
+// apiParam is a path or query parameter.
+type apiParam struct {
+	Name        string
+	In          string // "path" or "query"
+	Type        string // JSON schema type (default string)
+	Description string
+}
+
+var (
+	jobIDParam  = apiParam{Name: "id", In: "path", Description: "Job ID"}
+	jobIDResult = struct {
+		JobID string `json:"job_id"`
+	}{}
+)
+
+// apiOperations lists the routes registered in StartServer.
+var apiOperations = []apiOperation{
+	{Method: "POST", Path: "/scan/start", Summary: "Start a scan job", Body: types.ScanRequest{}, Status: http.StatusAccepted, Response: jobIDResult},
+	{Method: "POST", Path: "/scan/upload", Summary: `Upload a target list as the "file" part of a multipart form, for target_list`, ContentType: "multipart/form-data", Status: http.StatusCreated, Response: types.TargetListInfo{}},
+	{Method: "GET", Path: "/scan/status/{id}", Summary: "Job progress without results", Params: []apiParam{jobIDParam,
+		{Name: "hosts", In: "query", Type: "boolean", Description: "Add the per-host rollup"}}, Response: types.JobStatus{}},
+	{Method: "GET", Path: "/scan/result/{id}", Summary: "Job status with its results (202 with the results so far, partial, while running)", Params: []apiParam{jobIDParam,
+		{Name: "tier", In: "query", Description: "vulnerable, interesting, safe or error"},
+		{Name: "vulnerable", In: "query", Type: "boolean", Description: "Only vulnerable (true) or non-vulnerable (false) results"},
+		{Name: "host", In: "query", Description: "Only results for this host (with or without port)"},
+		{Name: "offset", In: "query", Type: "integer", Description: "Matching results to skip"},
+		{Name: "limit", In: "query", Type: "integer", Description: "Matching results to return (default all, at most 10000)"},
+		{Name: "format", In: "query", Description: "json (default), csv, txt, html, jsonl, md or junit"},
+		{Name: "fields", In: "query", Description: "Comma-separated CSV columns / JSONL keys"}}, Response: types.JobStatus{}},
+	{Method: "GET", Path: "/scan/logs/{id}", Summary: "Job log lines", Params: []apiParam{jobIDParam,
+		{Name: "follow", In: "query", Type: "boolean", Description: "Stream new lines until the job ends"}}, ContentType: "text/plain"},
+	{Method: "POST", Path: "/scan/cancel/{id}", Summary: "Cancel an unfinished job, keeping its results", Params: []apiParam{jobIDParam}, Response: types.JobStatus{}},
+	{Method: "GET", Path: "/scan/jobs", Summary: "List jobs, oldest first", Params: []apiParam{
+		{Name: "status", In: "query", Description: "Pending, Queued, Running, Completed, Error or Cancelled"},
+		{Name: "page", In: "query", Type: "integer"},
+		{Name: "per_page", In: "query", Type: "integer", Description: "Default 50, at most 500"}}, Response: types.JobList{}},
+	{Method: "DELETE", Path: "/scan/job/{id}", Summary: "Delete a finished job and its results", Params: []apiParam{jobIDParam}, Status: http.StatusNoContent},
+	{Method: "POST", Path: "/scan/{id}/targets", Summary: "Add URLs to a running job", Params: []apiParam{jobIDParam}, Body: struct {
+		URLs []string `json:"urls"`
+	}{}, Response: types.AddTargetsResponse{}},
+	{Method: "GET", Path: "/scan/download/{id}", Summary: "Results of a finished job through a signed link (--api-link-ttl)", Params: []apiParam{jobIDParam,
+		{Name: "expires", In: "query", Type: "integer"}, {Name: "sig", In: "query"}}, Response: types.JobStatus{}, Public: true},
+	{Method: "GET", Path: "/scan/stream/{id}", Summary: "Results as Server-Sent Events", Params: []apiParam{jobIDParam}, ContentType: "text/event-stream"},
+	{Method: "GET", Path: "/ws/scan/{id}", Summary: "WebSocket of WSMessage updates; send WSCommand to cancel", Params: []apiParam{jobIDParam}, Status: http.StatusSwitchingProtocols, Response: types.WSMessage{}},
+	{Method: "GET", Path: "/scan/templates", Summary: "List job templates", Response: []*types.JobTemplate{}},
+	{Method: "POST", Path: "/scan/templates", Summary: "Store a job template", Body: types.JobTemplate{}, Status: http.StatusCreated, Response: struct {
+		TemplateID string `json:"template_id"`
+	}{}},
+	{Method: "GET", Path: "/scan/templates/{id}", Summary: "Show a job template", Params: []apiParam{{Name: "id", In: "path"}}, Response: types.JobTemplate{}},
+	{Method: "DELETE", Path: "/scan/templates/{id}", Summary: "Delete a job template", Params: []apiParam{{Name: "id", In: "path"}}, Status: http.StatusNoContent},
+	{Method: "POST", Path: "/scan/templates/{id}/run", Summary: "Start a job from a template; body fields override the stored request", Params: []apiParam{{Name: "id", In: "path"}},
+		Body: types.ScanRequest{}, Status: http.StatusAccepted, Response: jobIDResult},
+	{Method: "GET", Path: "/schedules", Summary: "List recurring scans", Response: []types.JobSchedule{}},
+	{Method: "POST", Path: "/schedules", Summary: "Register a recurring scan of a template or request", Body: types.JobSchedule{}, Status: http.StatusCreated, Response: types.JobSchedule{}},
+	{Method: "GET", Path: "/schedules/{id}", Summary: "Show a schedule with its last and next run", Params: []apiParam{{Name: "id", In: "path"}}, Response: types.JobSchedule{}},
+	{Method: "DELETE", Path: "/schedules/{id}", Summary: "Delete a schedule, keeping its jobs", Params: []apiParam{{Name: "id", In: "path"}}, Status: http.StatusNoContent},
+	{Method: "GET", Path: "/campaigns", Summary: "List campaigns", Response: []types.CampaignInfo{}},
+	{Method: "GET", Path: "/campaigns/{id}", Summary: "Combined campaign report", Params: []apiParam{{Name: "id", In: "path"}}, Response: campaign.Summary{}},
+	{Method: "GET", Path: "/campaigns/{id}/diff", Summary: "Compare two runs of a campaign (default: the last two)", Params: []apiParam{{Name: "id", In: "path"},
+		{Name: "from", In: "query", Description: "Job ID"}, {Name: "to", In: "query", Description: "Job ID"}}, Response: diff.Report{}},
+	{Method: "GET", Path: "/stats", Summary: "Manager metrics", Response: ManagerStats{}},
+	{Method: "GET", Path: "/metrics", Summary: "Prometheus metrics", ContentType: "text/plain"},
+	{Method: "GET", Path: "/healthz", Summary: "Liveness probe", Response: HealthStatus{}, Public: true},
+	{Method: "GET", Path: "/readyz", Summary: "Readiness probe (503 while shutting down)", Response: HealthStatus{}, Public: true},
+}
+
+var (
+	openAPIOnce sync.Once
+	openAPIDoc  []byte
+)
+
+// docsPath reports whether path serves the API description, which is
+// public like the probes.
+func docsPath(path string) bool {
+	return path == "/openapi.json" || path == "/docs"
+}
+
+// OpenAPIHandler serves the OpenAPI 3 document of the API.
+// GET /openapi.json
+func (h *APIHandler) OpenAPIHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	openAPIOnce.Do(func() {
+		openAPIDoc, _ = json.MarshalIndent(openAPISpec(apiOperations), "", "  ")
+	})
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openAPIDoc)
+}
+
+// swaggerPage is the /docs page: Swagger UI (loaded from a CDN) pointed at
+// /openapi.json.
+const swaggerPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Hx-H.A.W.K.S API</title>
+<link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+<script>
+window.ui = SwaggerUIBundle({ url: "openapi.json", dom_id: "#swagger-ui" });
+</script>
+</body>
+</html>
+`
+
+// DocsHandler serves Swagger UI for the OpenAPI document.
+// GET /docs
+func (h *APIHandler) DocsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(swaggerPage))
+}
+
+// openAPISpec builds the OpenAPI document of ops.
+func openAPISpec(ops []apiOperation) map[string]interface{} {
+	schemas := make(map[string]interface{})
+	paths := make(map[string]map[string]interface{})
+	for _, op := range ops {
+		operation := map[string]interface{}{"summary": op.Summary}
+		var params []interface{}
+		for _, p := range op.Params {
+			typ := p.Type
+			if typ == "" {
+				typ = "string"
+			}
+			param := map[string]interface{}{"name": p.Name, "in": p.In, "schema": map[string]string{"type": typ}}
+			if p.In == "path" {
+				param["required"] = true
+			}
+			if p.Description != "" {
+				param["description"] = p.Description
+			}
+			params = append(params, param)
+		}
+		if params != nil {
+			operation["parameters"] = params
+		}
+		if op.Body != nil {
+			operation["requestBody"] = map[string]interface{}{
+				"required": true,
+				"content":  map[string]interface{}{"application/json": map[string]interface{}{"schema": schemaOf(reflect.TypeOf(op.Body), schemas)}},
+			}
+		} else if op.ContentType == "multipart/form-data" {
+			operation["requestBody"] = map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{"multipart/form-data": map[string]interface{}{"schema": map[string]interface{}{
+					"type":       "object",
+					"properties": map[string]interface{}{"file": map[string]string{"type": "string", "format": "binary"}},
+				}}},
+			}
+		}
+		status := op.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		response := map[string]interface{}{"description": http.StatusText(status)}
+		switch {
+		case op.Response != nil:
+			response["content"] = map[string]interface{}{"application/json": map[string]interface{}{"schema": schemaOf(reflect.TypeOf(op.Response), schemas)}}
+		case op.ContentType != "" && op.ContentType != "multipart/form-data":
+			response["content"] = map[string]interface{}{op.ContentType: map[string]interface{}{"schema": map[string]string{"type": "string"}}}
+		}
+		operation["responses"] = map[string]interface{}{strconv.Itoa(status): response}
+		if op.Public {
+			operation["security"] = []interface{}{}
+		}
+		if paths[op.Path] == nil {
+			paths[op.Path] = make(map[string]interface{})
+		}
+		paths[op.Path][strings.ToLower(op.Method)] = operation
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]string{
+			"title":       "Hx-H.A.W.K.S API",
+			"version":     "1.0",
+			"description": "Scan jobs, results and campaigns of an hx-hawks --api server.",
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": schemas,
+			"securitySchemes": map[string]interface{}{
+				"bearer": map[string]string{"type": "http", "scheme": "bearer"},
+				"apiKey": map[string]string{"type": "apiKey", "in": "header", "name": "X-API-Key"},
+			},
+		},
+		// Only enforced with --api-key
+		"security": []interface{}{map[string][]string{"bearer": {}}, map[string][]string{"apiKey": {}}},
+	}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaOf returns the JSON schema of t as encoding/json marshals it. Named
+// structs are added to schemas and referenced.
+func schemaOf(t reflect.Type, schemas map[string]interface{}) interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]string{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]string{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]string{"type": "number"}
+	case reflect.String:
+		return map[string]string{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]string{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": schemaOf(t.Elem(), schemas)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schemaOf(t.Elem(), schemas)}
+	case reflect.Struct:
+		if t == timeType {
+			return map[string]string{"type": "string", "format": "date-time"}
+		}
+		if t.Name() == "" {
+			return structSchema(t, schemas)
+		}
+		name := t.Name()
+		if _, seen := schemas[name]; !seen {
+			schemas[name] = nil // Placeholder for recursive types
+			schemas[name] = structSchema(t, schemas)
+		}
+		return map[string]string{"$ref": "#/components/schemas/" + name}
+	default:
+		return map[string]interface{}{} // interface{}: any value
+	}
+}
+
+// structSchema returns the object schema of a struct's JSON fields.
+func structSchema(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+	properties := make(map[string]interface{})
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" || (!f.IsExported() && !f.Anonymous) {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			// Embedded struct: its fields are promoted
+			embedded := structSchema(f.Type, schemas)
+			for k, v := range embedded["properties"].(map[string]interface{}) {
+				properties[k] = v
+			}
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		properties[name] = schemaOf(f.Type, schemas)
+	}
+	return map[string]interface{}{"type": "object", "properties": properties}
+}
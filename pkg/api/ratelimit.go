@@ -0,0 +1,122 @@
+package api
+
+import (
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// rateLimitIdle is how long a client's bucket is kept after it refilled.
+const rateLimitIdle = 10 * time.Minute
+
+// RateLimiter caps how many requests each client may make per minute
+// (--api-rate-limit), with a token bucket per client: the API key's name
+// when keys are required, else the remote IP. A client may burst up to a
+// minute's allowance at once. A nil *RateLimiter lets every request through.
+type RateLimiter struct {
+	perMinute float64
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+// tokenBucket is one client's remaining allowance.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time // When tokens was last refilled
+}
+
+// NewRateLimiter returns a limiter allowing perMinute requests per client,
+// or nil when perMinute is 0.
+func NewRateLimiter(perMinute int) *RateLimiter {
+	if perMinute <= 0 {
+		return nil
+	}
+	return &RateLimiter{perMinute: float64(perMinute), buckets: make(map[string]*tokenBucket)}
+}
+
+// Middleware answers 429 with a Retry-After header to clients over their
+// allowance. It runs after authentication so keys are limited by name
+// (requests failing authentication are limited by IP, see KeyAuth.Limiter);
+// health probes are never limited.
+func (l *RateLimiter) Middleware(next http.Handler) http.Handler {
+	if l == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !probePath(r.URL.Path) && l.limit(w, r) {
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// limit spends one of r's client's tokens, answering 429 and returning true
+// if there are none left. A nil limiter never limits.
+func (l *RateLimiter) limit(w http.ResponseWriter, r *http.Request) bool {
+	if l == nil {
+		return false
+	}
+	client := rateLimitClient(r)
+	wait := l.take(client, time.Now())
+	if wait <= 0 {
+		return false
+	}
+	log.Printf("[API] Rate limited %s %s from %s", r.Method, r.URL.Path, client)
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+	return true
+}
+
+// take spends one of client's tokens, returning 0, or how long until one is
+// available if there are none.
+func (l *RateLimiter) take(client string, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) > time.Minute {
+		l.sweep(now)
+	}
+	b, ok := l.buckets[client]
+	if !ok {
+		b = &tokenBucket{tokens: l.perMinute, last: now}
+		l.buckets[client] = b
+	}
+	b.tokens = math.Min(l.perMinute, b.tokens+now.Sub(b.last).Minutes()*l.perMinute)
+	b.last = now
+	if b.tokens < 1 {
+		return time.Duration((1 - b.tokens) / l.perMinute * float64(time.Minute))
+	}
+	b.tokens--
+	return 0
+}
+
+// sweep forgets clients idle long enough for their bucket to be full again,
+// so the map doesn't grow with every address ever seen. Callers hold l.mu.
+func (l *RateLimiter) sweep(now time.Time) {
+	for client, b := range l.buckets {
+		if now.Sub(b.last) > rateLimitIdle {
+			delete(l.buckets, client)
+		}
+	}
+	l.lastSweep = now
+}
+
+// rateLimitClient identifies who made r: "key <name>" for authenticated
+// requests, else the remote IP (without the port, which changes per
+// connection).
+func rateLimitClient(r *http.Request) string {
+	if name, ok := r.Context().Value(keyNameContextKey{}).(string); ok {
+		return "key " + name
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+// maxResultsLimit is the largest ?limit= of GET /scan/result.
+const maxResultsLimit = 10000
+
+// resultFilter selects a page of a job's results (GET /scan/result).
+type resultFilter struct {
+	tier       string // "" or a validTiers entry
+	vulnerable *bool  // nil = either
+	host       string // "" or a host, with or without port
+	offset     int    // Matching results skipped
+	limit      int    // Matching results returned (0 = all)
+}
+
+// parseResultFilter reads ?tier=, ?vulnerable=, ?host=, ?offset= and ?limit=.
+func parseResultFilter(q url.Values) (resultFilter, error) {
+	f := resultFilter{tier: q.Get("tier"), host: strings.ToLower(q.Get("host"))}
+	if f.tier != "" && !validTiers[f.tier] {
+		return f, errors.New("tier must be vulnerable, interesting, safe or error")
+	}
+	if v := q.Get("vulnerable"); v != "" {
+		vulnerable, err := strconv.ParseBool(v)
+		if err != nil {
+			return f, errors.New("vulnerable must be true or false")
+		}
+		f.vulnerable = &vulnerable
+	}
+	var err error
+	if f.offset, err = queryInt(q.Get("offset"), 0); err != nil || f.offset < 0 {
+		return f, errors.New("offset must be 0 or more")
+	}
+	if f.limit, err = queryInt(q.Get("limit"), 0); err != nil || f.limit < 0 || f.limit > maxResultsLimit {
+		return f, fmt.Errorf("limit must be between 0 (all) and %d", maxResultsLimit)
+	}
+	return f, nil
+}
+
+// matches reports whether a result passes the filter's tier, vulnerable
+// and host conditions.
+func (f resultFilter) matches(result types.ScanResult) bool {
+	if f.tier != "" && resultTier(result) != f.tier {
+		return false
+	}
+	if f.vulnerable != nil && result.IsVulnerable != *f.vulnerable {
+		return false
+	}
+	if f.host != "" {
+		host := strings.ToLower(resultHost(result))
+		if host != f.host && strings.Split(host, ":")[0] != f.host {
+			return false
+		}
+	}
+	return true
+}
+
+// page returns the matching results from f.offset on, at most f.limit of
+// them, and how many results match in all.
+func (f resultFilter) page(results []types.ScanResult) ([]types.ScanResult, int) {
+	kept := make([]types.ScanResult, 0)
+	matched := 0
+	for _, result := range results {
+		if !f.matches(result) {
+			continue
+		}
+		if matched >= f.offset && (f.limit == 0 || len(kept) < f.limit) {
+			kept = append(kept, result)
+		}
+		matched++
+	}
+	return kept, matched
+}
+
+// ResultPage returns the page of a job's results selected by f, how many
+// results match f in all, and the job's status (without results). Only the
+// page is copied out of memory.
+func (m *ScanManager) ResultPage(jobID string, f resultFilter) ([]types.ScanResult, int, *types.JobStatus, error) {
+	m.mu.RLock()
+	job, exists := m.jobs[jobID]
+	if !exists {
+		m.mu.RUnlock()
+		return nil, 0, nil, errJobNotFound
+	}
+	if st, ok := m.stored[jobID]; !ok || !st.released {
+		defer m.mu.RUnlock()
+		results, matched := f.page(job.Results)
+		return results, matched, statusCopy(job), nil
+	}
+	m.mu.RUnlock()
+	// Released to the Store: read back and filter there
+	stored, _, status, _, err := m.ResultsSince(jobID, 0)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	results, matched := f.page(stored)
+	return results, matched, status, nil
+}
+
+// resultHost is the host[:port] a result counts for: the one originally
+// requested if it was redirected.
+func resultHost(result types.ScanResult) string {
+	target := result.URL
+	if len(result.RedirectChain) > 0 {
+		target = result.RedirectChain[0].URL
+	}
+	if u, err := url.Parse(target); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return target
+}
@@ -0,0 +1,77 @@
+package api
+
+// queuedScan is a job waiting for a scan slot.
+type queuedScan struct {
+	jobID    string
+	priority int
+	scan     func()
+}
+
+// Schedule runs a job's scan in the background once fewer than
+// MaxConcurrentScans scans are running; until then the job is "Queued".
+// Queued jobs start by priority, highest first, then in the order they were
+// scheduled.
+func (m *ScanManager) Schedule(jobID string, priority int, scan func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[jobID]
+	if ok {
+		job.Priority = priority
+	}
+	if m.MaxConcurrentScans > 0 && m.running >= m.MaxConcurrentScans {
+		if ok {
+			job.Status = "Queued"
+			m.notifyJob(jobID)
+		}
+		i := len(m.waiting)
+		for i > 0 && m.waiting[i-1].priority < priority {
+			i-- // Ahead of lower-priority jobs, behind equal ones
+		}
+		m.waiting = append(m.waiting, nil)
+		copy(m.waiting[i+1:], m.waiting[i:])
+		m.waiting[i] = &queuedScan{jobID: jobID, priority: priority, scan: scan}
+		return
+	}
+	m.launch(jobID, scan)
+}
+
+// launch runs a scan in a new goroutine, holding a slot until it returns.
+// Callers hold m.mu.
+func (m *ScanManager) launch(jobID string, scan func()) {
+	m.running++
+	go func() {
+		defer m.scanDone()
+		scan()
+	}()
+}
+
+// scanDone frees the slot of a finished scan and starts queued ones.
+func (m *ScanManager) scanDone() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.running--
+	m.startQueued()
+}
+
+// startQueued starts queued scans while there are free slots. Nothing starts
+// once the server is shutting down: with --api-db, jobs still queued are
+// restored as interrupted. Callers hold m.mu.
+func (m *ScanManager) startQueued() {
+	for len(m.waiting) > 0 && m.ctx.Err() == nil && (m.MaxConcurrentScans <= 0 || m.running < m.MaxConcurrentScans) {
+		next := m.waiting[0]
+		m.waiting = m.waiting[1:]
+		m.launch(next.jobID, next.scan)
+	}
+}
+
+// unqueue drops a job from the queue, reporting whether it was waiting.
+// Callers hold m.mu.
+func (m *ScanManager) unqueue(jobID string) bool {
+	for i, q := range m.waiting {
+		if q.jobID == jobID {
+			m.waiting = append(m.waiting[:i], m.waiting[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
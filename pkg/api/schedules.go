@@ -0,0 +1,275 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+// scheduleCheckInterval is how often due schedules are looked for; runs
+// start up to this late.
+const scheduleCheckInterval = 10 * time.Second
+
+var errScheduleNotFound = errors.New("schedule not found")
+
+// SchedulesHandler lists and creates recurring scans.
+// POST /schedules  Body: {"name": "nightly", "cron": "0 2 * * *", "template_id": "<id>"} or "request": {<same as /scan/start>}
+// GET  /schedules
+func (h *APIHandler) SchedulesHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(h.Manager.ListSchedules())
+	case http.MethodPost:
+		var sched types.JobSchedule
+		if !h.decodeBody(w, r, &sched, false) {
+			return
+		}
+		cron, err := parseCron(sched.Cron)
+		if err != nil {
+			http.Error(w, "Invalid cron: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if (sched.TemplateID == "") == (sched.Request == nil) {
+			http.Error(w, "Schedule needs either template_id or request", http.StatusBadRequest)
+			return
+		}
+		if sched.TemplateID != "" {
+			if _, err := h.Manager.GetTemplate(sched.TemplateID); err != nil {
+				http.Error(w, "template_id: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+		} else {
+			req := sched.Request
+			if req.TargetList != "" {
+				// Uploaded lists expire; the schedule keeps the URLs
+				listed, err := h.Manager.TargetList(req.TargetList)
+				if err != nil {
+					http.Error(w, "target_list: "+err.Error(), http.StatusBadRequest)
+					return
+				}
+				req.URLs = append(listed, req.URLs...)
+				req.TargetList = ""
+			}
+			if len(req.URLs) == 0 {
+				http.Error(w, "Schedule request needs urls or target_list", http.StatusBadRequest)
+				return
+			}
+			if len(req.Keywords) == 0 && len(req.Recipes) == 0 && len(req.Selectors) == 0 && len(req.JSONPaths) == 0 && !req.Entropy {
+				http.Error(w, "Schedule request needs keywords, recipes, selectors or jsonpaths", http.StatusBadRequest)
+				return
+			}
+			if err := ValidateCallback(*req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		saved := h.Manager.SaveSchedule(sched, cron)
+		log.Printf("[API] Stored schedule %s (%s, %q), next run %s%s", saved.ID, saved.Name, saved.Cron, saved.NextRun.Format(time.RFC3339), byKey(r))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(saved)
+	default:
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// ScheduleHandler serves a single schedule.
+// GET    /schedules/{id}
+// DELETE /schedules/{id}
+func (h *APIHandler) ScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/schedules/")
+	if id == "" || strings.Contains(id, "/") {
+		http.NotFound(w, r)
+		return
+	}
+	sched, err := h.Manager.GetSchedule(id)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(sched)
+	case http.MethodDelete:
+		h.Manager.DeleteSchedule(id)
+		log.Printf("[API] Deleted schedule %s%s", id, byKey(r))
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// SaveSchedule stores a new schedule, due next when cron matches, and
+// returns a copy of it.
+func (m *ScanManager) SaveSchedule(sched types.JobSchedule, cron *cronExpr) types.JobSchedule {
+	now := time.Now().UTC()
+	sched.ID = uuid.New().String()
+	sched.Created = now
+	sched.NextRun = cron.next(now)
+	sched.LastRun, sched.LastJobID, sched.LastError, sched.Runs = nil, "", "", 0
+	m.mu.Lock()
+	m.schedules[sched.ID] = &sched
+	saved := copySchedule(&sched)
+	m.mu.Unlock()
+	m.storeSchedule(&saved)
+	return saved
+}
+
+// GetSchedule returns a copy of a schedule.
+func (m *ScanManager) GetSchedule(id string) (*types.JobSchedule, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	sched, ok := m.schedules[id]
+	if !ok {
+		return nil, errScheduleNotFound
+	}
+	c := copySchedule(sched)
+	return &c, nil
+}
+
+// ListSchedules returns every schedule, oldest first.
+func (m *ScanManager) ListSchedules() []types.JobSchedule {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	list := make([]types.JobSchedule, 0, len(m.schedules))
+	for _, sched := range m.schedules {
+		list = append(list, copySchedule(sched))
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Created.Before(list[j].Created) })
+	return list
+}
+
+// DeleteSchedule removes a schedule; jobs it started are kept.
+func (m *ScanManager) DeleteSchedule(id string) {
+	m.mu.Lock()
+	delete(m.schedules, id)
+	m.mu.Unlock()
+	if m.Store != nil {
+		if err := m.Store.DeleteSchedule(id); err != nil {
+			log.Printf("[API] Deleting schedule %s from %v failed: %v", id, m.Store, err)
+		}
+	}
+}
+
+// dueSchedules returns copies of the schedules due at now and moves them on
+// to their next run, so each run is taken once.
+func (m *ScanManager) dueSchedules(now time.Time) []types.JobSchedule {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var due []types.JobSchedule
+	for _, sched := range m.schedules {
+		if sched.NextRun.IsZero() || sched.NextRun.After(now) {
+			continue
+		}
+		due = append(due, copySchedule(sched))
+		if cron, err := parseCron(sched.Cron); err == nil {
+			sched.NextRun = cron.next(now)
+		} else {
+			sched.NextRun = time.Time{} // Never again
+		}
+	}
+	return due
+}
+
+// recordScheduleRun remembers the outcome of a schedule's run: the job it
+// started, or why it started none.
+func (m *ScanManager) recordScheduleRun(id string, at time.Time, jobID, failure string) {
+	m.mu.Lock()
+	sched, ok := m.schedules[id]
+	var saved types.JobSchedule
+	if ok {
+		sched.LastRun, sched.LastJobID, sched.LastError = &at, jobID, failure
+		if jobID != "" {
+			sched.Runs++
+		}
+		saved = copySchedule(sched)
+	}
+	m.mu.Unlock()
+	if ok {
+		m.storeSchedule(&saved)
+	}
+}
+
+// storeSchedule writes a schedule to the Store, if there is one.
+func (m *ScanManager) storeSchedule(sched *types.JobSchedule) {
+	if m.Store == nil {
+		return
+	}
+	if err := m.Store.SaveSchedule(sched); err != nil {
+		log.Printf("[API] Storing schedule %s in %v failed: %v", sched.ID, m.Store, err)
+	}
+}
+
+// copySchedule copies a schedule and its request.
+func copySchedule(sched *types.JobSchedule) types.JobSchedule {
+	c := *sched
+	if sched.Request != nil {
+		req := *sched.Request
+		c.Request = &req
+	}
+	return c
+}
+
+// runDueSchedules starts a job for every schedule due at now, through the
+// same path as POST /scan/start.
+func (h *APIHandler) runDueSchedules(now time.Time) {
+	for _, sched := range h.Manager.dueSchedules(now) {
+		if h.Manager.ctx.Err() != nil {
+			return // Shutting down
+		}
+		var req types.ScanRequest
+		if sched.TemplateID != "" {
+			tmpl, err := h.Manager.GetTemplate(sched.TemplateID)
+			if err != nil {
+				log.Printf("[API] Schedule %s: template %s: %v", sched.ID, sched.TemplateID, err)
+				h.Manager.recordScheduleRun(sched.ID, now, "", "template "+sched.TemplateID+": "+err.Error())
+				continue
+			}
+			req = tmpl.Request
+		} else {
+			req = *sched.Request
+		}
+
+		rec := httptest.NewRecorder()
+		r := &http.Request{Method: http.MethodPost, URL: &url.URL{Path: "/schedules/" + sched.ID}, Header: make(http.Header)}
+		jobID := h.startScan(rec, r, req)
+		if jobID == "" {
+			failure := strings.TrimSpace(rec.Body.String())
+			log.Printf("[API] Schedule %s started no job: %s", sched.ID, failure)
+			h.Manager.recordScheduleRun(sched.ID, now, "", failure)
+			continue
+		}
+		if sched.TemplateID != "" {
+			h.Manager.RecordTemplateRun(sched.TemplateID, jobID)
+		}
+		log.Printf("[API] Job %s started by schedule %s", jobID, sched.ID)
+		h.Manager.recordScheduleRun(sched.ID, now, jobID, "")
+	}
+}
+
+// runSchedulesPeriodically starts the jobs of due schedules until stop is
+// closed.
+func runSchedulesPeriodically(h *APIHandler, stop <-chan struct{}) {
+	ticker := time.NewTicker(scheduleCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			h.runDueSchedules(now.UTC())
+		case <-stop:
+			return
+		}
+	}
+}
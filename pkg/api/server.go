@@ -4,30 +4,116 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
-	"os"
-	"os/signal"
-	"syscall"
+	"strings"
 	"time"
 
+	"github.com/nxneeraj/hx-hawks/pkg/config"
+
 	// Use gorilla/mux or net/http's default mux
 	// "github.com/gorilla/mux"
 )
 
-// StartServer initializes and runs the API server.
-func StartServer(port int) {
+// StartServer initializes and runs the API server using the API settings from
+// cfg until ctx is cancelled (SIGINT/SIGTERM in main), then shuts down.
+func StartServer(ctx context.Context, cfg *config.Config) {
+	port := cfg.APIPort
 	log.Printf("[API] Starting API server on port %d", port)
 
-	manager := NewScanManager()
+	manager := NewScanManager(ctx)
+	manager.MaxJobURLs = cfg.MaxJobURLs
+	manager.MaxConcurrentScans = cfg.APIMaxScans
+	if manager.MaxConcurrentScans > 0 {
+		log.Printf("[API] At most %d scans run at once; more are queued", manager.MaxConcurrentScans)
+	}
+	if manager.MaxJobURLs > 0 {
+		log.Printf("[API] Per-job URL quota: %d", manager.MaxJobURLs)
+	}
+	manager.JobTTL = cfg.APIJobTTL
+	if manager.JobTTL > 0 {
+		log.Printf("[API] Finished jobs are evicted after %s", manager.JobTTL)
+	}
+	if cfg.APIExportDir != "" {
+		manager.Sinks = append(manager.Sinks, &DirSink{Dir: cfg.APIExportDir, Fields: cfg.Fields})
+		log.Printf("[API] Finished jobs are exported to %s on shutdown", cfg.APIExportDir)
+	}
+	if cfg.APILinkTTL > 0 {
+		links, err := NewLinkSigner(cfg.APILinkSecret, cfg.APILinkTTL, cfg.APIPublicURL)
+		if err != nil {
+			log.Fatalf("[API] Result links: %v", err)
+		}
+		if cfg.APILinkSecret == "" {
+			log.Println("[API] No --api-link-secret given; download links stop working when the server restarts")
+		}
+		manager.Links = links
+		log.Printf("[API] Finished jobs get signed download links valid for %s", cfg.APILinkTTL)
+	}
+	manager.CallbackSecret = cfg.APICallbackSecret
+	if cfg.APIDB != "" {
+		store, err := OpenBoltStore(cfg.APIDB)
+		if err != nil {
+			log.Fatalf("[API] Job store: %v", err)
+		}
+		manager.Store = store
+		n, err := manager.Restore()
+		if err != nil {
+			log.Fatalf("[API] Loading jobs from %v: %v", store, err)
+		}
+		log.Printf("[API] Jobs are kept in %v (%d restored)", store, n)
+	}
 	handler := NewAPIHandler(manager)
+	handler.MaxRequestBytes = cfg.APIMaxRequest
+	handler.MaxUploadBytes = cfg.APIMaxUpload
+	handler.MaxThreads = cfg.APIMaxThreads
+	auth := NewKeyAuth(cfg.APIKeys)
+	limiter := NewRateLimiter(cfg.APIRateLimit)
+	if limiter != nil {
+		if auth != nil {
+			auth.Limiter = limiter
+		}
+		log.Printf("[API] Rate limit: %d requests per minute per client", cfg.APIRateLimit)
+	}
+	cors := NewCORS(cfg.APICORSOrigins, cfg.APICORSMethods, cfg.APICORSHeaders)
+	handler.CORS = cors
+	if cors != nil {
+		log.Printf("[API] CORS allowed for %s", strings.Join(cors.Origins, ", "))
+	}
+	if auth != nil {
+		log.Printf("[API] API key authentication on (%d keys)", len(cfg.APIKeys))
+	} else {
+		log.Printf("[API] No --api-key set: anyone who can reach port %d can start scans", port)
+	}
 
 	// --- Using net/http's DefaultServeMux ---
 	mux := http.NewServeMux()
 	mux.HandleFunc("/scan/start", handler.StartScanHandler)
+	mux.HandleFunc("/scan/upload", handler.UploadTargetsHandler) // Multipart target list, referenced as target_list
 	// Need careful path matching for IDs with default mux
 	mux.HandleFunc("/scan/status/", handler.ScanStatusHandler) // Note trailing slash - matches /scan/status/jobid
 	mux.HandleFunc("/scan/result/", handler.ScanResultHandler) // Note trailing slash - matches /scan/result/jobid
-	// mux.HandleFunc("/scan/stream/", handler.ScanStreamHandler) // For future SSE/WS
+	mux.HandleFunc("/scan/logs/", handler.ScanLogsHandler)     // Per-job log lines, ?follow=true to stream
+	mux.HandleFunc("/scan/cancel/", handler.CancelJobHandler)  // Stops a running job, keeping its results
+	mux.HandleFunc("/scan/jobs", handler.ListJobsHandler)      // Job summaries, ?status= and page/per_page
+	mux.HandleFunc("/scan/job/", handler.DeleteJobHandler)     // DELETE a finished job and its results
+	if manager.Links != nil {
+		mux.HandleFunc("/scan/download/", handler.DownloadHandler) // Signed, expiring result downloads
+	}
+	mux.HandleFunc("/scan/", handler.ScanJobHandler)           // Per-job sub-resources, e.g. /scan/{id}/targets
+	mux.HandleFunc("/scan/templates", handler.TemplatesHandler) // Stored job definitions
+	mux.HandleFunc("/scan/templates/", handler.TemplateHandler) // Show/delete a template, /scan/templates/{id}/run starts a job
+	mux.HandleFunc("/schedules", handler.SchedulesHandler)   // Recurring scans (cron)
+	mux.HandleFunc("/schedules/", handler.ScheduleHandler)  // Show/delete a schedule
+	mux.HandleFunc("/campaigns", handler.CampaignsHandler)     // Campaigns and their job IDs
+	mux.HandleFunc("/campaigns/", handler.CampaignHandler)     // Combined report, /campaigns/{id}/diff compares runs
+	mux.HandleFunc("/stats", handler.StatsHandler)
+	mux.HandleFunc("/metrics", handler.MetricsHandler) // Prometheus text format
+	mux.HandleFunc("/healthz", handler.HealthzHandler) // Liveness probe, no API key needed
+	mux.HandleFunc("/readyz", handler.ReadyzHandler)   // Readiness probe, 503 while shutting down
+	mux.HandleFunc("/openapi.json", handler.OpenAPIHandler) // OpenAPI 3 document, see apiOperations
+	mux.HandleFunc("/docs", handler.DocsHandler)             // Swagger UI
+	mux.HandleFunc("/scan/stream/", handler.ScanStreamHandler) // Results as Server-Sent Events
+	mux.HandleFunc("/ws/scan/", handler.ScanWebSocketHandler)  // Progress and results over a WebSocket, accepts cancel
 
 	/* // --- Using Gorilla Mux (Example) ---
 	r := mux.NewRouter()
@@ -39,38 +125,79 @@ func StartServer(port int) {
 
 	server := &http.Server{
 		Addr:         fmt.Sprintf(":%d", port),
-		Handler:      mux, // Use 'r' if using Gorilla Mux
+		Handler:      cors.Middleware(auth.Middleware(limiter.Middleware(mux))), // Use 'r' if using Gorilla Mux
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
+		BaseContext:  func(net.Listener) context.Context { return ctx }, // Ends streaming requests on shutdown
 	}
+	tlsConfig, err := serverTLSConfig(cfg)
+	if err != nil {
+		log.Fatalf("[API] TLS: %v", err)
+	}
+	server.TLSConfig = tlsConfig
 
 	// Graceful shutdown setup
 	// Run server in a goroutine so that it doesn't block.
 	go func() {
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		var err error
+		if tlsConfig != nil {
+			err = server.ListenAndServeTLS("", "") // Certificate is in TLSConfig
+		} else {
+			err = server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			log.Fatalf("[API] ListenAndServe error: %v", err)
 		}
 	}()
-	log.Printf("[API] Server listening on http://localhost:%d", port)
+	scheme := "http"
+	if tlsConfig != nil {
+		scheme = "https"
+	}
+	log.Printf("[API] Server listening on %s://localhost:%d", scheme, port)
 
-	// Wait for interrupt signal to gracefully shut down the server
-	quit := make(chan os.Signal, 1)
-	// kill (no param) default send syscall.SIGTERM
-	// kill -2 is syscall.SIGINT
-	// kill -9 is syscall.SIGKILL but can't be caught
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit // Block until signal is received
+	// Periodically log manager pressure so operators can spot trouble early
+	stopStats := make(chan struct{})
+	go logStatsPeriodically(manager, time.Minute, stopStats)
+	defer close(stopStats)
+	if manager.JobTTL > 0 {
+		go evictPeriodically(manager, stopStats)
+	}
+	if manager.Store != nil {
+		go persistPeriodically(manager, stopStats)
+	}
+	go runSchedulesPeriodically(handler, stopStats)
+
+	// Wait for the root context (interrupt signal) to gracefully shut down the server
+	<-ctx.Done()
 	log.Println("[API] Shutting down server...")
 
 	// The context is used to inform the server it has 5 seconds to finish
 	// the request it is currently handling
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	if err := server.Shutdown(ctx); err != nil {
-		log.Fatalf("[API] Server forced to shutdown: %v", err)
+	// Open streams (SSE, log follows) can outlast the window: close them and
+	// carry on, so finished jobs are still exported and the store closed below
+	forced := false
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("[API] Server forced to shutdown: %v", err)
+		server.Close()
+		forced = true
+	}
+
+	// Nothing in the in-memory store is discarded silently: hand finished jobs to the sinks
+	if len(manager.Sinks) > 0 {
+		log.Printf("[API] Exported %d finished jobs", manager.Flush(context.Background()))
+	}
+	if manager.Store != nil {
+		if err := manager.CloseStore(); err != nil {
+			log.Printf("[API] Closing %v: %v", manager.Store, err)
+		}
 	}
 
+	if forced {
+		log.Fatalln("[API] Server exiting after a forced shutdown.")
+	}
 	log.Println("[API] Server exiting gracefully.")
 }
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"runtime"
+	"time"
+
+	"github.com/nxneeraj/hx-hawks/pkg/scanner"
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+// resultOverheadBytes approximates the fixed in-memory cost of one ScanResult
+// (struct fields, timestamps, slice headers) on top of its variable-size strings.
+const resultOverheadBytes = 256
+
+// ManagerStats is a point-in-time snapshot of ScanManager pressure.
+type ManagerStats struct {
+	TotalJobs       int                      `json:"total_jobs"`
+	JobsByState     map[string]int           `json:"jobs_by_state"`
+	ResultsInMemory int                      `json:"results_in_memory"`
+	ResultsOnDisk   int                      `json:"results_on_disk,omitempty"` // Results of finished jobs read from --api-db when asked for
+	StoreSizeBytes  int64                    `json:"store_size_bytes"`          // Approximate size of the results in memory
+	JobGoroutines   map[string]int           `json:"job_goroutines"`            // Live goroutines of each scanning job: its own, workers, feeder, collector and monitor
+	Goroutines      int                      `json:"goroutines"`                // Total goroutines in the process
+	HeapAllocBytes  uint64                   `json:"heap_alloc_bytes"`
+	Workers         scanner.UtilizationStats `json:"worker_utilization"` // Worker time per phase across all jobs since startup
+	Timestamp       time.Time                `json:"timestamp"`
+}
+
+// Stats collects a snapshot of the manager's jobs and the process runtime.
+func (m *ScanManager) Stats() ManagerStats {
+	m.mu.RLock()
+	stats := ManagerStats{
+		TotalJobs:      len(m.jobs),
+		JobsByState:    make(map[string]int),
+		StoreSizeBytes: m.resultBytes,
+		JobGoroutines:  make(map[string]int, len(m.goroutines)),
+	}
+	for id, n := range m.goroutines {
+		stats.JobGoroutines[id] = n
+	}
+	for id, job := range m.jobs {
+		stats.JobsByState[job.Status]++
+		stats.ResultsInMemory += len(job.Results)
+		if st, ok := m.stored[id]; ok && st.released {
+			stats.ResultsOnDisk += st.results
+		}
+	}
+	m.mu.RUnlock()
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	stats.Goroutines = runtime.NumGoroutine()
+	stats.HeapAllocBytes = mem.HeapAlloc
+	stats.Workers = m.util.Stats()
+	stats.Timestamp = time.Now().UTC()
+	return stats
+}
+
+// resultSize estimates the memory held by a single result.
+func resultSize(r *types.ScanResult) int64 {
+	size := int64(resultOverheadBytes + len(r.URL) + len(r.ResponseBody) + len(r.Error) + len(r.IP) + len(r.ContentType))
+	for _, k := range r.MatchedKeywords {
+		size += int64(len(k))
+	}
+	return size
+}
+
+// trackGoroutine counts the calling goroutine against jobID in /stats until
+// the returned func is called: defer m.trackGoroutine(jobID)().
+func (m *ScanManager) trackGoroutine(jobID string) func() {
+	m.mu.Lock()
+	m.goroutines[jobID]++
+	m.mu.Unlock()
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if m.goroutines[jobID]--; m.goroutines[jobID] <= 0 {
+			delete(m.goroutines, jobID)
+		}
+	}
+}
+
+// dropResults releases a job's results from memory and from the resultBytes
+// count. Callers hold m.mu.
+func (m *ScanManager) dropResults(job *types.JobStatus) {
+	for i := range job.Results {
+		m.resultBytes -= resultSize(&job.Results[i])
+	}
+	job.Results = nil
+}
+
+// logStatsPeriodically logs manager stats every interval until stop is closed.
+func logStatsPeriodically(m *ScanManager, interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s := m.Stats()
+			if s.TotalJobs == 0 {
+				continue
+			}
+			log.Printf("[API Stats] jobs=%d states=%v results=%d store=%dB goroutines=%d heap=%dB",
+				s.TotalJobs, s.JobsByState, s.ResultsInMemory, s.StoreSizeBytes, s.Goroutines, s.HeapAllocBytes)
+		case <-stop:
+			return
+		}
+	}
+}
+
+// StatsHandler returns internal manager metrics.
+// GET /stats
+func (h *APIHandler) StatsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.Manager.Stats())
+}
@@ -0,0 +1,187 @@
+package api
+
+import (
+	"log"
+	"sort"
+	"time"
+
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+// storeFlushInterval is how often job progress is written to the Store; a
+// crash loses at most this much of it.
+const storeFlushInterval = time.Second
+
+// jobInterrupted is the error of jobs found unfinished in the Store at start.
+const jobInterrupted = "interrupted: the server stopped before the job finished"
+
+// JobStore persists jobs, their results and templates so they survive server
+// restarts (--api-db). The manager writes to it in the background (see
+// persistJobs) and reads finished jobs' results back on demand.
+type JobStore interface {
+	// SaveJob writes a job's metadata and appends results, which follow
+	// the ones already stored (job.Results counts them all), atomically.
+	SaveJob(job *StoredJob, results []types.ScanResult) error
+	LoadJobs() ([]*StoredJob, error)
+	// LoadResults returns a job's stored results from index from on.
+	LoadResults(jobID string, from int) ([]types.ScanResult, error)
+	DeleteJob(jobID string) error
+	SaveTemplate(tmpl *types.JobTemplate) error
+	LoadTemplates() ([]*types.JobTemplate, error)
+	DeleteTemplate(id string) error
+	SaveSchedule(s *types.JobSchedule) error
+	LoadSchedules() ([]*types.JobSchedule, error)
+	DeleteSchedule(id string) error
+	Close() error
+}
+
+// StoredJob is what the Store keeps of a job besides its results.
+type StoredJob struct {
+	Status  types.JobStatus     `json:"status"`          // Without results
+	Results int                 `json:"results"`         // Number of results stored
+	Hosts   []types.HostSummary `json:"hosts,omitempty"` // Per-host rollup
+}
+
+// storedState tracks what of a job has been written to the Store.
+type storedState struct {
+	results  int  // Results written so far
+	settled  bool // Finished and written in its final state
+	released bool // Results dropped from memory: read them from the Store
+}
+
+// Restore loads the jobs, templates and schedules kept in the Store,
+// returning the number of jobs. Their results stay on disk until asked for.
+// Jobs that were still running when the server stopped are marked as
+// errored; schedules skip the runs they missed.
+func (m *ScanManager) Restore() (int, error) {
+	stored, err := m.Store.LoadJobs()
+	if err != nil {
+		return 0, err
+	}
+	templates, err := m.Store.LoadTemplates()
+	if err != nil {
+		return 0, err
+	}
+	schedules, err := m.Store.LoadSchedules()
+	if err != nil {
+		return 0, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sort.Slice(stored, func(i, j int) bool { return stored[i].Status.StartTime.Before(stored[j].Status.StartTime) })
+	now := time.Now().UTC()
+	for _, sj := range stored {
+		job := sj.Status
+		job.Results = nil
+		st := &storedState{results: sj.Results, settled: jobFinished(job.Status), released: true}
+		if !st.settled {
+			job.Status = "Error"
+			job.Error = jobInterrupted
+			job.EndTime = &now
+		}
+		m.jobs[job.JobID] = &job
+		m.stored[job.JobID] = st
+		m.exported[job.JobID] = st.settled // Exported when the server that ran it stopped
+		m.changed[job.JobID] = make(chan struct{})
+		jl := NewJobLog(defaultJobLogLines) // Log lines aren't stored
+		jl.Close()
+		m.logs[job.JobID] = jl
+		hosts := make(map[string]*types.HostSummary, len(sj.Hosts))
+		for i := range sj.Hosts {
+			hosts[sj.Hosts[i].Host] = &sj.Hosts[i]
+		}
+		m.hosts[job.JobID] = hosts
+		if job.Campaign != "" {
+			m.campaigns[job.Campaign] = append(m.campaigns[job.Campaign], job.JobID)
+		}
+	}
+	for _, tmpl := range templates {
+		m.templates[tmpl.ID] = tmpl
+	}
+	for _, s := range schedules {
+		if cron, err := parseCron(s.Cron); err == nil {
+			s.NextRun = cron.next(now)
+		}
+		m.schedules[s.ID] = s
+	}
+	return len(stored), nil
+}
+
+// persistJobs writes every job not yet settled to the Store: its metadata
+// and the results added since the last call. Finished jobs then have their
+// results released from memory.
+func (m *ScanManager) persistJobs() {
+	m.storeMu.Lock()
+	defer m.storeMu.Unlock()
+	if m.storeClosed {
+		return
+	}
+
+	type pending struct {
+		job      StoredJob
+		results  []types.ScanResult
+		finished bool
+	}
+	m.mu.RLock()
+	var batch []pending
+	for id, job := range m.jobs {
+		st := m.stored[id]
+		if st == nil || st.settled {
+			continue
+		}
+		p := pending{
+			job:      StoredJob{Status: *statusCopy(job), Hosts: m.hostList(id)},
+			finished: jobFinished(job.Status),
+		}
+		if st.released {
+			p.job.Results = st.results // Restored unfinished job: results are all on disk
+		} else {
+			p.job.Results = len(job.Results)
+			p.results = append(p.results, job.Results[st.results:]...)
+		}
+		batch = append(batch, p)
+	}
+	m.mu.RUnlock()
+
+	for _, p := range batch {
+		id := p.job.Status.JobID
+		if err := m.Store.SaveJob(&p.job, p.results); err != nil {
+			log.Printf("[API] Storing job %s in %v failed: %v", id, m.Store, err)
+			continue
+		}
+		m.mu.Lock()
+		if st, ok := m.stored[id]; ok {
+			st.results = p.job.Results
+			if p.finished {
+				st.settled, st.released = true, true
+				m.dropResults(m.jobs[id]) // Read back from the Store when asked for
+			}
+		}
+		m.mu.Unlock()
+	}
+}
+
+// CloseStore writes the jobs' last progress to the Store and closes it.
+func (m *ScanManager) CloseStore() error {
+	m.persistJobs()
+	m.storeMu.Lock()
+	defer m.storeMu.Unlock()
+	m.storeClosed = true
+	return m.Store.Close()
+}
+
+// persistPeriodically writes job progress to the Store (--api-db) until stop
+// is closed.
+func persistPeriodically(m *ScanManager, stop <-chan struct{}) {
+	ticker := time.NewTicker(storeFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			m.persistJobs()
+		case <-stop:
+			return
+		}
+	}
+}
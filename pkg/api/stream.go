@@ -0,0 +1,104 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// streamKeepAlive is how often an idle event stream sends a comment line, so
+// proxies don't close it.
+const streamKeepAlive = 15 * time.Second
+
+// ScanStreamHandler streams a job's results as Server-Sent Events: a
+// "result" event per ScanResult as it is added (id = its 1-based position,
+// so a reconnecting client's Last-Event-ID resumes after it), then a
+// "complete" event with the final job status.
+// GET /scan/stream/{id}
+func (h *APIHandler) ScanStreamHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	jobID := strings.TrimPrefix(r.URL.Path, "/scan/stream/")
+	if jobID == "" || strings.Contains(jobID, "/") {
+		http.Error(w, "Invalid or missing Job ID in URL path", http.StatusBadRequest)
+		return
+	}
+	next := 0
+	if last := r.Header.Get("Last-Event-ID"); last != "" {
+		n, err := strconv.Atoi(last)
+		if err != nil || n < 0 {
+			http.Error(w, "Invalid Last-Event-ID", http.StatusBadRequest)
+			return
+		}
+		next = n
+	}
+	results, next, status, changed, err := h.Manager.ResultsSince(jobID, next)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // Stop nginx from buffering the stream
+	rc := http.NewResponseController(w)
+	// Streaming outlives the server's write timeout
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		first := next - len(results)
+		for i, result := range results {
+			if err := writeEvent(w, "result", strconv.Itoa(first+i+1), result); err != nil {
+				return
+			}
+		}
+		if jobFinished(status.Status) {
+			_ = writeEvent(w, "complete", "", status)
+			_ = rc.Flush()
+			return
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+
+		select {
+		case <-changed:
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			_ = rc.Flush()
+			results = nil // Already sent
+			continue
+		case <-r.Context().Done():
+			return
+		}
+		results, next, status, changed, err = h.Manager.ResultsSince(jobID, next)
+		if err != nil {
+			return // Job deleted
+		}
+	}
+}
+
+// writeEvent writes one Server-Sent Event with data encoded as JSON.
+func writeEvent(w http.ResponseWriter, event, id string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if id != "" {
+		if _, err := fmt.Fprintf(w, "id: %s\n", id); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+	return err
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+// TemplatesHandler stores and lists job templates.
+// POST /scan/templates  Body: {"name": "weekly-external", "request": {<same as /scan/start>}}
+// GET  /scan/templates
+func (h *APIHandler) TemplatesHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(h.Manager.ListTemplates())
+	case http.MethodPost:
+		var tmpl types.JobTemplate
+		if !h.decodeBody(w, r, &tmpl, false) {
+			return
+		}
+		if len(tmpl.Request.URLs) == 0 {
+			http.Error(w, "Template request needs urls", http.StatusBadRequest)
+			return
+		}
+		if len(tmpl.Request.Keywords) == 0 && len(tmpl.Request.Recipes) == 0 && len(tmpl.Request.Selectors) == 0 && len(tmpl.Request.JSONPaths) == 0 {
+			http.Error(w, "Template request needs keywords, recipes, selectors or jsonpaths", http.StatusBadRequest)
+			return
+		}
+		id := h.Manager.SaveTemplate(tmpl)
+		log.Printf("[API] Stored job template %s (%s)%s", id, tmpl.Name, byKey(r))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]string{"template_id": id})
+	default:
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// TemplateHandler serves a single template.
+// GET    /scan/templates/{id}
+// DELETE /scan/templates/{id}
+// POST   /scan/templates/{id}/run  Optional body: fields overriding the stored request, e.g. {"label": "2024-w18"}
+func (h *APIHandler) TemplateHandler(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/scan/templates/"), "/"), "/")
+	id := parts[0]
+	if id == "" || len(parts) > 2 || (len(parts) == 2 && parts[1] != "run") {
+		http.NotFound(w, r)
+		return
+	}
+	tmpl, err := h.Manager.GetTemplate(id)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	if len(parts) == 2 {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		// Decoding over the stored request replaces only the fields sent
+		request := tmpl.Request
+		if !h.decodeBody(w, r, &request, true) {
+			return
+		}
+		if jobID := h.startScan(w, r, request); jobID != "" {
+			h.Manager.RecordTemplateRun(id, jobID)
+			log.Printf("[API] Job %s started from template %s", jobID, id)
+		}
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(tmpl)
+	case http.MethodDelete:
+		h.Manager.DeleteTemplate(id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"math/big"
+	"net"
+	"os"
+	"time"
+
+	"github.com/nxneeraj/hx-hawks/pkg/config"
+)
+
+// selfSignedValidity is how long a generated certificate is valid.
+const selfSignedValidity = 365 * 24 * time.Hour
+
+// serverTLSConfig returns the TLS configuration of the API server: the
+// --api-tls-cert/--api-tls-key pair, or a certificate generated at start
+// with --api-tls-self-signed. It returns nil for plain HTTP.
+func serverTLSConfig(cfg *config.Config) (*tls.Config, error) {
+	var cert tls.Certificate
+	switch {
+	case cfg.APITLSCert != "":
+		var err error
+		if cert, err = tls.LoadX509KeyPair(cfg.APITLSCert, cfg.APITLSKey); err != nil {
+			return nil, err
+		}
+		log.Printf("[API] TLS certificate loaded from %s", cfg.APITLSCert)
+	case cfg.APITLSSelfSigned:
+		var err error
+		if cert, err = selfSignedCertificate(); err != nil {
+			return nil, fmt.Errorf("generating self-signed certificate: %w", err)
+		}
+		sum := sha256.Sum256(cert.Certificate[0])
+		log.Printf("[API] Using a self-signed TLS certificate generated at start, SHA-256 fingerprint %s", hex.EncodeToString(sum[:]))
+	default:
+		return nil, nil
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// selfSignedCertificate generates an ECDSA certificate for localhost, the
+// machine's host name and the loopback addresses.
+func selfSignedCertificate() (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{"hx-hawks"}, CommonName: "hx-hawks API"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(selfSignedValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+	if host, err := os.Hostname(); err == nil && host != "localhost" {
+		tmpl.DNSNames = append(tmpl.DNSNames, host)
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
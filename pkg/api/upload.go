@@ -0,0 +1,135 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+	"github.com/nxneeraj/hx-hawks/pkg/utils"
+)
+
+// targetListTTL is how long an uploaded target list can be used.
+const targetListTTL = 24 * time.Hour
+
+var errTargetListNotFound = errors.New("target list not found or expired")
+
+// targetList is an uploaded list of URLs (POST /scan/upload).
+type targetList struct {
+	urls    []string
+	expires time.Time
+}
+
+// UploadTargetsHandler stores a target list sent as the "file" part of a
+// multipart form, one URL per line as in -f files, and answers with its ID
+// for the target_list field of /scan/start. The file is read as it streams
+// in, up to MaxUploadBytes.
+// POST /scan/upload  curl -F file=@urls.txt
+func (h *APIHandler) UploadTargetsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.MaxUploadBytes > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, h.MaxUploadBytes)
+	}
+	defer r.Body.Close()
+	mr, err := r.MultipartReader()
+	if err != nil {
+		http.Error(w, "Expected a multipart/form-data body with a file part: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var lines []string
+	for {
+		part, err := mr.NextPart()
+		if err != nil {
+			if !h.uploadFailed(w, r, err) {
+				http.Error(w, `Missing "file" part`, http.StatusBadRequest)
+			}
+			return
+		}
+		if part.FormName() != "file" {
+			continue
+		}
+		lines, err = utils.ReadLinesFrom(part, part.FileName())
+		if err != nil {
+			if !h.uploadFailed(w, r, err) {
+				http.Error(w, "Invalid target list: "+err.Error(), http.StatusBadRequest)
+			}
+			return
+		}
+		break
+	}
+
+	// Bare hosts are kept; /scan/start probes their scheme
+	urls := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if strings.HasPrefix(line, "http://") || strings.HasPrefix(line, "https://") || utils.IsBareHost(line) {
+			urls = append(urls, line)
+		}
+	}
+	if len(urls) == 0 {
+		http.Error(w, "No http(s) URLs or hosts in the uploaded file", http.StatusBadRequest)
+		return
+	}
+	if h.overURLQuota(w, len(urls)) {
+		return
+	}
+
+	id, expires := h.Manager.SaveTargetList(urls)
+	log.Printf("[API] Stored target list %s (%d URLs)%s", id, len(urls), byKey(r))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(types.TargetListInfo{
+		TargetListID: id,
+		URLs:         len(urls),
+		Skipped:      len(lines) - len(urls),
+		Expires:      expires,
+	})
+}
+
+// uploadFailed answers 413 if err comes from an upload over MaxUploadBytes,
+// reporting whether it did.
+func (h *APIHandler) uploadFailed(w http.ResponseWriter, r *http.Request, err error) bool {
+	var tooLarge *http.MaxBytesError
+	if !errors.As(err, &tooLarge) {
+		return false
+	}
+	log.Printf("[API] Rejected upload from %s: over %d bytes%s", r.RemoteAddr, tooLarge.Limit, byKey(r))
+	http.Error(w, fmt.Sprintf("Upload too large (limit %d bytes)", tooLarge.Limit), http.StatusRequestEntityTooLarge)
+	return true
+}
+
+// SaveTargetList stores an uploaded target list, dropping expired ones, and
+// returns its ID and expiry.
+func (m *ScanManager) SaveTargetList(urls []string) (string, time.Time) {
+	id := uuid.New().String()
+	now := time.Now().UTC()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for listID, list := range m.targetLists {
+		if now.After(list.expires) {
+			delete(m.targetLists, listID)
+		}
+	}
+	m.targetLists[id] = &targetList{urls: urls, expires: now.Add(targetListTTL)}
+	return id, now.Add(targetListTTL)
+}
+
+// TargetList returns a copy of the URLs of an uploaded target list.
+func (m *ScanManager) TargetList(id string) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	list, ok := m.targetLists[id]
+	if !ok || time.Now().After(list.expires) {
+		return nil, errTargetListNotFound
+	}
+	return append([]string(nil), list.urls...), nil
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+// ScanWebSocketHandler streams a job over a WebSocket: the current status as
+// a "progress" message, each result as a "result" message as it is added,
+// a "progress" message after every batch, and a "complete" message with the
+// final status, after which the server closes the connection. Clients can
+// send {"action": "cancel"} to cancel the job. ?from=N skips the first N
+// results, for reconnecting clients.
+// GET /ws/scan/{id}
+func (h *APIHandler) ScanWebSocketHandler(w http.ResponseWriter, r *http.Request) {
+	jobID := strings.TrimPrefix(r.URL.Path, "/ws/scan/")
+	if jobID == "" || strings.Contains(jobID, "/") {
+		http.Error(w, "Invalid or missing Job ID in URL path", http.StatusBadRequest)
+		return
+	}
+	from := 0
+	if v := r.URL.Query().Get("from"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			http.Error(w, "from must be a number of results", http.StatusBadRequest)
+			return
+		}
+		from = n
+	}
+	if _, err := h.Manager.GetJobStatus(jobID); err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	server := websocket.Server{
+		Handshake: func(_ *websocket.Config, r *http.Request) error { return h.checkWebSocketOrigin(r) },
+		Handler:   func(ws *websocket.Conn) { h.streamWebSocket(ws, jobID, from) },
+	}
+	server.ServeHTTP(w, r)
+}
+
+// checkWebSocketOrigin lets non-browser clients (no Origin), pages served by
+// the API itself and --api-cors-origins open a WebSocket. Browsers don't
+// apply CORS to WebSockets, so without this any site could cancel jobs from
+// a visitor's browser. A rejected handshake gets 403.
+func (h *APIHandler) checkWebSocketOrigin(r *http.Request) error {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return nil
+	}
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+		return nil
+	}
+	if h.CORS != nil && h.CORS.allowed(origin) {
+		return nil
+	}
+	log.Printf("[API] Rejected WebSocket %s from origin %s", r.URL.Path, origin)
+	return fmt.Errorf("origin %s not allowed", origin)
+}
+
+// streamWebSocket serves one /ws/scan/{id} connection.
+func (h *APIHandler) streamWebSocket(ws *websocket.Conn, jobID string, next int) {
+	defer ws.Close()
+	// Streaming outlives the server's read and write timeouts
+	_ = ws.SetDeadline(time.Time{})
+
+	commandErrs := make(chan string, 1)
+	gone := make(chan struct{})
+	go func() {
+		defer close(gone)
+		for {
+			var data []byte
+			if err := websocket.Message.Receive(ws, &data); err != nil {
+				return // Client went away
+			}
+			var cmd types.WSCommand
+			msg := ""
+			switch err := json.Unmarshal(data, &cmd); {
+			case err != nil:
+				msg = "invalid command: " + err.Error()
+			case cmd.Action == "cancel":
+				if _, err := h.Manager.CancelJob(jobID); err != nil {
+					msg = "cancel: " + err.Error()
+				} else {
+					log.Printf("[API Job %s] Cancelled over WebSocket%s", jobID, byKey(ws.Request()))
+				}
+			default:
+				msg = "unknown action " + strconv.Quote(cmd.Action) + " (use cancel)"
+			}
+			if msg != "" {
+				select {
+				case commandErrs <- msg:
+				default:
+				}
+			}
+		}
+	}()
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		results, n, status, changed, err := h.Manager.ResultsSince(jobID, next)
+		if err != nil {
+			_ = websocket.JSON.Send(ws, types.WSMessage{Type: "error", Error: err.Error()})
+			return
+		}
+		first := n - len(results)
+		for i := range results {
+			if err := websocket.JSON.Send(ws, types.WSMessage{Type: "result", Index: first + i + 1, Result: &results[i]}); err != nil {
+				return
+			}
+		}
+		next = n
+		if jobFinished(status.Status) {
+			_ = websocket.JSON.Send(ws, types.WSMessage{Type: "complete", Status: status})
+			return
+		}
+		if err := websocket.JSON.Send(ws, types.WSMessage{Type: "progress", Status: status}); err != nil {
+			return
+		}
+
+		for waiting := true; waiting; {
+			var msg types.WSMessage
+			select {
+			case <-changed:
+				waiting = false
+				continue
+			case text := <-commandErrs:
+				msg = types.WSMessage{Type: "error", Error: text}
+			case <-keepAlive.C:
+				msg = types.WSMessage{Type: "keep-alive"}
+			case <-gone:
+				return
+			}
+			if err := websocket.JSON.Send(ws, msg); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package bench
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/nxneeraj/hx-hawks/pkg/config"
+	"github.com/nxneeraj/hx-hawks/pkg/matcher"
+	"github.com/nxneeraj/hx-hawks/pkg/rules"
+)
+
+// RuleCost is the measured cost of matching a single rule over the corpus.
+type RuleCost struct {
+	ID         string
+	Duration   time.Duration // Total time across all iterations
+	Throughput float64       // MB/s
+	Hits       int           // Corpus documents matched (per iteration)
+}
+
+// Report summarizes a benchmark run.
+type Report struct {
+	Documents  int
+	CorpusSize int64
+	Iterations int
+	Combined   RuleCost   // All keywords in one automaton plus all regexes
+	Rules      []RuleCost // Sorted by cost, most expensive first
+}
+
+// Run loads the rules and corpus and measures matcher throughput.
+func Run(cfg *config.BenchConfig) (*Report, error) {
+	var ruleSet []rules.Rule
+	if cfg.RulesFile != "" {
+		loaded, err := rules.Load(cfg.RulesFile)
+		if err != nil {
+			return nil, err
+		}
+		ruleSet = loaded
+	}
+	if len(cfg.Keywords) > 0 {
+		ruleSet = append(ruleSet, rules.Rule{ID: "--ck", Keywords: cfg.Keywords})
+	}
+
+	corpus, size, err := loadCorpus(cfg.CorpusDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(corpus) == 0 {
+		return nil, fmt.Errorf("no files found in corpus directory %s", cfg.CorpusDir)
+	}
+
+	report := &Report{Documents: len(corpus), CorpusSize: size, Iterations: cfg.Iterations}
+
+	// Combined engine: the way the scanner matches (one automaton + regexes)
+	automaton := matcher.New(rules.Keywords(ruleSet))
+	report.Combined = measure("combined", corpus, size, cfg.Iterations, func(doc []byte) bool {
+		hit := len(automaton.Match(doc)) > 0
+		for i := range ruleSet {
+			if ruleSet[i].MatchRegex(doc) {
+				hit = true
+			}
+		}
+		return hit
+	})
+
+	// Per-rule cost, each rule on its own
+	for i := range ruleSet {
+		r := &ruleSet[i]
+		ruleAutomaton := matcher.New(r.Keywords)
+		report.Rules = append(report.Rules, measure(r.ID, corpus, size, cfg.Iterations, func(doc []byte) bool {
+			return len(ruleAutomaton.Match(doc)) > 0 || r.MatchRegex(doc)
+		}))
+	}
+	sort.Slice(report.Rules, func(i, j int) bool {
+		return report.Rules[i].Duration > report.Rules[j].Duration
+	})
+
+	return report, nil
+}
+
+// measure runs match over the corpus for the given iterations.
+func measure(id string, corpus [][]byte, size int64, iterations int, match func([]byte) bool) RuleCost {
+	cost := RuleCost{ID: id}
+	start := time.Now()
+	for it := 0; it < iterations; it++ {
+		hits := 0
+		for _, doc := range corpus {
+			if match(doc) {
+				hits++
+			}
+		}
+		cost.Hits = hits
+	}
+	cost.Duration = time.Since(start)
+	if secs := cost.Duration.Seconds(); secs > 0 {
+		cost.Throughput = float64(size*int64(iterations)) / (1024 * 1024) / secs
+	}
+	return cost
+}
+
+// loadCorpus reads every regular file under dir into memory.
+func loadCorpus(dir string) ([][]byte, int64, error) {
+	var corpus [][]byte
+	var size int64
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		corpus = append(corpus, data)
+		size += int64(len(data))
+		return nil
+	})
+	return corpus, size, err
+}
+
+// Print writes a human-readable report.
+func (r *Report) Print(w io.Writer) {
+	fmt.Fprintf(w, "Corpus: %d documents, %.2f MB, %d iterations\n\n", r.Documents, float64(r.CorpusSize)/(1024*1024), r.Iterations)
+	fmt.Fprintf(w, "Combined matcher: %.2f MB/s (%s total, %d documents matched)\n\n", r.Combined.Throughput, r.Combined.Duration, r.Combined.Hits)
+	fmt.Fprintf(w, "%-32s %12s %14s %8s\n", "RULE", "MB/s", "TIME/PASS", "HITS")
+	for _, c := range r.Rules {
+		perPass := c.Duration / time.Duration(r.Iterations)
+		fmt.Fprintf(w, "%-32s %12.2f %14s %8d\n", c.ID, c.Throughput, perPass, c.Hits)
+	}
+}
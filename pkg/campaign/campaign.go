@@ -0,0 +1,196 @@
+// Package campaign groups scan runs (different tools, profiles and points in
+// time) under one name, with a combined view of every finding across runs.
+package campaign
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+// validName restricts campaign names to something safe as a directory name.
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,127}$`)
+
+// ValidateName checks that name can be used as a campaign ID.
+func ValidateName(name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("invalid campaign name %q (letters, digits, '.', '_' and '-' only)", name)
+	}
+	return nil
+}
+
+// Run is one scan or imported report recorded in a campaign.
+type Run struct {
+	ID         string    `json:"id"`              // Timestamp-based for CLI runs, job ID for API jobs
+	Label      string    `json:"label,omitempty"` // Tool or profile, e.g. "nuclei", "nightly"
+	Time       time.Time `json:"time"`
+	Total      int       `json:"total"`
+	Vulnerable int       `json:"vulnerable"`
+}
+
+// NewRun describes results as a run recorded at t.
+func NewRun(id, label string, t time.Time, results []types.ScanResult) Run {
+	run := Run{ID: id, Label: label, Time: t.UTC(), Total: len(results)}
+	for _, r := range results {
+		if r.IsVulnerable {
+			run.Vulnerable++
+		}
+	}
+	return run
+}
+
+// Finding is a URL that was vulnerable in at least one run.
+type Finding struct {
+	URL       string   `json:"url"`
+	Keywords  []string `json:"keywords,omitempty"` // Union across runs
+	Rules     []string `json:"rules,omitempty"`    // Union of rule IDs across runs
+	FirstSeen string   `json:"first_seen"`         // Run ID
+	LastSeen  string   `json:"last_seen"`          // Last run that found it vulnerable
+	Runs      int      `json:"runs"`               // Runs that found it vulnerable
+	Open      bool     `json:"open"`               // Still vulnerable in the latest run that scanned it
+}
+
+// Summary is the combined report of a campaign.
+type Summary struct {
+	Campaign string    `json:"campaign"`
+	Runs     []Run     `json:"runs"`
+	Open     int       `json:"open"`
+	Closed   int       `json:"closed"`
+	Findings []Finding `json:"findings,omitempty"`
+}
+
+// Summarize combines the results of a campaign's runs (results[i] belongs to
+// runs[i]) into one report. Runs are processed in time order; a finding is
+// open unless a later run scanned the URL without an error and found nothing.
+func Summarize(name string, runs []Run, results [][]types.ScanResult) *Summary {
+	order := make([]int, len(runs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return runs[order[a]].Time.Before(runs[order[b]].Time) })
+
+	summary := &Summary{Campaign: name}
+	findings := make(map[string]*Finding)
+	keywordSets := make(map[string]map[string]bool)
+	ruleSets := make(map[string]map[string]bool)
+
+	for _, i := range order {
+		run := runs[i]
+		summary.Runs = append(summary.Runs, run)
+		for _, r := range results[i] {
+			if r.Error != "" {
+				continue // Not scanned, says nothing about the finding
+			}
+			f, known := findings[r.URL]
+			if !r.IsVulnerable {
+				if known {
+					f.Open = false
+				}
+				continue
+			}
+			if !known {
+				f = &Finding{URL: r.URL, FirstSeen: run.ID}
+				findings[r.URL] = f
+				keywordSets[r.URL] = make(map[string]bool)
+				ruleSets[r.URL] = make(map[string]bool)
+			}
+			if f.LastSeen != run.ID {
+				f.Runs++
+			}
+			f.LastSeen, f.Open = run.ID, true
+			for _, k := range r.MatchedKeywords {
+				keywordSets[r.URL][k] = true
+			}
+			for _, m := range r.MatchedRules {
+				ruleSets[r.URL][m.ID] = true
+			}
+		}
+	}
+
+	urls := make([]string, 0, len(findings))
+	for u := range findings {
+		urls = append(urls, u)
+	}
+	sort.Strings(urls)
+	for _, u := range urls {
+		f := findings[u]
+		f.Keywords = sortedSet(keywordSets[u])
+		f.Rules = sortedSet(ruleSets[u])
+		if f.Open {
+			summary.Open++
+		} else {
+			summary.Closed++
+		}
+		summary.Findings = append(summary.Findings, *f)
+	}
+	return summary
+}
+
+// Pick returns the indexes of the from and to runs for a diff. Empty IDs
+// default to the second-to-last and last run (by time).
+func Pick(runs []Run, from, to string) (int, int, error) {
+	if len(runs) < 2 && (from == "" || to == "") {
+		return 0, 0, fmt.Errorf("need at least two runs to compare, campaign has %d", len(runs))
+	}
+	order := make([]int, len(runs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return runs[order[a]].Time.Before(runs[order[b]].Time) })
+
+	find := func(id string, fallback int) (int, error) {
+		if id == "" {
+			return order[fallback], nil
+		}
+		for i, r := range runs {
+			if r.ID == id {
+				return i, nil
+			}
+		}
+		return 0, fmt.Errorf("run %q not found", id)
+	}
+	fromIdx, err := find(from, len(order)-2)
+	if err != nil {
+		return 0, 0, err
+	}
+	toIdx, err := find(to, len(order)-1)
+	if err != nil {
+		return 0, 0, err
+	}
+	return fromIdx, toIdx, nil
+}
+
+// sortedSet returns the members of set in order.
+func sortedSet(set map[string]bool) []string {
+	if len(set) == 0 {
+		return nil
+	}
+	out := make([]string, 0, len(set))
+	for k := range set {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Print writes a human-readable version of the summary.
+func (s *Summary) Print(w io.Writer) {
+	fmt.Fprintf(w, "Campaign %s: %d runs, %d open findings, %d closed\n\n", s.Campaign, len(s.Runs), s.Open, s.Closed)
+	for _, r := range s.Runs {
+		fmt.Fprintf(w, "  %-24s %-12s %s  %d scanned, %d vulnerable\n", r.ID, r.Label, r.Time.Format(time.RFC3339), r.Total, r.Vulnerable)
+	}
+	if len(s.Findings) > 0 {
+		fmt.Fprintln(w)
+	}
+	for _, f := range s.Findings {
+		state := "OPEN  "
+		if !f.Open {
+			state = "CLOSED"
+		}
+		fmt.Fprintf(w, "  %s %s (runs: %d, first: %s, last: %s)\n", state, f.URL, f.Runs, f.FirstSeen, f.LastSeen)
+	}
+}
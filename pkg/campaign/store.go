@@ -0,0 +1,174 @@
+package campaign
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+// ErrNotFound is returned for campaigns that have no recorded runs.
+var ErrNotFound = errors.New("campaign not found")
+
+// Store keeps CLI campaigns on disk: <dir>/<name>/campaign.json lists the
+// runs and <dir>/<name>/runs/<run-id>.json holds each run's full results.
+type Store struct {
+	Dir string
+}
+
+// manifest is the layout of campaign.json.
+type manifest struct {
+	Name    string    `json:"name"`
+	Created time.Time `json:"created"`
+	Runs    []Run     `json:"runs"`
+}
+
+// DefaultDir returns ~/.hx-hawks/campaigns (or ./.hx-hawks/campaigns if the
+// home directory is unknown).
+func DefaultDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = "."
+	}
+	return filepath.Join(home, ".hx-hawks", "campaigns")
+}
+
+// NewStore opens the store in dir ("" = DefaultDir).
+func NewStore(dir string) *Store {
+	if dir == "" {
+		dir = DefaultDir()
+	}
+	return &Store{Dir: dir}
+}
+
+// Record adds results as a new run of the campaign, creating it if needed.
+func (s *Store) Record(name, label string, results []types.ScanResult) (Run, error) {
+	if err := ValidateName(name); err != nil {
+		return Run{}, err
+	}
+	m, err := s.load(name)
+	if errors.Is(err, ErrNotFound) {
+		m, err = &manifest{Name: name, Created: time.Now().UTC()}, nil
+	}
+	if err != nil {
+		return Run{}, err
+	}
+
+	now := time.Now().UTC()
+	id := now.Format("20060102T150405.000Z")
+	run := NewRun(id, label, now, results)
+
+	runsDir := filepath.Join(s.Dir, name, "runs")
+	if err := os.MkdirAll(runsDir, 0755); err != nil {
+		return Run{}, err
+	}
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return Run{}, err
+	}
+	if err := os.WriteFile(filepath.Join(runsDir, id+".json"), append(data, '\n'), 0644); err != nil {
+		return Run{}, err
+	}
+
+	m.Runs = append(m.Runs, run)
+	return run, s.save(m)
+}
+
+// Runs returns the runs of a campaign in the order they were recorded.
+func (s *Store) Runs(name string) ([]Run, error) {
+	m, err := s.load(name)
+	if err != nil {
+		return nil, err
+	}
+	return m.Runs, nil
+}
+
+// Results loads the full results of one run.
+func (s *Store) Results(name, runID string) ([]types.ScanResult, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(s.Dir, name, "runs", filepath.Base(runID)+".json"))
+	if err != nil {
+		return nil, fmt.Errorf("run %s of campaign %s: %w", runID, name, err)
+	}
+	var results []types.ScanResult
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("run %s of campaign %s: %w", runID, name, err)
+	}
+	return results, nil
+}
+
+// Summary builds the combined report of a campaign from all its runs.
+func (s *Store) Summary(name string) (*Summary, error) {
+	runs, err := s.Runs(name)
+	if err != nil {
+		return nil, err
+	}
+	all := make([][]types.ScanResult, len(runs))
+	for i, run := range runs {
+		if all[i], err = s.Results(name, run.ID); err != nil {
+			return nil, err
+		}
+	}
+	return Summarize(name, runs, all), nil
+}
+
+// List returns the names of all campaigns in the store.
+func (s *Store) List() ([]string, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() {
+			if _, err := os.Stat(filepath.Join(s.Dir, e.Name(), "campaign.json")); err == nil {
+				names = append(names, e.Name())
+			}
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// load reads a campaign's manifest.
+func (s *Store) load(name string) (*manifest, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(s.Dir, name, "campaign.json"))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("campaign %s: %w", name, err)
+	}
+	return &m, nil
+}
+
+// save writes a campaign's manifest atomically.
+func (s *Store) save(m *manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(s.Dir, m.Name, "campaign.json")
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
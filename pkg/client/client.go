@@ -0,0 +1,364 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+// maxRetryAfter caps how long a server's Retry-After header makes a retry wait.
+const maxRetryAfter = time.Minute
+
+// Client is a typed client for the Hx-H.A.W.K.S API server.
+type Client struct {
+	BaseURL      string        // e.g. "http://localhost:7171"
+	APIKey       string        // Sent as a Bearer token when the server requires API keys
+	HTTPClient   *http.Client  // Underlying HTTP client
+	MaxRetries   int           // Retries for 429 responses, and for network errors and 5xx responses to idempotent requests
+	RetryWait    time.Duration // Initial wait between retries (doubled each attempt, at least the server's Retry-After)
+	PollInterval time.Duration // Interval used while waiting on a job
+}
+
+// APIError is returned when the server answers with a non-success status code.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("api error (status %d): %s", e.StatusCode, e.Message)
+}
+
+// ErrJobNotFound is returned when the server does not know the requested job ID.
+var ErrJobNotFound = errors.New("job not found")
+
+// jobError turns the 404 of a job endpoint into ErrJobNotFound.
+func jobError(err error) error {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return ErrJobNotFound
+	}
+	return err
+}
+
+// New creates a client for the API server at baseURL.
+func New(baseURL string) *Client {
+	return &Client{
+		BaseURL:      strings.TrimRight(baseURL, "/"),
+		HTTPClient:   &http.Client{Timeout: 30 * time.Second},
+		MaxRetries:   3,
+		RetryWait:    500 * time.Millisecond,
+		PollInterval: 2 * time.Second,
+	}
+}
+
+// StartScan submits a new scan job and returns its job ID.
+func (c *Client) StartScan(ctx context.Context, req types.ScanRequest) (string, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+
+	var resp struct {
+		JobID string `json:"job_id"`
+	}
+	if _, err := c.do(ctx, http.MethodPost, "/scan/start", body, &resp); err != nil {
+		return "", err
+	}
+	if resp.JobID == "" {
+		return "", errors.New("server returned an empty job ID")
+	}
+	return resp.JobID, nil
+}
+
+// AddTargets appends URLs to a running job's queue and returns the server's answer.
+func (c *Client) AddTargets(ctx context.Context, jobID string, urls []string) (*types.AddTargetsResponse, error) {
+	body, err := json.Marshal(map[string][]string{"urls": urls})
+	if err != nil {
+		return nil, err
+	}
+
+	var resp types.AddTargetsResponse
+	if _, err := c.do(ctx, http.MethodPost, "/scan/"+url.PathEscape(jobID)+"/targets", body, &resp); err != nil {
+		return nil, jobError(err)
+	}
+	return &resp, nil
+}
+
+// GetStatus returns the current status of a job (without results).
+func (c *Client) GetStatus(ctx context.Context, jobID string) (*types.JobStatus, error) {
+	var status types.JobStatus
+	if _, err := c.do(ctx, http.MethodGet, "/scan/status/"+url.PathEscape(jobID), nil, &status); err != nil {
+		return nil, jobError(err)
+	}
+	return &status, nil
+}
+
+// GetResult returns the job including its results. The boolean reports
+// whether the job has finished; unfinished jobs carry the results so far.
+func (c *Client) GetResult(ctx context.Context, jobID string) (*types.JobStatus, bool, error) {
+	var job types.JobStatus
+	code, err := c.do(ctx, http.MethodGet, "/scan/result/"+url.PathEscape(jobID), nil, &job)
+	if err != nil {
+		return nil, false, jobError(err)
+	}
+	return &job, code == http.StatusOK, nil
+}
+
+// WaitForCompletion polls the job until it reaches a final state and returns it with results.
+// It polls the status, which carries no results, so a long job isn't downloaded over and over.
+func (c *Client) WaitForCompletion(ctx context.Context, jobID string) (*types.JobStatus, error) {
+	ticker := time.NewTicker(c.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		status, err := c.GetStatus(ctx, jobID)
+		if err != nil {
+			return nil, err
+		}
+		switch status.Status {
+		case "Completed", "Error", "Cancelled":
+			job, _, err := c.GetResult(ctx, jobID)
+			return job, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// StreamResults delivers the job's results on the returned channel as the
+// server adds them (see Stream). Both channels are closed once the stream
+// ends; at most one error is sent.
+func (c *Client) StreamResults(ctx context.Context, jobID string) (<-chan types.ScanResult, <-chan error) {
+	results := make(chan types.ScanResult)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(results)
+		defer close(errc)
+
+		_, err := c.Stream(ctx, jobID, func(r types.ScanResult) error {
+			select {
+			case results <- r:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil {
+			errc <- err
+		}
+	}()
+
+	return results, errc
+}
+
+// Stream calls fn with each of the job's results as the server adds them
+// (Server-Sent Events from GET /scan/stream/{id}) and returns the final
+// status once the job has finished. A dropped stream is resumed after the
+// last result received, up to MaxRetries times in a row; an error from fn
+// ends the stream.
+func (c *Client) Stream(ctx context.Context, jobID string, fn func(types.ScanResult) error) (*types.JobStatus, error) {
+	received := 0
+	wait := c.RetryWait
+	for failures := 0; ; failures++ {
+		before := received
+		status, retry, err := c.streamOnce(ctx, jobID, &received, fn)
+		switch {
+		case err == nil:
+			return status, nil
+		case ctx.Err() != nil:
+			return nil, ctx.Err()
+		case !retry:
+			return nil, err
+		}
+		if received > before {
+			failures, wait = 0, c.RetryWait // Got somewhere, start counting again
+		}
+		if failures >= c.MaxRetries {
+			return nil, fmt.Errorf("result stream failed after %d attempts: %w", failures+1, err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
+
+// streamOnce reads the event stream of a job from the result after the
+// first *received ones on, counting the results passed to fn in *received.
+// retry reports whether reconnecting may help.
+func (c *Client) streamOnce(ctx context.Context, jobID string, received *int, fn func(types.ScanResult) error) (status *types.JobStatus, retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.BaseURL+"/scan/stream/"+url.PathEscape(jobID), nil)
+	if err != nil {
+		return nil, false, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if *received > 0 {
+		req.Header.Set("Last-Event-ID", strconv.Itoa(*received))
+	}
+	if c.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	}
+	streamClient := *c.HTTPClient
+	streamClient.Timeout = 0 // The stream lasts as long as the job; ctx ends it
+	resp, err := streamClient.Do(req)
+	if err != nil {
+		return nil, true, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		apiErr := &APIError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(data))}
+		return nil, resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, jobError(apiErr)
+	}
+
+	r := bufio.NewReader(resp.Body)
+	var event, id string
+	var data bytes.Buffer
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, true, fmt.Errorf("result stream ended before the job finished: %w", err)
+		}
+		line = strings.TrimRight(line, "\r\n")
+		switch {
+		case line == "": // End of an event
+			switch event {
+			case "result":
+				var result types.ScanResult
+				if err := json.Unmarshal(data.Bytes(), &result); err != nil {
+					return nil, false, fmt.Errorf("decoding result: %w", err)
+				}
+				if err := fn(result); err != nil {
+					return nil, false, err
+				}
+				if n, err := strconv.Atoi(id); err == nil {
+					*received = n
+				} else {
+					*received++
+				}
+			case "complete":
+				var final types.JobStatus
+				if err := json.Unmarshal(data.Bytes(), &final); err != nil {
+					return nil, false, fmt.Errorf("decoding status: %w", err)
+				}
+				return &final, false, nil
+			}
+			event, id = "", ""
+			data.Reset()
+		case strings.HasPrefix(line, ":"): // Keep-alive comment
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "id:"):
+			id = strings.TrimSpace(strings.TrimPrefix(line, "id:"))
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+}
+
+// do performs a request with retries and decodes a JSON response into out.
+// It returns the final HTTP status code. 429 answers are always retried;
+// network errors and 5xx answers only for idempotent methods, since a POST
+// may have been acted on (e.g. started a job) before it failed.
+func (c *Client) do(ctx context.Context, method, path string, body []byte, out interface{}) (int, error) {
+	wait := c.RetryWait
+	var lastErr error
+	idempotent := method != http.MethodPost && method != http.MethodPatch
+
+	for attempt := 0; attempt <= c.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			case <-time.After(wait):
+			}
+			wait *= 2
+		}
+
+		var reader io.Reader
+		if body != nil {
+			reader = bytes.NewReader(body)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, reader)
+		if err != nil {
+			return 0, err
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		req.Header.Set("Accept", "application/json")
+		if c.APIKey != "" {
+			req.Header.Set("Authorization", "Bearer "+c.APIKey)
+		}
+
+		resp, err := c.HTTPClient.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return 0, ctx.Err()
+			}
+			if !idempotent {
+				return 0, err
+			}
+			lastErr = err
+			continue // Network error, retry
+		}
+
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			if !idempotent {
+				return resp.StatusCode, err
+			}
+			lastErr = err
+			continue
+		}
+
+		switch {
+		case resp.StatusCode == http.StatusTooManyRequests || (resp.StatusCode >= 500 && idempotent):
+			lastErr = &APIError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(data))}
+			// A rate-limited server says when to come back
+			if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+				if after := time.Duration(secs) * time.Second; after > wait {
+					wait = after
+				}
+				if wait > maxRetryAfter {
+					wait = maxRetryAfter
+				}
+			}
+			continue
+		case resp.StatusCode >= 400:
+			return resp.StatusCode, &APIError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(data))}
+		}
+
+		if out != nil && len(data) > 0 {
+			if err := json.Unmarshal(data, out); err != nil {
+				return resp.StatusCode, fmt.Errorf("decoding response: %w", err)
+			}
+		}
+		return resp.StatusCode, nil
+	}
+
+	return 0, fmt.Errorf("request failed after %d attempts: %w", c.MaxRetries+1, lastErr)
+}
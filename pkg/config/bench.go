@@ -0,0 +1,45 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// BenchConfig holds the settings for the `bench` subcommand.
+type BenchConfig struct {
+	RulesFile  string
+	CorpusDir  string
+	Keywords   []string // Extra keywords from --ck, benchmarked as one rule
+	Iterations int
+}
+
+// ParseBenchFlags parses the arguments of `hx-hawks bench`.
+func ParseBenchFlags(args []string) (*BenchConfig, error) {
+	cfg := &BenchConfig{}
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	fs.StringVar(&cfg.RulesFile, "rules", "", "Rules YAML file to benchmark")
+	fs.StringVar(&cfg.CorpusDir, "corpus", "", "Directory of response bodies to match against (required)")
+	keywordsRaw := fs.String("ck", "", "Comma-separated keywords to benchmark alongside the rules")
+	fs.IntVar(&cfg.Iterations, "iterations", 5, "Passes over the corpus per measurement")
+	fs.Parse(args)
+
+	if cfg.CorpusDir == "" {
+		return nil, fmt.Errorf("%w: corpus directory (--corpus) is required", ErrUsage)
+	}
+	if cfg.RulesFile == "" && *keywordsRaw == "" {
+		return nil, fmt.Errorf("%w: provide rules (--rules) and/or keywords (--ck) to benchmark", ErrUsage)
+	}
+	if cfg.Iterations <= 0 {
+		log.Println("[!] Invalid iterations value, defaulting to 5")
+		cfg.Iterations = 5
+	}
+	for _, k := range strings.Split(*keywordsRaw, ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			cfg.Keywords = append(cfg.Keywords, k)
+		}
+	}
+	cfg.Keywords, _ = ParseKeywordSeverities(cfg.Keywords) // Severities don't change matching cost
+	return cfg, nil
+}
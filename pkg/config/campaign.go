@@ -0,0 +1,72 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+)
+
+// CampaignConfig holds the settings for the `campaign` subcommand.
+type CampaignConfig struct {
+	Action string   // list, show, add or diff
+	Name   string   // Campaign name (all actions but list)
+	Dir    string   // Campaign store directory ("" = ~/.hx-hawks/campaigns)
+	Label  string   // add: label of the recorded run (tool/profile)
+	Files  []string // add: reports to record, one run each
+	Output string   // show: write the combined report to this JSON file
+
+	// diff: runs to compare (default: the last two) and CI gating, as for `hx-hawks diff`
+	From        string
+	To          string
+	ChangesFile string
+	FailOn      string
+}
+
+// campaignUsage is printed for missing or unknown actions.
+const campaignUsage = `usage:
+    hx-hawks campaign list [--dir d]
+    hx-hawks campaign show [--dir d] [-o summary.json] <name>
+    hx-hawks campaign add  [--dir d] [--label tool] <name> report.json...
+    hx-hawks campaign diff [--dir d] [--from run] [--to run] [--changes-file f] [--fail-on regression|any|never] <name>`
+
+// ParseCampaignFlags parses the arguments of `hx-hawks campaign <action> ...`.
+// Callers should exit with DiffExitError on error, like the diff subcommand.
+func ParseCampaignFlags(args []string) (*CampaignConfig, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("%w, %s", ErrUsage, campaignUsage)
+	}
+	cfg := &CampaignConfig{Action: args[0]}
+	fs := flag.NewFlagSet("campaign "+cfg.Action, flag.ExitOnError)
+	fs.StringVar(&cfg.Dir, "dir", "", "Campaign store directory (default ~/.hx-hawks/campaigns)")
+	switch cfg.Action {
+	case "list":
+	case "show":
+		fs.StringVar(&cfg.Output, "o", "", "Write the combined campaign report to this JSON file")
+	case "add":
+		fs.StringVar(&cfg.Label, "label", "", "Label for the recorded runs, e.g. the tool or profile")
+	case "diff":
+		fs.StringVar(&cfg.From, "from", "", "Run ID to compare from (default: second-to-last run)")
+		fs.StringVar(&cfg.To, "to", "", "Run ID to compare to (default: last run)")
+		fs.StringVar(&cfg.ChangesFile, "changes-file", "", "Write new/fixed/changed counts and details to this JSON file")
+		fs.StringVar(&cfg.FailOn, "fail-on", "regression", "When to exit 1: regression, any or never")
+	default:
+		return nil, fmt.Errorf("%w, %s", ErrUsage, campaignUsage)
+	}
+	fs.Parse(args[1:])
+
+	rest := fs.Args()
+	switch {
+	case cfg.Action == "list":
+	case cfg.Action == "add" && len(rest) >= 2:
+		cfg.Name, cfg.Files = rest[0], rest[1:]
+	case cfg.Action != "add" && len(rest) == 1:
+		cfg.Name = rest[0]
+	default:
+		return nil, fmt.Errorf("%w, %s", ErrUsage, campaignUsage)
+	}
+	switch cfg.FailOn {
+	case "", "regression", "any", "never":
+	default:
+		return nil, errInvalidFailOn(cfg.FailOn)
+	}
+	return cfg, nil
+}
@@ -1,11 +1,23 @@
 package config
 
 import (
+	"crypto/x509"
 	"flag"
+	"fmt"
 	"log"
+	"net/http"
+	"net/url"
 	"os"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/nxneeraj/hx-hawks/pkg/campaign"
+	"github.com/nxneeraj/hx-hawks/pkg/fingerprint"
+	"github.com/nxneeraj/hx-hawks/pkg/notify"
+	"github.com/nxneeraj/hx-hawks/pkg/rules"
+	"github.com/nxneeraj/hx-hawks/pkg/types"
 )
 
 // Config holds all the configuration settings for the scanner.
@@ -16,52 +28,305 @@ type Config struct {
 	OutputResponse string
 	OutputAll      string
 	OutputAllJSON  string
+	OutputInteresting string // JSON file for the "interesting" tier (leads that aren't findings)
+	OutputJUnit    string // JUnit XML report: one test case per URL, failing when vulnerable
+	OutputMarkdown string // Markdown findings report with evidence snippets
+	OutputCSV      string // CSV of all results, one row per URL (honours --fields)
+	OutputHTML     string // Self-contained HTML findings report
+	OutputJSONL    string // JSON Lines file written as results arrive (honours --fields)
+	Append         bool   // Add to existing output files instead of overwriting them (--append)
+	OutputTemplate string // Go template each result is printed with instead of the colored output (-o-template)
+	ESURL          string // Elasticsearch/OpenSearch cluster results are bulk-indexed into as they arrive (--es-url)
+	ESIndex        string // Index for --es-url documents
+	StoreResponses string // Directory for per-URL request/response files (--store-responses)
+	Fields         []string // JSON keys written to JSON outputs (--fields); empty = default layout
+	Campaign       string // Record the scan as a run of this campaign
+	CampaignLabel  string // Label of the recorded run (tool/profile)
+	CampaignDir    string // Campaign store directory ("" = ~/.hx-hawks/campaigns)
 	KeywordsRaw    string // Raw comma-separated keywords
 	Keywords       []string // Parsed keywords
+	KeywordSeverity map[string]string // Keyword -> severity, from --ck "keyword:severity"
+	KeywordScope   rules.Scope  // Hosts/technologies where --ck keywords count (--ck-hosts, --ck-tech)
+	RulesFile      string       // YAML rules file
+	Recipes        []string     // Built-in recipes (--recipe)
+	Selectors      []string     // Ad-hoc CSS selectors (--match-selector), each becomes a rule
+	JSONPaths      []string     // Ad-hoc JSONPath expressions (--match-jsonpath), each becomes a rule
+	Rules          []rules.Rule // Compiled rules from RulesFile and Recipes
+	DedupeRules    bool         // Remove redundant keywords/rules instead of only warning
+	Entropy        bool         // Report high-entropy strings (possible tokens/keys) as low-confidence matches
+	EntropyThreshold float64    // Minimum Shannon entropy in bits per character (--entropy-threshold)
+	TechDetect     bool                      // Tag results with detected technologies
+	TechRulesFile  string                    // Extra fingerprints (YAML) added to the built-in ones
+	Fingerprints   []fingerprint.Fingerprint // Compiled fingerprints used when TechDetect is set
+	MatchCodes     []int    // Only treat responses with these status codes as vulnerable
+	FilterCodes    []int    // Drop responses with these status codes before matching
+	MatchSizes     []SizeRange // Only treat responses within these body sizes as vulnerable
+	FilterSizes    []SizeRange // Drop responses within these body sizes before matching
 	Threads        int
-	Timeout        time.Duration
+	MaxCPUs        int // Upper bound for GOMAXPROCS (--max-cpus); 0 = container/host limit
+	Timeout        time.Duration // Total budget per request, redirects and body included
+	ConnectTimeout time.Duration // TCP connect budget (0 = bounded by Timeout)
+	TLSTimeout     time.Duration // TLS handshake budget (0 = bounded by Timeout)
+	HeaderTimeout  time.Duration // Budget for the response headers once the request is sent (0 = bounded by Timeout)
+	ReadTimeout    time.Duration // Budget for reading the body once the headers arrived (0 = bounded by Timeout)
 	ScanDuration   time.Duration // Max duration for the entire scan
 	Delay          time.Duration // Delay between requests *per worker*
+	Heartbeat      time.Duration // Interval between heartbeat log lines (0 = off)
+	StallTimeout   time.Duration // No results for this long while workers are busy = stalled (0 = off)
+	StallAbort     bool          // Cancel the in-flight requests of stalled hosts so workers move on
+	Method         string // HTTP method (default GET, or POST when Data is set)
+	Data           []byte // Request body sent to every target (--data/--data-file)
+	ContentType    string // Content-Type of Data (inferred when empty)
+	Headers        http.Header // Extra headers sent with every request (-H)
+	VHosts         []string    // Host headers tried against every target (--vhost-list)
+	AuthBasic      string // "user:pass" sent as HTTP Basic auth (--auth-basic)
+	AuthBearer     string // Token sent as "Authorization: Bearer <token>" (--auth-bearer)
+	Cookies        []*http.Cookie // Cookies sent with every request (--cookie)
+	CookieFile     string         // Netscape cookie file (--cookie-file)
+	FileCookies    []FileCookie   // Parsed CookieFile, sent per host via the cookie jar
+	CookieJar      bool           // Keep cookies set by the targets during the scan (per host)
 	Verbose        bool
+	TLSVerify      bool           // Fail requests whose certificate does not verify (--tls-verify)
+	CACert         string         // Extra PEM CA bundle trusted besides the system roots (--ca-cert)
+	RootCAs        *x509.CertPool // System roots plus CACert; nil = system roots
+	PlainLog       bool // One structured line per result: no previews, emoji or colors (--plain-log)
+	Silent         bool // Print only vulnerable URLs on stdout; no banner, logs or progress (--silent)
+	NoColor        bool   // No ANSI colors in the output (--no-color, or the NO_COLOR environment variable)
+	Theme          string // YAML file of terminal colors per role (--theme)
+	SkipBinary     bool // Skip matching on non-text content (images, PDFs, binaries)
+	LoginRedirects string // Findings on login/SSO pages reached via redirect: off, downgrade or suppress
+	CacheFile      string // ETag/Last-Modified cache for conditional requests across runs
+	Crawl          bool   // Queue same-host links found on scanned pages (--crawl)
+	CrawlDepth     int    // Maximum clicks from a seed URL (--depth)
+	CrawlMaxPages  int    // Maximum pages added by the crawl (0 = unlimited)
+	CrawlScope     string // "host" (default) or "subdomains"
+	CrawlExclude   *regexp.Regexp // Links matching it are not followed
+	Sitemap        bool   // Add the paths in each host's robots.txt and sitemaps to the scan (--sitemap)
+	Shuffle        bool   // Scan targets in random order (--shuffle)
+	Interleave     bool   // Round-robin targets across hosts (--interleave)
+	ResumeFile     string // State file for checkpoints; an existing one is resumed (--resume)
+	CheckpointInterval time.Duration // How often the --resume state file is saved
+	NotifyWebhook  string        // POST vulnerable results to this URL as they are found (--notify-webhook)
+	NotifySlack    string        // Slack incoming webhook URL (--notify-slack)
+	NotifyDiscord  string        // Discord channel webhook URL (--notify-discord)
+	NotifyTelegramToken string   // Telegram bot token (--notify-telegram-token)
+	NotifyTelegramChat  string   // Telegram chat ID (--notify-telegram-chat)
+	NotifyConfig   string        // YAML file of notification providers (--notify-config)
+	NotifyEvents   []string      // Events notified: findings, complete (nil = all)
+	Notifiers      []notify.Provider // Providers built from the flags and NotifyConfig
+	NotifyBatch    int           // Results per notification
+	NotifyInterval time.Duration // Longest a result waits for its notification batch to fill
+	FullBody       bool   // Always download complete bodies (no early stop after all keywords match)
+	MaxBodySize    int64  // Maximum bytes read from a response body (0 = unlimited)
+	DedupeResponses bool  // Skip matching/storing bodies identical to an earlier response
+	Evasion        bool          // Switch hosts that keep blocking to the evasion profile and retry
+	EvasionThreshold int         // Consecutive blocked responses before a host gets the evasion profile
+	EvasionDelay   time.Duration // Minimum per-host interval under evasion (jittered +/-50%)
+	Proxy          *url.URL      // Proxy for every request (--proxy); nil = environment settings
+	Retries        int           // Retries of requests that got no response (--retries)
+	MaxRedirects   int           // Redirects followed per request (0 = 10)
+	NoRedirects    bool          // Report redirect responses instead of following them (--no-redirects)
+	ProxyList      string        // File of alternate proxies rotated under evasion
+	Proxies        []*url.URL    // Parsed ProxyList
+	Resolvers      []string      // Nameservers (ip:port or DoH URLs) used instead of the system resolver (--resolvers)
+	Resolve        map[string]string // Host -> IP used without asking DNS (--resolve host:ip)
+	CertPins       []CertPin // Expected leaf certificate fingerprints per host pattern (--pin)
+	Calibrate      bool   // Probe a sample of targets before scanning and recommend settings
+	CalibrateApply bool   // Apply the recommended settings automatically
+	CalibrateSample int   // Number of targets probed during calibration
 	NoLimit        bool // (Concept - implementation might vary)
+	Logger         *log.Logger // Destination for worker logs (nil = standard logger); API jobs log to their own buffer
+	Inputs         *types.InputDigest // Digest of the targets and rules, recorded in every result
 	API            bool
 	APIPort        int
+	MaxJobURLs     int    // API mode: maximum URLs per job, including ones added while running (0 = unlimited)
+	APIMaxScans    int    // API mode: scans run at once, later jobs are queued (0 = unlimited)
+	APIMaxThreads  int    // API mode: most workers a job may ask for with "threads" (0 = unlimited)
+	APIMaxRequest  int64  // API mode: maximum JSON request body size in bytes (0 = unlimited)
+	APIMaxUpload   int64  // API mode: maximum target-list upload size in bytes (0 = unlimited)
+	APIRateLimit   int    // API mode: requests per minute allowed per client (0 = unlimited)
+	APIExportDir   string // API mode: write finished jobs here on shutdown or deletion
+	APILinkTTL     time.Duration // API mode: lifetime of signed result-download links (0 = no links)
+	APIJobTTL      time.Duration // API mode: how long finished jobs are kept in memory (0 = until deleted)
+	APIDB          string // API mode: BoltDB file keeping jobs, results and templates across restarts ("" = memory only)
+	APILinkSecret  string // API mode: HMAC key of download links ("" = random per start)
+	APICallbackSecret string // API mode: HMAC key of job callbacks without their own callback_secret ("" = unsigned)
+	APIPublicURL   string // API mode: base URL used in download links ("" = request host)
+	APIKeys        []APIKey // API mode: keys accepted from clients (none = no authentication); with --remote, the first is sent
+	APIKeysFile    string   // File of API keys, one "name:key" per line (--api-keys-file)
+	APITLSCert     string // API mode: PEM certificate (chain) to serve HTTPS with
+	APITLSKey      string // API mode: PEM private key of APITLSCert
+	APITLSSelfSigned bool // API mode: serve HTTPS with a certificate generated at start
+	APICORSOrigins []string // API mode: browser origins allowed to call the API ("*" = any; none = no CORS)
+	APICORSMethods []string // API mode: methods allowed in CORS preflight answers
+	APICORSHeaders []string // API mode: request headers allowed in CORS preflight answers
+	Remote         string // Base URL of a remote API server to run the scan on
+	Detach         bool   // Submit the remote scan and exit without waiting
+	Attach         string // Job ID of a remote scan to reattach to
 	// Weight         int // Placeholder for future rate limiting logic
 }
 
-// ParseFlags parses command-line flags and returns a Config struct.
-func ParseFlags() *Config {
-	cfg := &Config{}
+// ParseFlags parses command-line flags and returns a Config struct. Errors
+// wrap ErrUsage, ErrInputFile or ErrInvalidRule, or are a *FlagError.
+func ParseFlags() (*Config, error) {
+	cfg := ScanDefaults() // Flag defaults, shared with API scans
 
 	flag.StringVar(&cfg.InputFile, "f", "", "Path to input file with list of target URLs (required)")
 	flag.StringVar(&cfg.OutputFile, "o", "", "Output file to store vulnerable URLs only (plain text)")
 	flag.StringVar(&cfg.OutputJSON, "o-json", "", "Output matched data in JSON format (url, matched_keywords, response)")
 	flag.StringVar(&cfg.OutputResponse, "o-response", "", "Output matched URLs along with their full HTTP response")
 	flag.StringVar(&cfg.OutputAll, "o-all", "", "Output all scanned URLs (vulnerable + safe) with basic info")
+	flag.StringVar(&cfg.OutputInteresting, "o-interesting", "", "Output results that aren't vulnerable but are worth a manual look (low-confidence matches, 401/403/5xx, WAF blocks) in JSON format")
 	flag.StringVar(&cfg.OutputAllJSON, "o-all-json", "", "Full JSON report of all URLs, matched keywords, response, status, IP, timestamp, etc.")
+	flag.StringVar(&cfg.OutputJUnit, "o-junit", "", "JUnit XML report for CI: one test case per URL, failing when vulnerable")
+	flag.StringVar(&cfg.StoreResponses, "store-responses", "", "Write each response (request and response headers, raw body) to a file in this directory named by URL hash; results reference it in response_file instead of carrying the body")
+	flag.StringVar(&cfg.OutputTemplate, "o-template", "", "Print each result as one line rendered by this Go template (or template file), e.g. '{{.URL}} {{.StatusCode}} {{join .MatchedKeywords \",\"}}'")
+	flag.StringVar(&cfg.OutputJSONL, "o-jsonl", "", "Stream every result to this JSON Lines file as it arrives, one object per line (survives crashes; honours --fields)")
+	flag.StringVar(&cfg.ESURL, "es-url", "", "Bulk-index every result into this Elasticsearch/OpenSearch cluster as it arrives, e.g. https://user:pass@es:9200 (honours --fields)")
+	flag.StringVar(&cfg.ESIndex, "es-index", cfg.ESIndex, "Index for --es-url documents")
+	flag.StringVar(&cfg.OutputMarkdown, "o-md", "", "Markdown findings report (summary table, per-finding sections with evidence snippets)")
+	flag.StringVar(&cfg.OutputCSV, "o-csv", "", "CSV of all scanned URLs, one row each (columns from --fields, default url, status, verdict, severity, matches, title, ...)")
+	flag.StringVar(&cfg.OutputHTML, "o-html", "", "Self-contained HTML findings report (findings with evidence, then every scanned URL)")
+	flag.BoolVar(&cfg.Append, "append", false, "Add to existing output files instead of overwriting them: text and JSONL files are appended to, JSON arrays and JUnit reports merged, Markdown reports added below, CSV rows added without a new header (for scanning a target list in chunks)")
+	fields := flag.String("fields", "", "Comma-separated fields for JSON outputs, e.g. url,status,severity,keywords,ip (default: all)")
+	flag.StringVar(&cfg.Campaign, "campaign", "", "Record this scan as a run of the named campaign (see `hx-hawks campaign`)")
+	flag.StringVar(&cfg.CampaignLabel, "campaign-label", "", "Label for the campaign run, e.g. the profile (default \"hx-hawks\")")
+	flag.StringVar(&cfg.CampaignDir, "campaign-dir", "", "Campaign store directory (default ~/.hx-hawks/campaigns)")
 	flag.StringVar(&cfg.KeywordsRaw, "ck", "", "Comma-separated list of keywords to search in the response body (required)")
-	flag.IntVar(&cfg.Threads, "threads", 10, "Number of concurrent goroutines/workers")
-	timeoutSec := flag.Int("timeout", 10, "Timeout for each HTTP request in seconds")
+	ckHosts := flag.String("ck-hosts", "", "Comma-separated host patterns where --ck keywords count, e.g. '*.example.com' (default: every host)")
+	ckTech := flag.String("ck-tech", "", "Comma-separated technologies where --ck keywords count, e.g. WordPress (enables --tech-detect)")
+	flag.StringVar(&cfg.RulesFile, "rules", "", "YAML rules file with named keyword/regex signatures")
+	flag.BoolVar(&cfg.Entropy, "entropy", false, "Report high-entropy strings (possible tokens/keys) as low-confidence matches, even without a keyword match")
+	flag.Float64Var(&cfg.EntropyThreshold, "entropy-threshold", cfg.EntropyThreshold, "Minimum entropy in bits per character for --entropy (hex-only strings need 2/3 of it)")
+	flag.BoolVar(&cfg.DedupeRules, "dedupe-rules", false, "Remove duplicate/subsumed keywords and rules with identical matchers")
+	flag.BoolVar(&cfg.TechDetect, "tech-detect", false, "Tag each result with detected technologies (nginx, WordPress, Laravel, ...)")
+	flag.StringVar(&cfg.TechRulesFile, "tech-rules", "", "YAML file with extra technology fingerprints (implies --tech-detect)")
+	var selectors stringList
+	flag.Var(&selectors, "match-selector", "CSS selector that marks a response vulnerable when it matches an element (repeatable, e.g. 'form[action*=\"login\"]')")
+	var jsonPaths stringList
+	flag.Var(&jsonPaths, "match-jsonpath", "JSONPath expression that marks a JSON response vulnerable when it holds (repeatable, e.g. '$.debug == true')")
+	recipes := flag.String("recipe", "", "Comma-separated built-in recipes: "+strings.Join(rules.RecipeNames(), "|"))
+	matchCodes := flag.String("match-code", "", "Comma-separated status codes required for a keyword match to count (e.g. 200,500)")
+	filterCodes := flag.String("filter-code", "", "Comma-separated status codes to discard without keyword matching (e.g. 404,403)")
+	matchSizes := flag.String("match-size", "", "Body sizes required for a keyword match to count (e.g. >1024,100-2000)")
+	filterSizes := flag.String("filter-size", "", "Body sizes to discard without keyword matching (e.g. 4242,<100)")
+	flag.IntVar(&cfg.Threads, "threads", cfg.Threads, "Number of concurrent goroutines/workers")
+	flag.IntVar(&cfg.MaxCPUs, "max-cpus", 0, "Use at most N CPUs (default: the container's CPU quota, or all cores)")
+	timeoutSec := flag.Int("timeout", int(cfg.Timeout/time.Second), "Timeout for each HTTP request in seconds")
+	connectSec := flag.Int("connect-timeout", 0, "TCP connect timeout in seconds (0 = bounded by --timeout)")
+	tlsSec := flag.Int("tls-timeout", int(cfg.TLSTimeout/time.Second), "TLS handshake timeout in seconds (0 = bounded by --timeout)")
+	headerSec := flag.Int("header-timeout", 0, "Seconds to wait for the response headers after sending a request (0 = bounded by --timeout)")
+	readSec := flag.Int("read-timeout", 0, "Seconds allowed for reading a body once the headers arrived, so slow-drip servers release workers early (0 = bounded by --timeout)")
 	durationSec := flag.Int("duration", 0, "Total duration to run the scan in seconds (0 for unlimited)")
 	delayMs := flag.Int("delay", 0, "Delay between requests per worker in milliseconds")
+	var headers stringList
+	flag.Var(&headers, "H", "Custom header sent with every request, \"Name: value\" (repeatable, e.g. -H \"Authorization: Bearer x\")")
+	hostHeader := flag.String("host-header", "", "Host header sent with every request while connecting to the target's own host (same as -H \"Host: <name>\")")
+	vhostList := flag.String("vhost-list", "", "Virtual host mode: file of Host names tried against every target, e.g. IPs in -f (one request per target and name)")
+	flag.StringVar(&cfg.AuthBasic, "auth-basic", "", "HTTP Basic credentials sent with every request, as user:pass")
+	flag.StringVar(&cfg.AuthBearer, "auth-bearer", "", "Bearer token sent with every request (Authorization: Bearer <token>)")
+	cookies := flag.String("cookie", "", "Cookies sent with every request, e.g. \"session=abc; theme=dark\"")
+	flag.StringVar(&cfg.CookieFile, "cookie-file", "", "Netscape/curl cookie file; cookies are sent to their own hosts only")
+	flag.BoolVar(&cfg.CookieJar, "cookie-jar", false, "Keep cookies set by targets during the scan and send them on later requests to the same host")
+	flag.StringVar(&cfg.Method, "method", "", "HTTP method to send (default GET, or POST with --data/--data-file)")
+	data := flag.String("data", "", "Request body to send to every target (e.g. '{\"debug\":true}' or 'a=1&b=2')")
+	dataFile := flag.String("data-file", "", "Read the request body from this file")
+	flag.StringVar(&cfg.ContentType, "content-type", "", "Content-Type of the request body (default: application/json if the data is JSON, else form-urlencoded)")
 	flag.BoolVar(&cfg.Verbose, "verbose", false, "Enable verbose logging")
+	flag.BoolVar(&cfg.Silent, "silent", false, "Print only vulnerable URLs to stdout, one per line: no banner, logs or progress (errors still go to stderr)")
+	flag.BoolVar(&cfg.NoColor, "no-color", os.Getenv("NO_COLOR") != "", "Disable colored output (also set by the NO_COLOR environment variable; off anyway when stdout isn't a terminal)")
+	flag.StringVar(&cfg.Theme, "theme", "", "YAML file mapping output roles (safe, vulnerable, response, keyword, warning, detail, neutral) to colors, e.g. 'vulnerable: hi-red bold'")
+	flag.BoolVar(&cfg.PlainLog, "plain-log", false, "Print each result as one key=value line without previews, emoji or colors (for journald/CloudWatch)")
+	heartbeatSec := flag.Int("heartbeat", int(cfg.Heartbeat/time.Second), "Log a heartbeat (requests done, busy workers) every N seconds (0 to disable)")
+	stallSec := flag.Int("stall-timeout", int(cfg.StallTimeout/time.Second), "Report a stall when no request finishes for N seconds while workers are busy (0 to disable)")
+	flag.BoolVar(&cfg.StallAbort, "stall-abort", false, "On a stall, abort the in-flight requests to the affected hosts so workers continue with the queue")
+	flag.BoolVar(&cfg.Calibrate, "calibrate", false, "Probe a sample of targets first and recommend thread/delay/timeout settings")
+	flag.BoolVar(&cfg.CalibrateApply, "calibrate-apply", false, "Like --calibrate, but apply the recommended settings automatically")
+	flag.IntVar(&cfg.CalibrateSample, "calibrate-sample", cfg.CalibrateSample, "Number of targets probed by --calibrate")
+	flag.BoolVar(&cfg.Crawl, "crawl", false, "Follow same-host links found in HTML responses and scan them too")
+	flag.IntVar(&cfg.CrawlDepth, "depth", cfg.CrawlDepth, "Maximum link depth from each seed URL for --crawl")
+	flag.IntVar(&cfg.CrawlMaxPages, "crawl-max", cfg.CrawlMaxPages, "Maximum pages added by --crawl (0 = unlimited)")
+	flag.StringVar(&cfg.CrawlScope, "crawl-scope", cfg.CrawlScope, "Hosts --crawl may follow links to: host (the seed's host) or subdomains (also its subdomains)")
+	crawlExclude := flag.String("crawl-exclude", "", "Regex of links --crawl must not follow, e.g. 'logout|signout'")
+	flag.BoolVar(&cfg.Sitemap, "sitemap", false, "Fetch each host's robots.txt and sitemap.xml and scan the paths they list (Disallow entries included)")
+	flag.BoolVar(&cfg.Shuffle, "shuffle", false, "Scan targets in random order, so input grouped by domain doesn't hammer one host at a time")
+	flag.BoolVar(&cfg.Interleave, "interleave", false, "Round-robin targets across hosts, so consecutive requests go to different hosts (after --shuffle, if both)")
+	flag.StringVar(&cfg.ResumeFile, "resume", "", "Save progress (remaining URLs, results so far) to this state file and, if it exists, resume the scan it records")
+	flag.StringVar(&cfg.NotifyWebhook, "notify-webhook", "", "POST each vulnerable result as JSON to this URL the moment it is found (batched, retried on failure)")
+	flag.StringVar(&cfg.NotifySlack, "notify-slack", "", "Post vulnerable results and the scan summary to this Slack incoming webhook URL")
+	flag.StringVar(&cfg.NotifyDiscord, "notify-discord", "", "Post vulnerable results and the scan summary to this Discord channel webhook URL")
+	flag.StringVar(&cfg.NotifyTelegramToken, "notify-telegram-token", "", "Telegram bot token to send vulnerable results and the scan summary with (needs --notify-telegram-chat)")
+	flag.StringVar(&cfg.NotifyTelegramChat, "notify-telegram-chat", "", "Telegram chat ID the bot sends to")
+	flag.StringVar(&cfg.NotifyConfig, "notify-config", "", "YAML file of notification providers (slack, discord, telegram, webhook) and events")
+	notifyOn := flag.String("notify-on", "", "Comma-separated events to notify: findings, complete (default: both, or the --notify-config 'on' list)")
+	flag.IntVar(&cfg.NotifyBatch, "notify-batch", cfg.NotifyBatch, "Maximum vulnerable results per notification")
+	notifyIntervalSec := flag.Int("notify-interval", int(cfg.NotifyInterval/time.Second), "Send a partial batch of vulnerable results after N seconds")
+	checkpointSec := flag.Int("checkpoint-interval", int(cfg.CheckpointInterval/time.Second), "Save the --resume state file every N seconds (0 = only when the scan stops)")
+	flag.StringVar(&cfg.CacheFile, "cache-file", "", "Store ETag/Last-Modified per URL in this file and send conditional requests on later runs")
+	maxBodySize := flag.String("max-body-size", "10MB", "Maximum response body size to download per URL (e.g. 512KB, 10MB; 0 = unlimited)")
+	flag.BoolVar(&cfg.FullBody, "full-body", false, "Always download complete bodies instead of stopping once every keyword has matched")
+	flag.BoolVar(&cfg.DedupeResponses, "dedupe-responses", false, "Mark responses whose body matches an earlier one as duplicates without re-matching or storing them")
+	flag.BoolVar(&cfg.Evasion, "evasion", false, "When a host keeps returning block pages/rate limits, lower the rate, rotate UA/proxy, add jitter and retry")
+	flag.IntVar(&cfg.EvasionThreshold, "evasion-threshold", cfg.EvasionThreshold, "Consecutive blocked responses before --evasion applies to a host")
+	evasionDelayMs := flag.Int("evasion-delay", int(cfg.EvasionDelay/time.Millisecond), "Minimum delay in milliseconds between requests to a host under --evasion")
+	proxy := flag.String("proxy", "", "Send every request through this proxy: http, https or socks5 URL (default: HTTP_PROXY/HTTPS_PROXY)")
+	flag.IntVar(&cfg.Retries, "retries", 0, "Retry a request up to N times when it gets no response (connection errors, timeouts)")
+	flag.IntVar(&cfg.MaxRedirects, "max-redirects", cfg.MaxRedirects, "Maximum redirects followed per request")
+	flag.BoolVar(&cfg.NoRedirects, "no-redirects", false, "Don't follow redirects; scan the redirect response itself")
+	flag.StringVar(&cfg.ProxyList, "proxy-list", "", "File with alternate proxy URLs (http, https, socks5) rotated under --evasion")
+	var resolve stringList
+	flag.Var(&resolve, "resolve", "Connect to host at ip without DNS, like curl --resolve: host:ip (repeatable, e.g. www.example.com:203.0.113.7)")
+	resolvers := flag.String("resolvers", "", "Comma-separated DNS resolvers used instead of the system resolver: IPs, ip:port or DoH URLs (e.g. 1.1.1.1,https://8.8.8.8/dns-query)")
+	var pins stringList
+	flag.Var(&pins, "pin", "Pin a certificate per host: host-pattern=sha256/<fingerprint> (repeatable, e.g. *.example.com=sha256/ab12...)")
+	flag.BoolVar(&cfg.TLSVerify, "tls-verify", false, "Verify TLS certificates and fail requests that don't verify (default: scan anyway and record the problem in tls_error)")
+	flag.StringVar(&cfg.CACert, "ca-cert", "", "PEM file with extra CA certificates to trust, e.g. a corporate root")
+	flag.BoolVar(&cfg.SkipBinary, "skip-binary", false, "Skip keyword matching on non-text content types (images, PDFs, binaries)")
+	flag.StringVar(&cfg.LoginRedirects, "login-redirects", cfg.LoginRedirects, "Findings on login/SSO pages reached via redirect: off, downgrade (rules to info) or suppress (not vulnerable)")
 	flag.BoolVar(&cfg.NoLimit, "no-limit", false, "Disable internal limits (conceptual)")
 	flag.BoolVar(&cfg.API, "api", false, "Enable embedded API server")
 	flag.IntVar(&cfg.APIPort, "port", 7171, "Port for the API server")
+	flag.IntVar(&cfg.APIMaxScans, "api-max-scans", 4, "API mode: scans run at the same time; further jobs wait in the Queued state and start in order (0 = unlimited)")
+	flag.IntVar(&cfg.APIMaxThreads, "api-max-threads", 100, "API mode: most workers one job may run; larger \"threads\" values are lowered to this (0 = unlimited)")
+	flag.IntVar(&cfg.MaxJobURLs, "max-job-urls", 0, "API mode: maximum URLs per job, including targets added to running jobs (0 = unlimited)")
+	apiMaxRequest := flag.String("api-max-request-size", "10MB", "API mode: maximum body size of requests such as POST /scan/start (e.g. 512KB, 10MB; 0 = unlimited)")
+	apiMaxUpload := flag.String("api-max-upload-size", "100MB", "API mode: maximum size of target lists uploaded to POST /scan/upload (0 = unlimited)")
+	flag.IntVar(&cfg.APIRateLimit, "api-rate-limit", 0, "API mode: requests per minute allowed per client (API key, or IP without keys), bursting up to a minute's worth; over it gets 429 (0 = unlimited)")
+	flag.StringVar(&cfg.APIExportDir, "api-export-dir", "", "API mode: on shutdown or job deletion, write finished jobs' results to <dir>/<jobID>.json and .txt")
+	flag.StringVar(&cfg.APIDB, "api-db", "", "API mode: keep jobs, their results and templates in this BoltDB file so they survive restarts; finished jobs' results are read from it when asked for (default: memory only)")
+	jobTTLSec := flag.Int("api-job-ttl", 0, "API mode: evict finished jobs and their results N seconds after they end, exporting them to --api-export-dir first (0 = keep until DELETE /scan/job/{id})")
+	linkTTLSec := flag.Int("api-link-ttl", 0, "API mode: give finished jobs a signed result-download link valid for N seconds (0 = no links)")
+	flag.StringVar(&cfg.APILinkSecret, "api-link-secret", "", "API mode: secret used to sign download links (default: random, links end on restart)")
+	flag.StringVar(&cfg.APICallbackSecret, "api-callback-secret", os.Getenv("HX_HAWKS_CALLBACK_SECRET"), "API mode: secret signing the callback_url webhooks of jobs without their own callback_secret, in X-Hawks-Signature (default $HX_HAWKS_CALLBACK_SECRET; unset = unsigned)")
+	apiKeys := flag.String("api-key", os.Getenv("HX_HAWKS_API_KEYS"), "API mode: comma-separated API keys clients must send (Authorization: Bearer <key> or X-API-Key), each optionally named for the logs as name:key; with --remote, the key sent to the server (default $HX_HAWKS_API_KEYS)")
+	flag.StringVar(&cfg.APIKeysFile, "api-keys-file", "", "API mode: file of API keys, one key or name:key per line (# comments)")
+	flag.StringVar(&cfg.APITLSCert, "api-tls-cert", "", "API mode: serve HTTPS with this PEM certificate (chain); needs --api-tls-key")
+	flag.StringVar(&cfg.APITLSKey, "api-tls-key", "", "API mode: PEM private key of --api-tls-cert")
+	flag.BoolVar(&cfg.APITLSSelfSigned, "api-tls-self-signed", false, "API mode: serve HTTPS with a self-signed certificate generated at start (its fingerprint is logged)")
+	corsOrigins := flag.String("api-cors-origins", "", "API mode: comma-separated browser origins allowed to call the API, e.g. https://ui.example.com (* = any; default: none)")
+	corsMethods := flag.String("api-cors-methods", "GET,POST,DELETE,OPTIONS", "API mode: methods allowed for --api-cors-origins")
+	corsHeaders := flag.String("api-cors-headers", "Authorization,Content-Type,X-API-Key,Last-Event-ID", "API mode: request headers allowed for --api-cors-origins")
+	flag.StringVar(&cfg.APIPublicURL, "api-public-url", "", "API mode: base URL put in download links, e.g. https://scanner.example.com (default: request host)")
+	flag.StringVar(&cfg.Remote, "remote", "", "Run the scan on a remote API server (e.g. https://hawks.internal:7171)")
+	flag.BoolVar(&cfg.Detach, "detach", false, "With --remote, submit the scan and exit without streaming results")
+	flag.StringVar(&cfg.Attach, "attach", "", "With --remote, reattach to an existing job ID instead of starting a new scan")
 	// flag.IntVar(&cfg.Weight, "weight", 1, "Request weight for rate limiting (future)")
 
 	flag.Parse()
 
 	// Validation and Defaults
-	if cfg.InputFile == "" && !cfg.API { // Input file required for CLI mode
-		log.Fatal("[-] Input file path (-f) is required for CLI mode")
+	if cfg.Attach != "" && cfg.Remote == "" {
+		return nil, fmt.Errorf("%w: --attach requires --remote", ErrUsage)
+	}
+	if cfg.InputFile == "" && !cfg.API && cfg.Attach == "" { // Input file required for CLI mode
+		return nil, fmt.Errorf("%w: input file path (-f) is required for CLI mode", ErrUsage)
 	}
-	if cfg.KeywordsRaw == "" && !cfg.API { // Keywords required for CLI mode (can be passed via API later)
-		log.Fatal("[-] Custom keywords (--ck) are required")
+	if cfg.KeywordsRaw == "" && cfg.RulesFile == "" && *recipes == "" && len(selectors) == 0 && len(jsonPaths) == 0 && !cfg.Entropy && !cfg.API && cfg.Attach == "" { // Keywords required for CLI mode (can be passed via API later)
+		return nil, fmt.Errorf("%w: custom keywords (--ck), a rules file (--rules), a recipe (--recipe), a selector (--match-selector), a JSONPath (--match-jsonpath) or --entropy is required", ErrUsage)
 	}
 	if cfg.InputFile != "" {
-		if _, err := os.Stat(cfg.InputFile); os.IsNotExist(err) {
-			log.Fatalf("[-] Input file does not exist: %s", cfg.InputFile)
+		if _, err := os.Stat(cfg.InputFile); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInputFile, err)
 		}
 	}
 
@@ -70,6 +335,21 @@ func ParseFlags() *Config {
 		*timeoutSec = 10
 	}
 	cfg.Timeout = time.Duration(*timeoutSec) * time.Second
+	for _, t := range []struct {
+		flag string
+		sec  int
+		dst  *time.Duration
+	}{
+		{"--connect-timeout", *connectSec, &cfg.ConnectTimeout},
+		{"--tls-timeout", *tlsSec, &cfg.TLSTimeout},
+		{"--header-timeout", *headerSec, &cfg.HeaderTimeout},
+		{"--read-timeout", *readSec, &cfg.ReadTimeout},
+	} {
+		if t.sec < 0 {
+			return nil, &FlagError{Flag: t.flag, Err: fmt.Errorf("must be 0 or more seconds, got %d", t.sec)}
+		}
+		*t.dst = time.Duration(t.sec) * time.Second
+	}
 
 	if *durationSec < 0 {
 		log.Println("[!] Invalid duration value, defaulting to 0 (unlimited)")
@@ -83,10 +363,279 @@ func ParseFlags() *Config {
 	}
 	cfg.Delay = time.Duration(*delayMs) * time.Millisecond
 
+	if *heartbeatSec < 0 {
+		log.Println("[!] Invalid heartbeat value, defaulting to 60 seconds")
+		*heartbeatSec = 60
+	}
+	cfg.Heartbeat = time.Duration(*heartbeatSec) * time.Second
+	if *crawlExclude != "" {
+		re, err := regexp.Compile(*crawlExclude)
+		if err != nil {
+			return nil, &FlagError{Flag: "--crawl-exclude", Err: err}
+		}
+		cfg.CrawlExclude = re
+	}
+	if *checkpointSec < 0 {
+		return nil, &FlagError{Flag: "--checkpoint-interval", Err: fmt.Errorf("must be 0 or more seconds, got %d", *checkpointSec)}
+	}
+	cfg.CheckpointInterval = time.Duration(*checkpointSec) * time.Second
+	if err := parseNotify(cfg, *notifyOn); err != nil {
+		return nil, err
+	}
+	if *notifyIntervalSec < 1 {
+		return nil, &FlagError{Flag: "--notify-interval", Err: fmt.Errorf("must be at least 1 second, got %d", *notifyIntervalSec)}
+	}
+	cfg.NotifyInterval = time.Duration(*notifyIntervalSec) * time.Second
+	if cfg.Silent && cfg.API {
+		return nil, fmt.Errorf("%w: --silent doesn't apply to --api", ErrUsage)
+	}
+	if cfg.ResumeFile != "" && (cfg.API || cfg.Remote != "") {
+		return nil, fmt.Errorf("%w: --resume only applies to local CLI scans", ErrUsage)
+	}
+	if (cfg.APITLSCert == "") != (cfg.APITLSKey == "") {
+		return nil, fmt.Errorf("%w: --api-tls-cert and --api-tls-key go together", ErrUsage)
+	}
+	if cfg.APITLSSelfSigned && cfg.APITLSCert != "" {
+		return nil, fmt.Errorf("%w: --api-tls-self-signed can't be combined with --api-tls-cert", ErrUsage)
+	}
+	if (cfg.APITLSCert != "" || cfg.APITLSSelfSigned) && !cfg.API {
+		return nil, fmt.Errorf("%w: --api-tls-* only apply to --api", ErrUsage)
+	}
+	if cfg.Append && cfg.API {
+		return nil, fmt.Errorf("%w: --append doesn't apply to --api", ErrUsage)
+	}
+	if cfg.Append && cfg.OutputHTML != "" {
+		return nil, fmt.Errorf("%w: --append can't merge HTML reports; write each chunk to its own -o-html file", ErrUsage)
+	}
+	if cfg.Append && cfg.ResumeFile != "" {
+		return nil, fmt.Errorf("%w: --append can't be combined with --resume (results of the earlier run would be written twice)", ErrUsage)
+	}
+	if cfg.OutputTemplate != "" && !strings.Contains(cfg.OutputTemplate, "{{") {
+		// Not a template itself, so the name of a template file
+		data, err := os.ReadFile(cfg.OutputTemplate)
+		if err != nil {
+			return nil, &FlagError{Flag: "-o-template", Err: err}
+		}
+		cfg.OutputTemplate = strings.TrimRight(string(data), "\r\n")
+	}
+	if cfg.StoreResponses != "" && (cfg.API || cfg.Remote != "") {
+		return nil, fmt.Errorf("%w: --store-responses only applies to local CLI scans", ErrUsage)
+	}
+	if *stallSec < 0 {
+		log.Println("[!] Invalid stall timeout, defaulting to 300 seconds")
+		*stallSec = 300
+	}
+	cfg.StallTimeout = time.Duration(*stallSec) * time.Second
+	if *linkTTLSec < 0 {
+		return nil, &FlagError{Flag: "--api-link-ttl", Err: fmt.Errorf("must be 0 or more seconds, got %d", *linkTTLSec)}
+	}
+	cfg.APILinkTTL = time.Duration(*linkTTLSec) * time.Second
+	if *jobTTLSec < 0 {
+		return nil, &FlagError{Flag: "--api-job-ttl", Err: fmt.Errorf("must be 0 or more seconds, got %d", *jobTTLSec)}
+	}
+	cfg.APIJobTTL = time.Duration(*jobTTLSec) * time.Second
+
+	if cfg.MaxCPUs < 0 {
+		return nil, &FlagError{Flag: "--max-cpus", Err: fmt.Errorf("must be 0 or more, got %d", cfg.MaxCPUs)}
+	}
 	if cfg.Threads <= 0 {
 		log.Println("[!] Invalid threads value, defaulting to 10")
 		cfg.Threads = 10
 	}
+	if cfg.CalibrateApply {
+		cfg.Calibrate = true
+	}
+	if *evasionDelayMs < 0 {
+		log.Println("[!] Invalid evasion delay, defaulting to 2000ms")
+		*evasionDelayMs = 2000
+	}
+	cfg.EvasionDelay = time.Duration(*evasionDelayMs) * time.Millisecond
+
+	var err error
+	if cfg.MaxBodySize, err = ParseByteSize(*maxBodySize); err != nil {
+		return nil, &FlagError{Flag: "--max-body-size", Err: err}
+	}
+	if cfg.APIMaxRequest, err = ParseByteSize(*apiMaxRequest); err != nil {
+		return nil, &FlagError{Flag: "--api-max-request-size", Err: err}
+	}
+	if cfg.APIMaxUpload, err = ParseByteSize(*apiMaxUpload); err != nil {
+		return nil, &FlagError{Flag: "--api-max-upload-size", Err: err}
+	}
+	if cfg.APIMaxScans < 0 {
+		return nil, &FlagError{Flag: "--api-max-scans", Err: fmt.Errorf("must be 0 or more, got %d", cfg.APIMaxScans)}
+	}
+	if cfg.APIMaxThreads < 0 {
+		return nil, &FlagError{Flag: "--api-max-threads", Err: fmt.Errorf("must be 0 or more, got %d", cfg.APIMaxThreads)}
+	}
+	if cfg.APIRateLimit < 0 {
+		return nil, &FlagError{Flag: "--api-rate-limit", Err: fmt.Errorf("must be 0 or more, got %d", cfg.APIRateLimit)}
+	}
+	if cfg.MaxJobURLs < 0 {
+		return nil, &FlagError{Flag: "--max-job-urls", Err: fmt.Errorf("must be 0 or more, got %d", cfg.MaxJobURLs)}
+	}
+	keyEntries := strings.Split(*apiKeys, ",")
+	if cfg.APIKeysFile != "" {
+		fileEntries, err := readAPIKeys(cfg.APIKeysFile)
+		if err != nil {
+			return nil, &FlagError{Flag: "--api-keys-file", Err: err}
+		}
+		keyEntries = append(keyEntries, fileEntries...)
+	}
+	if cfg.APIKeys, err = ParseAPIKeys(keyEntries); err != nil {
+		return nil, &FlagError{Flag: "--api-key", Err: err}
+	}
+	cfg.APICORSOrigins = splitList(*corsOrigins)
+	for i, origin := range cfg.APICORSOrigins {
+		if err := ValidateCORSOrigin(origin); err != nil {
+			return nil, &FlagError{Flag: "--api-cors-origins", Err: err}
+		}
+		cfg.APICORSOrigins[i] = strings.TrimSuffix(origin, "/")
+	}
+	cfg.APICORSMethods, cfg.APICORSHeaders = splitList(strings.ToUpper(*corsMethods)), splitList(*corsHeaders)
+	if cfg.Proxy, err = ParseProxy(*proxy); err != nil {
+		return nil, &FlagError{Flag: "--proxy", Err: err}
+	}
+	if cfg.ProxyList != "" {
+		if cfg.Proxies, err = LoadProxies(cfg.ProxyList); err != nil {
+			return nil, &FlagError{Flag: "--proxy-list", Err: err}
+		}
+	}
+	if cfg.Resolvers, err = ParseResolvers(*resolvers); err != nil {
+		return nil, &FlagError{Flag: "--resolvers", Err: err}
+	}
+	if cfg.Resolve, err = ParseResolve(resolve); err != nil {
+		return nil, &FlagError{Flag: "--resolve", Err: err}
+	}
+	if cfg.CACert != "" {
+		if cfg.RootCAs, err = LoadCertPool(cfg.CACert); err != nil {
+			return nil, &FlagError{Flag: "--ca-cert", Err: err}
+		}
+	}
+	if cfg.CertPins, err = ParseCertPins(pins); err != nil {
+		return nil, &FlagError{Flag: "--pin", Err: err}
+	}
+	if cfg.MatchCodes, err = ParseStatusCodes(*matchCodes); err != nil {
+		return nil, &FlagError{Flag: "--match-code", Err: err}
+	}
+	if cfg.FilterCodes, err = ParseStatusCodes(*filterCodes); err != nil {
+		return nil, &FlagError{Flag: "--filter-code", Err: err}
+	}
+	if cfg.MatchSizes, err = ParseSizeRanges(*matchSizes); err != nil {
+		return nil, &FlagError{Flag: "--match-size", Err: err}
+	}
+	if cfg.FilterSizes, err = ParseSizeRanges(*filterSizes); err != nil {
+		return nil, &FlagError{Flag: "--filter-size", Err: err}
+	}
+	if cfg.Campaign != "" {
+		if err := campaign.ValidateName(cfg.Campaign); err != nil {
+			return nil, &FlagError{Flag: "--campaign", Err: err}
+		}
+		if cfg.CampaignLabel == "" {
+			cfg.CampaignLabel = "hx-hawks"
+		}
+	}
+	if cfg.Fields, err = types.ParseFields(*fields); err != nil {
+		return nil, &FlagError{Flag: "--fields", Err: err}
+	}
+	if cfg.Headers, err = ParseHeaders(headers); err != nil {
+		return nil, &FlagError{Flag: "-H", Err: err}
+	}
+	if *hostHeader != "" {
+		if err := ValidateHostHeader(*hostHeader); err != nil {
+			return nil, &FlagError{Flag: "--host-header", Err: err}
+		}
+		if cfg.Headers == nil {
+			cfg.Headers = make(http.Header)
+		}
+		cfg.Headers.Set("Host", *hostHeader)
+	}
+	if *vhostList != "" {
+		if cfg.VHosts, err = LoadVHosts(*vhostList); err != nil {
+			return nil, &FlagError{Flag: "--vhost-list", Err: err}
+		}
+	}
+	if cfg.Cookies, err = ParseCookieHeader(*cookies); err != nil {
+		return nil, &FlagError{Flag: "--cookie", Err: err}
+	}
+	if cfg.CookieFile != "" {
+		if cfg.FileCookies, err = ParseCookieFile(cfg.CookieFile); err != nil {
+			return nil, &FlagError{Flag: "--cookie-file", Err: err}
+		}
+	}
+	if *data != "" && *dataFile != "" {
+		return nil, fmt.Errorf("%w: use either --data or --data-file, not both", ErrUsage)
+	}
+	cfg.Data = []byte(*data)
+	if *dataFile != "" {
+		if cfg.Data, err = os.ReadFile(*dataFile); err != nil {
+			return nil, &FlagError{Flag: "--data-file", Err: err}
+		}
+	}
+	if cfg.Method, cfg.ContentType, err = ResolveRequest(cfg.Method, cfg.ContentType, cfg.Data); err != nil {
+		return nil, &FlagError{Flag: "--method", Err: err}
+	}
+	// The checks shared with API scan requests
+	cfg.KeywordScope = rules.Scope{Hosts: splitList(*ckHosts), Tech: splitList(*ckTech)}
+	if err := cfg.ValidateScan(); err != nil {
+		return nil, err
+	}
+
+	// Load rules and recipes
+	if cfg.RulesFile != "" {
+		if cfg.Rules, err = rules.Load(cfg.RulesFile); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidRule, err)
+		}
+	}
+	// Load technology fingerprints (tech-scoped rules need them too)
+	if cfg.TechRulesFile != "" {
+		cfg.TechDetect = true
+	}
+	if rules.NeedsTech(cfg.Rules) && !cfg.TechDetect {
+		log.Println("[+] Rules are scoped to technologies, enabling --tech-detect")
+		cfg.TechDetect = true
+	}
+	if len(cfg.KeywordScope.Tech) > 0 && !cfg.TechDetect {
+		log.Println("[+] Keywords are scoped to technologies (--ck-tech), enabling --tech-detect")
+		cfg.TechDetect = true
+	}
+	if cfg.TechDetect {
+		cfg.Fingerprints = fingerprint.Defaults()
+		if cfg.TechRulesFile != "" {
+			extra, err := fingerprint.Load(cfg.TechRulesFile)
+			if err != nil {
+				return nil, &FlagError{Flag: "--tech-rules", Err: err}
+			}
+			cfg.Fingerprints = append(cfg.Fingerprints, extra...)
+		}
+	}
+	for _, name := range strings.Split(*recipes, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		recipeRules, err := rules.Recipe(name)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidRule, err)
+		}
+		cfg.Recipes = append(cfg.Recipes, name)
+		cfg.Rules = append(cfg.Rules, recipeRules...)
+	}
+
+	if len(selectors) > 0 {
+		selectorRules, err := rules.SelectorRules(selectors)
+		if err != nil {
+			return nil, fmt.Errorf("%w: --match-selector: %w", ErrInvalidRule, err)
+		}
+		cfg.Selectors = selectors
+		cfg.Rules = append(cfg.Rules, selectorRules...)
+	}
+	if len(jsonPaths) > 0 {
+		jsonPathRules, err := rules.JSONPathRules(jsonPaths)
+		if err != nil {
+			return nil, fmt.Errorf("%w: --match-jsonpath: %w", ErrInvalidRule, err)
+		}
+		cfg.JSONPaths = jsonPaths
+		cfg.Rules = append(cfg.Rules, jsonPathRules...)
+	}
 
 	// Parse keywords
 	if cfg.KeywordsRaw != "" {
@@ -101,11 +650,236 @@ func ParseFlags() *Config {
 				validKeywords = append(validKeywords, k)
 			}
 		}
-		cfg.Keywords = validKeywords
-		if len(cfg.Keywords) == 0 && !cfg.API {
-			log.Fatal("[-] No valid keywords provided via --ck")
+		cfg.Keywords, cfg.KeywordSeverity = ParseKeywordSeverities(validKeywords)
+		if len(cfg.Keywords) == 0 && len(cfg.Rules) == 0 && !cfg.API && cfg.Attach == "" {
+			return nil, fmt.Errorf("%w: no valid keywords provided via --ck", ErrUsage)
+		}
+	}
+
+	// Warn about redundant keywords/rules that would double scan cost and findings
+	for _, c := range rules.Lint(cfg.Keywords, cfg.Rules) {
+		log.Printf("[!] Rule conflict (%s): %s", c.Kind, c.Message)
+	}
+	if cfg.DedupeRules {
+		before := len(cfg.Keywords) + len(cfg.Rules)
+		cfg.Keywords, cfg.Rules = rules.Dedupe(cfg.Keywords, cfg.Rules)
+		log.Printf("[+] Deduplicated keywords and rules (%d -> %d entries)", before, len(cfg.Keywords)+len(cfg.Rules))
+	}
+
+	return cfg, nil
+}
+
+// LoadProxies reads proxy URLs (http, https or socks5), one per line; blank
+// lines and lines starting with # are ignored.
+func LoadProxies(path string) ([]*url.URL, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var proxies []*url.URL
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		u, err := ParseProxy(line)
+		if err != nil {
+			return nil, err
+		}
+		proxies = append(proxies, u)
+	}
+	return proxies, nil
+}
+
+// ValidateCORSOrigin checks an --api-cors-origins entry: "*" or a
+// scheme://host[:port] origin as browsers send it.
+func ValidateCORSOrigin(origin string) error {
+	if origin == "*" {
+		return nil
+	}
+	u, err := url.Parse(origin)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || (u.Path != "" && u.Path != "/") || u.RawQuery != "" {
+		return fmt.Errorf("invalid origin %q (use scheme://host[:port] or *)", origin)
+	}
+	return nil
+}
+
+// splitList splits a comma-separated flag value, dropping blank entries.
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// APIKey is a key accepted by the API server. Name identifies the key in
+// logs without revealing it.
+type APIKey struct {
+	Name string
+	Key  string
+}
+
+// ParseAPIKeys parses API key entries, "key" or "name:key"; blank entries
+// are skipped. Unnamed keys are called key-1, key-2, ... by position.
+func ParseAPIKeys(entries []string) ([]APIKey, error) {
+	var keys []APIKey
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		k := APIKey{Name: fmt.Sprintf("key-%d", len(keys)+1), Key: entry}
+		if name, key, ok := strings.Cut(entry, ":"); ok {
+			k.Name, k.Key = strings.TrimSpace(name), strings.TrimSpace(key)
+		}
+		if k.Name == "" || k.Key == "" {
+			return nil, fmt.Errorf("invalid API key entry %q (use key or name:key)", entry)
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// readAPIKeys reads API key entries, one "key" or "name:key" per line;
+// blank lines and lines starting with # are ignored.
+func readAPIKeys(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var entries []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			entries = append(entries, line)
+		}
+	}
+	return entries, nil
+}
+
+// parseNotify builds cfg.Notifiers and cfg.NotifyEvents from the --notify-*
+// flags and the --notify-config file; flag providers come first.
+func parseNotify(cfg *Config, notifyOn string) error {
+	flagProviders := []struct {
+		flag string
+		pc   notify.ProviderConfig
+	}{
+		{"--notify-webhook", notify.ProviderConfig{Type: "webhook", URL: cfg.NotifyWebhook}},
+		{"--notify-slack", notify.ProviderConfig{Type: "slack", URL: cfg.NotifySlack}},
+		{"--notify-discord", notify.ProviderConfig{Type: "discord", URL: cfg.NotifyDiscord}},
+	}
+	for _, fp := range flagProviders {
+		if fp.pc.URL == "" {
+			continue
+		}
+		p, err := fp.pc.Provider()
+		if err != nil {
+			return &FlagError{Flag: fp.flag, Err: fmt.Errorf("must be an http(s) URL, got %q", fp.pc.URL)}
+		}
+		cfg.Notifiers = append(cfg.Notifiers, p)
+	}
+	if (cfg.NotifyTelegramToken == "") != (cfg.NotifyTelegramChat == "") {
+		return fmt.Errorf("%w: --notify-telegram-token and --notify-telegram-chat must be used together", ErrUsage)
+	}
+	if cfg.NotifyTelegramToken != "" {
+		cfg.Notifiers = append(cfg.Notifiers, &notify.Telegram{Token: cfg.NotifyTelegramToken, ChatID: cfg.NotifyTelegramChat})
+	}
+	if cfg.NotifyConfig != "" {
+		providers, events, err := notify.Load(cfg.NotifyConfig)
+		if err != nil {
+			return &FlagError{Flag: "--notify-config", Err: err}
+		}
+		cfg.Notifiers = append(cfg.Notifiers, providers...)
+		cfg.NotifyEvents = events
+	}
+	if notifyOn != "" {
+		cfg.NotifyEvents = nil
+		for _, e := range strings.Split(notifyOn, ",") {
+			if e = strings.TrimSpace(e); e != "" {
+				cfg.NotifyEvents = append(cfg.NotifyEvents, e)
+			}
+		}
+		if err := notify.ValidateEvents(cfg.NotifyEvents); err != nil {
+			return &FlagError{Flag: "--notify-on", Err: err}
+		}
+	}
+	return nil
+}
+
+// ParseKeywordSeverities splits "keyword:severity" entries (e.g.
+// "AWS_SECRET:critical") into the keyword and its severity. The suffix is
+// only taken as a severity when it is one (info, low, medium, high,
+// critical), so keywords containing ':' keep working. The map is nil when no
+// keyword has a severity.
+func ParseKeywordSeverities(entries []string) ([]string, map[string]string) {
+	keywords := make([]string, 0, len(entries))
+	var severities map[string]string
+	for _, k := range entries {
+		if i := strings.LastIndex(k, ":"); i > 0 && types.IsSeverity(strings.TrimSpace(k[i+1:])) {
+			if keyword := strings.TrimSpace(k[:i]); keyword != "" {
+				if severities == nil {
+					severities = make(map[string]string)
+				}
+				severities[keyword] = strings.ToLower(strings.TrimSpace(k[i+1:]))
+				k = keyword
+			}
+		}
+		keywords = append(keywords, k)
+	}
+	return keywords, severities
+}
+
+// ValidateESIndex checks an Elasticsearch index name: lowercase, not starting
+// with -, _ or +, and none of the characters the cluster rejects. Date math
+// names like <hx-hawks-{now/d}> are accepted.
+func ValidateESIndex(index string) error {
+	if strings.HasPrefix(index, "<") && strings.HasSuffix(index, ">") {
+		return nil
+	}
+	if index == "" || index == "." || index == ".." || strings.ContainsAny(index[:1], "-_+") {
+		return fmt.Errorf("invalid index name %q", index)
+	}
+	if index != strings.ToLower(index) || strings.ContainsAny(index, "\\/*?\"<>| ,#:") {
+		return fmt.Errorf("invalid index name %q (must be lowercase, without spaces or \\/*?\"<>|,#:)", index)
+	}
+	return nil
+}
+
+// ParseStatusCodes parses a comma-separated list of HTTP status codes (e.g. "200,500").
+func ParseStatusCodes(raw string) ([]int, error) {
+	var codes []int
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
 		}
+		code, err := strconv.Atoi(part)
+		if err != nil || code < 100 || code > 599 {
+			return nil, fmt.Errorf("invalid status code %q", part)
+		}
+		codes = append(codes, code)
 	}
+	return codes, nil
+}
 
-	return cfg
+// ValidateLoginRedirects checks a --login-redirects mode ("" counts as the
+// default, downgrade).
+func ValidateLoginRedirects(mode string) error {
+	switch mode {
+	case "", "off", "downgrade", "suppress":
+		return nil
+	}
+	return fmt.Errorf("%q (use off, downgrade or suppress)", mode)
+}
+
+// ValidateCrawlScope checks a --crawl-scope value ("" counts as the default, host).
+func ValidateCrawlScope(scope string) error {
+	switch scope {
+	case "", "host", "subdomains":
+		return nil
+	}
+	return fmt.Errorf("%q (use host or subdomains)", scope)
 }
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FileCookie is a cookie loaded from a cookie file with the host it belongs to.
+type FileCookie struct {
+	Host     string // Domain from the file, without a leading dot
+	HostOnly bool   // Sent to Host only, not its subdomains
+	Cookie   *http.Cookie
+}
+
+// ParseCookieFile reads cookies from a Netscape/curl cookie file
+// (domain, include-subdomains, path, secure, expiry, name, value separated
+// by tabs). Expired cookies are skipped.
+func ParseCookieFile(path string) ([]FileCookie, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var cookies []FileCookie
+	scanner := bufio.NewScanner(f)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		httpOnly := false
+		if strings.HasPrefix(line, "#HttpOnly_") {
+			line = strings.TrimPrefix(line, "#HttpOnly_")
+			httpOnly = true
+		}
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != 7 {
+			return nil, fmt.Errorf("%s:%d: expected 7 tab-separated fields, got %d", path, lineNo, len(fields))
+		}
+		expiry, err := strconv.ParseInt(fields[4], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid expiry %q", path, lineNo, fields[4])
+		}
+
+		c := &http.Cookie{
+			Name:     fields[5],
+			Value:    fields[6],
+			Path:     fields[2],
+			Secure:   strings.EqualFold(fields[3], "TRUE"),
+			HttpOnly: httpOnly,
+		}
+		if expiry > 0 {
+			c.Expires = time.Unix(expiry, 0)
+			if c.Expires.Before(time.Now()) {
+				continue
+			}
+		}
+		cookies = append(cookies, FileCookie{
+			Host:     strings.TrimPrefix(fields[0], "."),
+			HostOnly: !strings.EqualFold(fields[1], "TRUE"),
+			Cookie:   c,
+		})
+	}
+	return cookies, scanner.Err()
+}
+
+// ParseCookieHeader parses a Cookie header value such as "a=b; c=d".
+func ParseCookieHeader(s string) ([]*http.Cookie, error) {
+	var cookies []*http.Cookie
+	for _, part := range strings.Split(s, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(part, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid cookie %q (want name=value)", part)
+		}
+		cookies = append(cookies, &http.Cookie{Name: name, Value: strings.TrimSpace(value)})
+	}
+	return cookies, nil
+}
+
+// FormatCookies renders cookies as a Cookie header value ("a=b; c=d").
+func FormatCookies(cookies []*http.Cookie) string {
+	parts := make([]string, 0, len(cookies))
+	for _, c := range cookies {
+		parts = append(parts, c.Name+"="+c.Value)
+	}
+	return strings.Join(parts, "; ")
+}
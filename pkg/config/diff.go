@@ -0,0 +1,48 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+)
+
+// Exit codes of the `diff` subcommand, for CI gating.
+const (
+	DiffExitClean     = 0 // No regression (per --fail-on)
+	DiffExitRegressed = 1 // Security posture regressed
+	DiffExitError     = 2 // Bad arguments or unreadable reports
+)
+
+// DiffConfig holds the settings for the `diff` subcommand.
+type DiffConfig struct {
+	OldReport   string
+	NewReport   string
+	ChangesFile string // Machine-readable summary of the comparison (JSON)
+	FailOn      string // "regression" (default), "any" or "never"
+}
+
+// ParseDiffFlags parses the arguments of `hx-hawks diff [flags] old.json new.json`.
+// Callers should exit with DiffExitError on error so CI can tell usage errors
+// from a regression.
+func ParseDiffFlags(args []string) (*DiffConfig, error) {
+	cfg := &DiffConfig{}
+	fs := flag.NewFlagSet("diff", flag.ExitOnError) // ExitOnError exits with status 2
+	fs.StringVar(&cfg.ChangesFile, "changes-file", "", "Write new/fixed/changed counts and details to this JSON file")
+	fs.StringVar(&cfg.FailOn, "fail-on", "regression", "When to exit 1: regression (new or added findings), any (any difference) or never")
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		return nil, fmt.Errorf("%w (usage: hx-hawks diff [--changes-file changes.json] [--fail-on regression|any|never] old.json new.json)", ErrUsage)
+	}
+	switch cfg.FailOn {
+	case "regression", "any", "never":
+	default:
+		return nil, errInvalidFailOn(cfg.FailOn)
+	}
+	cfg.OldReport, cfg.NewReport = fs.Arg(0), fs.Arg(1)
+	return cfg, nil
+}
+
+// errInvalidFailOn reports a --fail-on value other than regression, any or never.
+func errInvalidFailOn(value string) error {
+	return &FlagError{Flag: "--fail-on", Err: fmt.Errorf("%q (use regression, any or never)", value)}
+}
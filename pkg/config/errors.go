@@ -0,0 +1,31 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Failure classes returned (wrapped) by the flag parsers, so callers can
+// tell them apart with errors.Is instead of matching messages.
+var (
+	// ErrUsage marks missing or conflicting flags and arguments.
+	ErrUsage = errors.New("invalid arguments")
+	// ErrInputFile marks a target list (-f) that is missing or unreadable.
+	ErrInputFile = errors.New("cannot read input file")
+	// ErrInvalidRule marks a rules file, recipe, selector or JSONPath that
+	// does not load or compile.
+	ErrInvalidRule = errors.New("invalid rule")
+)
+
+// FlagError reports a flag whose value could not be parsed or loaded.
+type FlagError struct {
+	Flag  string // As typed on the command line, e.g. "--match-code" or "-H"
+	Field string // The same option in a /scan/start request, e.g. "match_codes" ("" = CLI only)
+	Err   error
+}
+
+func (e *FlagError) Error() string {
+	return fmt.Sprintf("invalid %s value: %v", e.Flag, e.Err)
+}
+
+func (e *FlagError) Unwrap() error { return e.Err }
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+// ImportConfig holds the settings for the `import` subcommand.
+type ImportConfig struct {
+	Format string   // httpx, nuclei, ffuf or auto
+	Inputs []string // Files produced by the other tool
+
+	// Same output flags as a scan, so imported results feed diff and reporting
+	OutputFile     string
+	OutputJSON     string
+	OutputResponse string
+	OutputAll      string
+	OutputAllJSON  string
+	OutputJUnit    string
+	OutputMarkdown string
+	Fields         []string // --fields, as for scans
+}
+
+// ParseImportFlags parses the arguments of `hx-hawks import [flags] file...`.
+func ParseImportFlags(args []string) (*ImportConfig, error) {
+	cfg := &ImportConfig{}
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	fs.StringVar(&cfg.Format, "format", "auto", "Input format: httpx, nuclei, ffuf or auto (detect per file)")
+	fs.StringVar(&cfg.OutputFile, "o", "", "Output file for vulnerable URLs (plain text)")
+	fs.StringVar(&cfg.OutputJSON, "o-json", "", "Output file for vulnerable results (JSON)")
+	fs.StringVar(&cfg.OutputResponse, "o-response", "", "Output file for vulnerable URLs + response (plain text)")
+	fs.StringVar(&cfg.OutputAll, "o-all", "", "Output file for all URLs (plain text)")
+	fs.StringVar(&cfg.OutputAllJSON, "o-all-json", "", "Output file for all results (JSON report)")
+	fs.StringVar(&cfg.OutputJUnit, "o-junit", "", "Output file for all results (JUnit XML report)")
+	fs.StringVar(&cfg.OutputMarkdown, "o-md", "", "Output file for vulnerable results (Markdown report)")
+	fields := fs.String("fields", "", "Comma-separated fields for JSON outputs (default: all)")
+	fs.Parse(args)
+
+	cfg.Inputs = fs.Args()
+	if len(cfg.Inputs) == 0 {
+		return nil, fmt.Errorf("%w (usage: hx-hawks import [--format httpx|nuclei|ffuf|auto] -o-all-json report.json file...)", ErrUsage)
+	}
+	switch cfg.Format {
+	case "auto", "httpx", "nuclei", "ffuf":
+	default:
+		return nil, &FlagError{Flag: "--format", Err: fmt.Errorf("unknown format %q (use httpx, nuclei, ffuf or auto)", cfg.Format)}
+	}
+	var err error
+	if cfg.Fields, err = types.ParseFields(*fields); err != nil {
+		return nil, &FlagError{Flag: "--fields", Err: err}
+	}
+	if cfg.OutputFile == "" && cfg.OutputJSON == "" && cfg.OutputResponse == "" && cfg.OutputAll == "" && cfg.OutputAllJSON == "" && cfg.OutputJUnit == "" && cfg.OutputMarkdown == "" {
+		return nil, fmt.Errorf("%w: at least one output (-o, -o-json, -o-response, -o-all, -o-all-json, -o-junit, -o-md) is required", ErrUsage)
+	}
+	return cfg, nil
+}
+
+// OutputConfig returns a scan Config carrying only the output paths, for the
+// shared result writers.
+func (c *ImportConfig) OutputConfig() *Config {
+	return &Config{
+		OutputFile:     c.OutputFile,
+		OutputJSON:     c.OutputJSON,
+		OutputResponse: c.OutputResponse,
+		OutputAll:      c.OutputAll,
+		OutputAllJSON:  c.OutputAllJSON,
+		OutputJUnit:    c.OutputJUnit,
+		OutputMarkdown: c.OutputMarkdown,
+		Fields:         c.Fields,
+	}
+}
@@ -0,0 +1,257 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/nxneeraj/hx-hawks/pkg/campaign"
+	"github.com/nxneeraj/hx-hawks/pkg/fingerprint"
+	"github.com/nxneeraj/hx-hawks/pkg/notify"
+	"github.com/nxneeraj/hx-hawks/pkg/rules"
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+// ScanDefaults returns a Config holding the default scan options. The
+// command-line flags take their defaults from it, and API scan requests
+// start from it, so both run the same scan when nothing is set.
+func ScanDefaults() *Config {
+	return &Config{
+		Threads:            10,
+		Timeout:            10 * time.Second,
+		TLSTimeout:         10 * time.Second,
+		EntropyThreshold:   4.5,
+		MaxBodySize:        DefaultMaxBodySize,
+		MaxRedirects:       10,
+		LoginRedirects:     "downgrade",
+		CrawlDepth:         2,
+		CrawlMaxPages:      500,
+		CrawlScope:         "host",
+		Heartbeat:          time.Minute,
+		StallTimeout:       5 * time.Minute,
+		EvasionThreshold:   3,
+		EvasionDelay:       2 * time.Second,
+		ESIndex:            "hx-hawks",
+		NotifyBatch:        notify.DefaultBatchSize,
+		NotifyInterval:     5 * time.Second,
+		CheckpointInterval: 30 * time.Second,
+		CalibrateSample:    20,
+	}
+}
+
+// ValidateScan checks the scan options of cfg, however they were set (flags
+// or a /scan/start request). Errors are a *FlagError naming the option both
+// ways, or wrap ErrUsage.
+func (cfg *Config) ValidateScan() error {
+	for _, c := range []struct {
+		flag, field string
+		bad         bool
+		want        string
+		got         interface{}
+	}{
+		{"--threads", "threads", cfg.Threads < 1, "must be at least 1", cfg.Threads},
+		{"--timeout", "timeout_sec", cfg.Timeout <= 0, "must be more than 0 seconds", cfg.Timeout},
+		{"--connect-timeout", "connect_timeout_sec", cfg.ConnectTimeout < 0, "must be 0 or more seconds", cfg.ConnectTimeout},
+		{"--tls-timeout", "tls_timeout_sec", cfg.TLSTimeout < 0, "must be 0 or more seconds", cfg.TLSTimeout},
+		{"--header-timeout", "header_timeout_sec", cfg.HeaderTimeout < 0, "must be 0 or more seconds", cfg.HeaderTimeout},
+		{"--read-timeout", "read_timeout_sec", cfg.ReadTimeout < 0, "must be 0 or more seconds", cfg.ReadTimeout},
+		{"--delay", "delay_ms", cfg.Delay < 0, "must be 0 or more milliseconds", cfg.Delay},
+		{"--stall-timeout", "stall_timeout_sec", cfg.StallTimeout < 0, "must be 0 or more seconds", cfg.StallTimeout},
+		{"--entropy-threshold", "entropy_threshold", cfg.EntropyThreshold <= 0 || cfg.EntropyThreshold > 8, "must be between 0 and 8 bits per character", cfg.EntropyThreshold},
+		{"--max-body-size", "max_body_size", cfg.MaxBodySize < 0, "must be 0 (unlimited) or more bytes", cfg.MaxBodySize},
+		{"--depth", "crawl_depth", cfg.CrawlDepth < 1, "must be at least 1", cfg.CrawlDepth},
+		{"--crawl-max", "crawl_max_pages", cfg.CrawlMaxPages < 0, "must be 0 or more", cfg.CrawlMaxPages},
+		{"--notify-batch", "", cfg.NotifyBatch < 1, "must be at least 1", cfg.NotifyBatch},
+	} {
+		if c.bad {
+			return &FlagError{Flag: c.flag, Field: c.field, Err: fmt.Errorf("%s, got %v", c.want, c.got)}
+		}
+	}
+	for _, c := range []struct {
+		flag, field string
+		err         error
+	}{
+		{"--crawl-scope", "crawl_scope", ValidateCrawlScope(cfg.CrawlScope)},
+		{"--login-redirects", "login_redirects", ValidateLoginRedirects(cfg.LoginRedirects)},
+		{"--retries", "retries", ValidateRetries(cfg.Retries)},
+		{"--max-redirects", "max_redirects", ValidateMaxRedirects(cfg.MaxRedirects)},
+		{"--match-code", "match_codes", ValidateStatusCodes(cfg.MatchCodes)},
+		{"--filter-code", "filter_codes", ValidateStatusCodes(cfg.FilterCodes)},
+		{"--notify-on", "notify_on", notify.ValidateEvents(cfg.NotifyEvents)},
+		{"--ck-hosts", "keyword_hosts", cfg.KeywordScope.Validate()},
+	} {
+		if c.err != nil {
+			return &FlagError{Flag: c.flag, Field: c.field, Err: c.err}
+		}
+	}
+	if cfg.ESURL != "" {
+		if u, err := url.Parse(cfg.ESURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return &FlagError{Flag: "--es-url", Field: "es_url", Err: fmt.Errorf("must be an http(s) URL, got %q", cfg.ESURL)}
+		}
+		if err := ValidateESIndex(cfg.ESIndex); err != nil {
+			return &FlagError{Flag: "--es-index", Field: "es_index", Err: err}
+		}
+	}
+	return ValidateAuth(cfg.AuthBasic, cfg.AuthBearer)
+}
+
+// FromScanRequest builds the config of an API scan from a /scan/start
+// request: the ScanDefaults, overridden by the options the request sets,
+// parsed and checked like the matching flags. Errors are a *FlagError
+// naming the request field, or wrap ErrUsage or ErrInvalidRule.
+func FromScanRequest(req types.ScanRequest) (*Config, error) {
+	cfg := ScanDefaults()
+	cfg.API = true
+	cfg.Keywords, cfg.KeywordSeverity = ParseKeywordSeverities(req.Keywords)
+	cfg.KeywordsRaw = strings.Join(req.Keywords, ",")
+	cfg.KeywordScope = rules.Scope{Hosts: req.KeywordHosts, Tech: req.KeywordTech}
+
+	seconds := func(n int) time.Duration { return time.Duration(n) * time.Second }
+	if req.Threads != 0 {
+		cfg.Threads = req.Threads
+	}
+	if req.TimeoutSec != 0 {
+		cfg.Timeout = seconds(req.TimeoutSec)
+	}
+	if req.TLSTimeoutSec != 0 {
+		cfg.TLSTimeout = seconds(req.TLSTimeoutSec)
+	}
+	cfg.ConnectTimeout = seconds(req.ConnectTimeoutSec)
+	cfg.HeaderTimeout = seconds(req.HeaderTimeoutSec)
+	cfg.ReadTimeout = seconds(req.ReadTimeoutSec)
+	cfg.Delay = time.Duration(req.DelayMs) * time.Millisecond
+	if req.StallTimeoutSec != 0 {
+		cfg.StallTimeout = seconds(req.StallTimeoutSec)
+	}
+	cfg.StallAbort = req.StallAbort
+	cfg.Verbose = req.Verbose
+
+	// Matching
+	cfg.SkipBinary, cfg.FullBody, cfg.DedupeResponses = req.SkipBinary, req.FullBody, req.DedupeResponses
+	cfg.Entropy = req.Entropy
+	if req.EntropyThreshold != 0 {
+		cfg.EntropyThreshold = req.EntropyThreshold
+	}
+	if req.MaxBodySize != 0 {
+		cfg.MaxBodySize = req.MaxBodySize
+	}
+	if req.LoginRedirects != "" {
+		cfg.LoginRedirects = req.LoginRedirects
+	}
+	cfg.MatchCodes, cfg.FilterCodes = req.MatchCodes, req.FilterCodes
+	var err error
+	if cfg.MatchSizes, err = ParseSizeRanges(req.MatchSize); err != nil {
+		return nil, &FlagError{Flag: "--match-size", Field: "match_size", Err: err}
+	}
+	if cfg.FilterSizes, err = ParseSizeRanges(req.FilterSize); err != nil {
+		return nil, &FlagError{Flag: "--filter-size", Field: "filter_size", Err: err}
+	}
+
+	// Requests
+	cfg.TLSVerify = req.TLSVerify
+	if cfg.CertPins, err = ParseCertPins(req.Pins); err != nil {
+		return nil, &FlagError{Flag: "--pin", Field: "pins", Err: err}
+	}
+	if cfg.Headers, err = HeadersFromMap(req.Headers); err != nil {
+		return nil, &FlagError{Flag: "-H", Field: "headers", Err: err}
+	}
+	cfg.AuthBasic, cfg.AuthBearer = req.AuthBasic, req.AuthBearer
+	if cfg.Cookies, err = ParseCookieHeader(req.Cookies); err != nil {
+		return nil, &FlagError{Flag: "--cookie", Field: "cookies", Err: err}
+	}
+	cfg.CookieJar = req.CookieJar
+	cfg.Data = []byte(req.Data)
+	if cfg.Method, cfg.ContentType, err = ResolveRequest(req.Method, req.ContentType, cfg.Data); err != nil {
+		return nil, &FlagError{Flag: "--method", Field: "method", Err: err}
+	}
+	if cfg.Proxy, err = ParseProxy(req.Proxy); err != nil {
+		return nil, &FlagError{Flag: "--proxy", Field: "proxy", Err: err}
+	}
+	cfg.Retries, cfg.NoRedirects = req.Retries, req.NoRedirects
+	if req.MaxRedirects != 0 {
+		cfg.MaxRedirects = req.MaxRedirects
+	}
+	cfg.Evasion = req.Evasion
+	for _, vhost := range req.VHosts {
+		if err := ValidateHostHeader(vhost); err != nil {
+			return nil, &FlagError{Flag: "--vhost-list", Field: "vhosts", Err: err}
+		}
+		cfg.VHosts = append(cfg.VHosts, strings.ToLower(vhost))
+	}
+
+	// Targets
+	cfg.Crawl, cfg.Sitemap, cfg.Shuffle, cfg.Interleave = req.Crawl, req.Sitemap, req.Shuffle, req.Interleave
+	if req.CrawlDepth != 0 {
+		cfg.CrawlDepth = req.CrawlDepth
+	}
+	if req.CrawlMaxPages != 0 {
+		cfg.CrawlMaxPages = req.CrawlMaxPages
+	}
+	if req.CrawlScope != "" {
+		cfg.CrawlScope = req.CrawlScope
+	}
+	if req.CrawlExclude != "" {
+		if cfg.CrawlExclude, err = regexp.Compile(req.CrawlExclude); err != nil {
+			return nil, &FlagError{Flag: "--crawl-exclude", Field: "crawl_exclude", Err: err}
+		}
+	}
+	if req.Campaign != "" {
+		if err := campaign.ValidateName(req.Campaign); err != nil {
+			return nil, &FlagError{Flag: "--campaign", Field: "campaign", Err: err}
+		}
+	}
+
+	// Rules: recipes, selectors and JSONPaths, as --recipe, --match-selector and --match-jsonpath
+	for _, name := range req.Recipes {
+		recipeRules, err := rules.Recipe(name)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidRule, err)
+		}
+		cfg.Recipes = append(cfg.Recipes, name)
+		cfg.Rules = append(cfg.Rules, recipeRules...)
+	}
+	if len(req.Selectors) > 0 {
+		selectorRules, err := rules.SelectorRules(req.Selectors)
+		if err != nil {
+			return nil, fmt.Errorf("%w: selectors: %w", ErrInvalidRule, err)
+		}
+		cfg.Selectors = req.Selectors
+		cfg.Rules = append(cfg.Rules, selectorRules...)
+	}
+	if len(req.JSONPaths) > 0 {
+		jsonPathRules, err := rules.JSONPathRules(req.JSONPaths)
+		if err != nil {
+			return nil, fmt.Errorf("%w: jsonpaths: %w", ErrInvalidRule, err)
+		}
+		cfg.JSONPaths = req.JSONPaths
+		cfg.Rules = append(cfg.Rules, jsonPathRules...)
+	}
+	cfg.TechDetect = req.TechDetect || rules.NeedsTech(cfg.Rules) || len(cfg.KeywordScope.Tech) > 0
+	if cfg.TechDetect {
+		cfg.Fingerprints = fingerprint.Defaults()
+	}
+
+	// Output targets: Elasticsearch and notifications (files stay a CLI matter)
+	cfg.ESURL = req.ESURL
+	if req.ESIndex != "" {
+		cfg.ESIndex = req.ESIndex
+	}
+	if cfg.Fields, err = types.ParseFields(req.Fields); err != nil {
+		return nil, &FlagError{Flag: "--fields", Field: "fields", Err: err}
+	}
+	for _, t := range req.Notify {
+		p, err := notify.ProviderConfig{Type: t.Type, URL: t.URL, Token: t.Token, ChatID: t.ChatID}.Provider()
+		if err != nil {
+			return nil, &FlagError{Flag: "--notify-config", Field: "notify", Err: err}
+		}
+		cfg.Notifiers = append(cfg.Notifiers, p)
+	}
+	cfg.NotifyEvents = req.NotifyOn
+
+	if err := cfg.ValidateScan(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+)
+
+// CertPin lists the leaf certificate fingerprints expected for hosts matching a pattern.
+type CertPin struct {
+	HostPattern  string   // Hostname or glob, e.g. "*.example.com"
+	Fingerprints []string // Lowercase hex SHA-256 of the DER-encoded leaf certificate
+}
+
+// Matches reports whether host is covered by the pin's pattern.
+func (p CertPin) Matches(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if ok, _ := path.Match(p.HostPattern, host); ok {
+		return true
+	}
+	// "*.example.com" also covers nested subdomains
+	if strings.HasPrefix(p.HostPattern, "*.") {
+		return strings.HasSuffix(host, p.HostPattern[1:])
+	}
+	return false
+}
+
+// Accepts reports whether fingerprint is one of the pinned fingerprints.
+func (p CertPin) Accepts(fingerprint string) bool {
+	for _, fp := range p.Fingerprints {
+		if fp == fingerprint {
+			return true
+		}
+	}
+	return false
+}
+
+// FindPin returns the first pin whose pattern matches host, or nil.
+func FindPin(pins []CertPin, host string) *CertPin {
+	for i := range pins {
+		if pins[i].Matches(host) {
+			return &pins[i]
+		}
+	}
+	return nil
+}
+
+// ParseCertPin parses "host-pattern=sha256/<hex>[|sha256/<hex>...]". The
+// fingerprint may use colons (as printed by openssl) and any case.
+func ParseCertPin(raw string) (CertPin, error) {
+	pattern, fps, ok := strings.Cut(strings.TrimSpace(raw), "=")
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	if !ok || pattern == "" {
+		return CertPin{}, fmt.Errorf("invalid pin %q, expected host-pattern=sha256/<fingerprint>", raw)
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return CertPin{}, fmt.Errorf("invalid host pattern %q: %v", pattern, err)
+	}
+
+	pin := CertPin{HostPattern: pattern}
+	for _, fp := range strings.Split(fps, "|") {
+		fp = strings.TrimSpace(fp)
+		fp = strings.TrimPrefix(strings.TrimPrefix(fp, "sha256/"), "sha256:")
+		fp = strings.ToLower(strings.ReplaceAll(fp, ":", ""))
+		if b, err := hex.DecodeString(fp); err != nil || len(b) != 32 {
+			return CertPin{}, fmt.Errorf("invalid SHA-256 fingerprint %q for %s", fp, pattern)
+		}
+		pin.Fingerprints = append(pin.Fingerprints, fp)
+	}
+	return pin, nil
+}
+
+// ParseCertPins parses a list of pins (see ParseCertPin).
+func ParseCertPins(raw []string) ([]CertPin, error) {
+	var pins []CertPin
+	for _, r := range raw {
+		pin, err := ParseCertPin(r)
+		if err != nil {
+			return nil, err
+		}
+		pins = append(pins, pin)
+	}
+	return pins, nil
+}
+
+// FormatCertPins renders pins back into their flag form.
+func FormatCertPins(pins []CertPin) []string {
+	var out []string
+	for _, p := range pins {
+		fps := make([]string, len(p.Fingerprints))
+		for i, fp := range p.Fingerprints {
+			fps[i] = "sha256/" + fp
+		}
+		out = append(out, p.HostPattern+"="+strings.Join(fps, "|"))
+	}
+	return out
+}
+
+// stringList is a repeatable string flag.
+type stringList []string
+
+func (s *stringList) String() string { return strings.Join(*s, ", ") }
+
+func (s *stringList) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
+// LoadCertPool returns the system roots plus the PEM certificates in caFile
+// (--ca-cert).
+func LoadCertPool(caFile string) (*x509.CertPool, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	data, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no PEM certificates found in %s", caFile)
+	}
+	return pool, nil
+}
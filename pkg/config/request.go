@@ -0,0 +1,171 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const (
+	maxRetries   = 10 // Highest --retries
+	maxRedirects = 50 // Highest --max-redirects
+)
+
+// ResolveRequest fills in the request method and Content-Type the way curl
+// does: sending data defaults to POST, and data without an explicit type is
+// sent as JSON if it parses as JSON, otherwise as a urlencoded form.
+func ResolveRequest(method, contentType string, data []byte) (string, string, error) {
+	method = strings.ToUpper(strings.TrimSpace(method))
+	if method == "" {
+		method = http.MethodGet
+		if len(data) > 0 {
+			method = http.MethodPost
+		}
+	}
+	for _, c := range method {
+		if c < 'A' || c > 'Z' {
+			return "", "", fmt.Errorf("invalid method %q", method)
+		}
+	}
+	if contentType == "" && len(data) > 0 {
+		contentType = "application/x-www-form-urlencoded"
+		if json.Valid(data) {
+			contentType = "application/json"
+		}
+	}
+	return method, contentType, nil
+}
+
+// ParseHeaders parses "Name: value" strings (-H) into a header set. Repeated
+// names keep every value.
+func ParseHeaders(lines []string) (http.Header, error) {
+	if len(lines) == 0 {
+		return nil, nil
+	}
+	headers := make(http.Header)
+	for _, line := range lines {
+		name, value, ok := strings.Cut(line, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || strings.ContainsAny(name, " \t") {
+			return nil, fmt.Errorf("invalid header %q (want \"Name: value\")", line)
+		}
+		headers.Add(name, strings.TrimSpace(value))
+	}
+	return headers, nil
+}
+
+// HeaderMap flattens headers for the API request, joining repeated values
+// with ", ".
+func HeaderMap(headers http.Header) map[string]string {
+	if len(headers) == 0 {
+		return nil
+	}
+	m := make(map[string]string, len(headers))
+	for name, values := range headers {
+		m[name] = strings.Join(values, ", ")
+	}
+	return m
+}
+
+// HeadersFromMap converts an API headers map into a header set.
+func HeadersFromMap(m map[string]string) (http.Header, error) {
+	lines := make([]string, 0, len(m))
+	for name, value := range m {
+		lines = append(lines, name+": "+value)
+	}
+	return ParseHeaders(lines)
+}
+
+// ValidateAuth checks the --auth-basic/--auth-bearer combination.
+func ValidateAuth(basic, bearer string) error {
+	if basic != "" && bearer != "" {
+		return fmt.Errorf("%w: use either --auth-basic or --auth-bearer, not both", ErrUsage)
+	}
+	if basic != "" && !strings.Contains(basic, ":") {
+		return &FlagError{Flag: "--auth-basic", Err: fmt.Errorf("want user:pass")}
+	}
+	return nil
+}
+
+// LoadVHosts reads virtual host names (--vhost-list), one per line, with an
+// optional :port. Blank lines and # comments are skipped.
+func LoadVHosts(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var vhosts []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := ValidateHostHeader(line); err != nil {
+			return nil, err
+		}
+		vhosts = append(vhosts, strings.ToLower(line))
+	}
+	if len(vhosts) == 0 {
+		return nil, fmt.Errorf("%s lists no virtual hosts", path)
+	}
+	return vhosts, nil
+}
+
+// ParseProxy parses the proxy every request goes through (--proxy): an
+// http, https or socks5 URL, optionally with user:pass.
+func ParseProxy(raw string) (*url.URL, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q", raw)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+		return u, nil
+	default:
+		return nil, fmt.Errorf("proxy scheme must be http, https or socks5, got %q", u.Scheme)
+	}
+}
+
+// ValidateRetries checks the number of retries of a request that got no
+// response (--retries).
+func ValidateRetries(n int) error {
+	if n < 0 || n > maxRetries {
+		return fmt.Errorf("must be between 0 and %d, got %d", maxRetries, n)
+	}
+	return nil
+}
+
+// ValidateMaxRedirects checks the number of redirects followed per request
+// (--max-redirects).
+func ValidateMaxRedirects(n int) error {
+	if n < 1 || n > maxRedirects {
+		return fmt.Errorf("must be between 1 and %d, got %d", maxRedirects, n)
+	}
+	return nil
+}
+
+// ValidateStatusCodes checks a list of HTTP status codes (100-599), as
+// --match-code and --filter-code accept.
+func ValidateStatusCodes(codes []int) error {
+	for _, code := range codes {
+		if code < 100 || code > 599 {
+			return fmt.Errorf("invalid status code %d", code)
+		}
+	}
+	return nil
+}
+
+// ValidateHostHeader checks a Host header value: a hostname or IP, optionally
+// with a port, and nothing else.
+func ValidateHostHeader(host string) error {
+	if host == "" || strings.ContainsAny(host, " \t/\\?#@") {
+		return fmt.Errorf("invalid host %q", host)
+	}
+	return nil
+}
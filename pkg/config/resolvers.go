@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// ParseResolvers parses a comma-separated --resolvers list. Each entry is an
+// IP (port 53 is implied), ip:port, [ipv6]:port or a DNS-over-HTTPS URL such
+// as https://1.1.1.1/dns-query.
+func ParseResolvers(raw string) ([]string, error) {
+	var servers []string
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if strings.HasPrefix(entry, "https://") {
+			u, err := url.Parse(entry)
+			if err != nil || u.Host == "" {
+				return nil, fmt.Errorf("invalid DoH resolver %q", entry)
+			}
+			servers = append(servers, entry)
+			continue
+		}
+		if ip := net.ParseIP(entry); ip != nil {
+			servers = append(servers, net.JoinHostPort(ip.String(), "53"))
+			continue
+		}
+		host, port, err := net.SplitHostPort(entry)
+		if err != nil || net.ParseIP(host) == nil || port == "" {
+			return nil, fmt.Errorf("invalid resolver %q, expected an IP, ip:port or https:// DoH URL", entry)
+		}
+		servers = append(servers, net.JoinHostPort(host, port))
+	}
+	return servers, nil
+}
+
+// ParseResolve parses --resolve entries of the form host:ip (the IP may be
+// IPv6, optionally in brackets) into a map keyed by lowercase host.
+func ParseResolve(entries []string) (map[string]string, error) {
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	hosts := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		host, addr, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		host = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(host), "."))
+		ip := net.ParseIP(strings.Trim(strings.TrimSpace(addr), "[]"))
+		if !ok || host == "" || ip == nil {
+			return nil, fmt.Errorf("invalid entry %q, expected host:ip", entry)
+		}
+		if prev, dup := hosts[host]; dup && prev != ip.String() {
+			return nil, fmt.Errorf("host %s is pinned to both %s and %s", host, prev, ip)
+		}
+		hosts[host] = ip.String()
+	}
+	return hosts, nil
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultMaxBodySize is the default cap on downloaded response bodies.
+const DefaultMaxBodySize = 10 * 1024 * 1024
+
+// ParseByteSize parses a byte count with an optional KB/MB/GB suffix
+// (binary multiples), e.g. "512", "64KB", "10MB".
+func ParseByteSize(raw string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(raw))
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix string
+		mult   int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10}, {"B", 1}} {
+		if strings.HasSuffix(s, unit.suffix) {
+			s = strings.TrimSpace(strings.TrimSuffix(s, unit.suffix))
+			multiplier = unit.mult
+			break
+		}
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid byte size %q", raw)
+	}
+	return n * multiplier, nil
+}
+
+// SizeRange is an inclusive range of response body sizes in bytes.
+// A negative Max means there is no upper bound.
+type SizeRange struct {
+	Min int64
+	Max int64
+}
+
+// Contains reports whether size falls within the range.
+func (r SizeRange) Contains(size int64) bool {
+	return size >= r.Min && (r.Max < 0 || size <= r.Max)
+}
+
+// String renders the range in the syntax accepted by ParseSizeRanges.
+func (r SizeRange) String() string {
+	switch {
+	case r.Max < 0:
+		return fmt.Sprintf(">=%d", r.Min)
+	case r.Min == r.Max:
+		return strconv.FormatInt(r.Min, 10)
+	default:
+		return fmt.Sprintf("%d-%d", r.Min, r.Max)
+	}
+}
+
+// FormatSizeRanges renders ranges as a comma-separated expression list.
+func FormatSizeRanges(ranges []SizeRange) string {
+	parts := make([]string, len(ranges))
+	for i, r := range ranges {
+		parts[i] = r.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// ParseSizeRanges parses a comma-separated list of size expressions:
+// "1024" (exact), ">1024", ">=1024", "<500", "<=500" and "100-2000".
+func ParseSizeRanges(raw string) ([]SizeRange, error) {
+	var ranges []SizeRange
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		r, err := parseSizeRange(part)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges, nil
+}
+
+func parseSizeRange(expr string) (SizeRange, error) {
+	parseNum := func(s string) (int64, error) {
+		n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid size expression %q", expr)
+		}
+		return n, nil
+	}
+
+	switch {
+	case strings.HasPrefix(expr, ">="):
+		n, err := parseNum(expr[2:])
+		return SizeRange{Min: n, Max: -1}, err
+	case strings.HasPrefix(expr, ">"):
+		n, err := parseNum(expr[1:])
+		return SizeRange{Min: n + 1, Max: -1}, err
+	case strings.HasPrefix(expr, "<="):
+		n, err := parseNum(expr[2:])
+		return SizeRange{Min: 0, Max: n}, err
+	case strings.HasPrefix(expr, "<"):
+		n, err := parseNum(expr[1:])
+		if err == nil && n == 0 {
+			err = fmt.Errorf("invalid size expression %q", expr)
+		}
+		return SizeRange{Min: 0, Max: n - 1}, err
+	case strings.Contains(expr, "-"):
+		bounds := strings.SplitN(expr, "-", 2)
+		lo, err := parseNum(bounds[0])
+		if err != nil {
+			return SizeRange{}, err
+		}
+		hi, err := parseNum(bounds[1])
+		if err != nil {
+			return SizeRange{}, err
+		}
+		if lo > hi {
+			return SizeRange{}, fmt.Errorf("invalid size range %q (min > max)", expr)
+		}
+		return SizeRange{Min: lo, Max: hi}, nil
+	default:
+		n, err := parseNum(expr)
+		return SizeRange{Min: n, Max: n}, err
+	}
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Exit codes of the `verify` subcommand.
+const (
+	VerifyExitMatch    = 0 // The report was produced from the given inputs
+	VerifyExitMismatch = 1 // Targets or rules differ from the recorded digest
+	VerifyExitError    = 2 // Bad arguments, unreadable report or no digest recorded
+)
+
+// VerifyConfig holds the settings for the `verify` subcommand.
+type VerifyConfig struct {
+	Report string
+	Scan   *Config // The scan flags, parsed exactly as for the original scan
+}
+
+// ParseVerifyFlags parses `hx-hawks verify report.json <scan flags>`, where
+// the scan flags (-f, --ck, --rules, --recipe, ...) are those of the scan
+// that produced the report.
+func ParseVerifyFlags(args []string) (*VerifyConfig, error) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return nil, fmt.Errorf("%w (usage: hx-hawks verify report.json -f targets.txt [--ck ...] [--rules ...] [--recipe ...])", ErrUsage)
+	}
+	os.Args = append([]string{os.Args[0]}, args[1:]...)
+	scan, err := ParseFlags()
+	if err != nil {
+		return nil, err
+	}
+	if scan.InputFile == "" {
+		return nil, fmt.Errorf("%w: the targets file (-f) of the scan is required", ErrUsage)
+	}
+	return &VerifyConfig{Report: args[0], Scan: scan}, nil
+}
@@ -0,0 +1,212 @@
+// Package diff compares two scan reports (-o-json or -o-all-json) and
+// summarizes which findings are new, fixed or changed.
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+// Finding lists what matched at one URL.
+type Finding struct {
+	URL      string   `json:"url"`
+	Keywords []string `json:"keywords,omitempty"`
+	Rules    []string `json:"rules,omitempty"`
+}
+
+// Change is a URL that is vulnerable in both reports with different findings.
+type Change struct {
+	URL     string   `json:"url"`
+	Added   []string `json:"added,omitempty"`   // Findings only in the new report ("keyword:x" / "rule:id")
+	Removed []string `json:"removed,omitempty"` // Findings only in the old report
+}
+
+// Counts is the short summary CI jobs gate on.
+type Counts struct {
+	New       int `json:"new"`
+	Fixed     int `json:"fixed"`
+	Changed   int `json:"changed"`
+	Unchanged int `json:"unchanged"` // Vulnerable in both with the same findings
+}
+
+// Report is the result of comparing two scans.
+type Report struct {
+	Old       string    `json:"old"`
+	New       string    `json:"new"`
+	Counts    Counts    `json:"counts"`
+	Regressed bool      `json:"regressed"` // New findings, or added findings on a known URL
+	NewURLs   []Finding `json:"new_findings,omitempty"`
+	Fixed     []Finding `json:"fixed,omitempty"`
+	Changed   []Change  `json:"changed,omitempty"`
+}
+
+// Load reads a JSON report written with -o-json or -o-all-json.
+func Load(path string) ([]types.ScanResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var results []types.ScanResult
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("parsing report %s: %w", path, err)
+	}
+	return results, nil
+}
+
+// Compare diffs the vulnerable results of two scans by URL. A URL that is
+// missing from the new scan is not counted as fixed, since it wasn't checked.
+func Compare(oldResults, newResults []types.ScanResult) *Report {
+	oldFindings, _ := index(oldResults)
+	newFindings, newSeen := index(newResults)
+	report := &Report{}
+
+	for _, u := range sortedKeys(newFindings) {
+		nf := newFindings[u]
+		of, wasVulnerable := oldFindings[u]
+		if !wasVulnerable {
+			report.NewURLs = append(report.NewURLs, nf)
+			continue
+		}
+		added, removed := difference(signatures(nf), signatures(of))
+		if len(added) == 0 && len(removed) == 0 {
+			report.Counts.Unchanged++
+			continue
+		}
+		report.Changed = append(report.Changed, Change{URL: u, Added: added, Removed: removed})
+		if len(added) > 0 {
+			report.Regressed = true
+		}
+	}
+	for _, u := range sortedKeys(oldFindings) {
+		if _, still := newFindings[u]; !still && newSeen[u] {
+			report.Fixed = append(report.Fixed, oldFindings[u])
+		}
+	}
+
+	report.Counts.New = len(report.NewURLs)
+	report.Counts.Fixed = len(report.Fixed)
+	report.Counts.Changed = len(report.Changed)
+	if report.Counts.New > 0 {
+		report.Regressed = true
+	}
+	return report
+}
+
+// ShouldFail reports whether the comparison fails under the --fail-on policy.
+func (r *Report) ShouldFail(failOn string) bool {
+	switch failOn {
+	case "never":
+		return false
+	case "any":
+		return r.Counts.New > 0 || r.Counts.Fixed > 0 || r.Counts.Changed > 0
+	}
+	return r.Regressed
+}
+
+// WriteFile saves the report as indented JSON.
+func (r *Report) WriteFile(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// Print writes a human-readable summary.
+func (r *Report) Print(w io.Writer) {
+	fmt.Fprintf(w, "Comparing %s -> %s\n\n", r.Old, r.New)
+	for _, f := range r.NewURLs {
+		fmt.Fprintf(w, "  NEW      %s %v\n", f.URL, signatures(f))
+	}
+	for _, c := range r.Changed {
+		fmt.Fprintf(w, "  CHANGED  %s", c.URL)
+		if len(c.Added) > 0 {
+			fmt.Fprintf(w, " +%v", c.Added)
+		}
+		if len(c.Removed) > 0 {
+			fmt.Fprintf(w, " -%v", c.Removed)
+		}
+		fmt.Fprintln(w)
+	}
+	for _, f := range r.Fixed {
+		fmt.Fprintf(w, "  FIXED    %s %v\n", f.URL, signatures(f))
+	}
+	fmt.Fprintf(w, "\nNew: %d, Fixed: %d, Changed: %d, Unchanged: %d\n", r.Counts.New, r.Counts.Fixed, r.Counts.Changed, r.Counts.Unchanged)
+	if r.Regressed {
+		fmt.Fprintln(w, "Security posture regressed.")
+	}
+}
+
+// index maps each vulnerable URL to its findings and records every URL that
+// was scanned without an error.
+func index(results []types.ScanResult) (map[string]Finding, map[string]bool) {
+	findings := make(map[string]Finding)
+	seen := make(map[string]bool)
+	for _, r := range results {
+		if r.Error != "" {
+			continue
+		}
+		seen[r.URL] = true
+		// -o-json reports carry matches but no is_vulnerable flag
+		if !r.IsVulnerable && len(r.MatchedKeywords) == 0 && len(r.MatchedRules) == 0 {
+			continue
+		}
+		f := findings[r.URL]
+		f.URL = r.URL
+		f.Keywords = append(f.Keywords, r.MatchedKeywords...)
+		for _, m := range r.MatchedRules {
+			f.Rules = append(f.Rules, m.ID)
+		}
+		findings[r.URL] = f
+	}
+	return findings, seen
+}
+
+// signatures flattens a finding into sorted, de-duplicated "keyword:x" /
+// "rule:id" entries.
+func signatures(f Finding) []string {
+	set := make(map[string]bool)
+	for _, k := range f.Keywords {
+		set["keyword:"+k] = true
+	}
+	for _, id := range f.Rules {
+		set["rule:"+id] = true
+	}
+	return sortedKeys(set)
+}
+
+// difference returns the entries only in a and only in b.
+func difference(a, b []string) (onlyA, onlyB []string) {
+	inA := make(map[string]bool, len(a))
+	for _, s := range a {
+		inA[s] = true
+	}
+	inB := make(map[string]bool, len(b))
+	for _, s := range b {
+		inB[s] = true
+		if !inA[s] {
+			onlyB = append(onlyB, s)
+		}
+	}
+	for _, s := range a {
+		if !inB[s] {
+			onlyA = append(onlyA, s)
+		}
+	}
+	return onlyA, onlyB
+}
+
+// sortedKeys returns the keys of m in order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
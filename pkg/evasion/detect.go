@@ -0,0 +1,60 @@
+package evasion
+
+import (
+	"bytes"
+	"net/http"
+)
+
+// blockSignature identifies a WAF/CDN block or challenge page.
+type blockSignature struct {
+	name   string
+	header string // Header whose presence (or value, if set) identifies the block
+	value  string
+	body   []byte // Body fragment identifying the block page
+}
+
+// blockSignatures are checked on 401/403/406/429/503 responses.
+var blockSignatures = []blockSignature{
+	{name: "cloudflare", header: "Cf-Mitigated", value: "challenge"},
+	{name: "cloudflare", body: []byte("Attention Required! | Cloudflare")},
+	{name: "cloudflare", body: []byte("cf-browser-verification")},
+	{name: "cloudflare", body: []byte("cf_chl_opt")},
+	{name: "akamai", body: []byte("Reference&#32;&#35;")},
+	{name: "akamai", body: []byte("You don't have permission to access")},
+	{name: "aws-waf", body: []byte("Request blocked.")},
+	{name: "imperva", body: []byte("Incapsula incident ID")},
+	{name: "imperva", header: "X-Iinfo"},
+	{name: "sucuri", body: []byte("Sucuri WebSite Firewall")},
+	{name: "modsecurity", body: []byte("Mod_Security")},
+	{name: "modsecurity", body: []byte("This error was generated by Mod_Security")},
+	{name: "f5-bigip", body: []byte("The requested URL was rejected. Please consult with your administrator.")},
+	{name: "ddos-guard", body: []byte("DDoS-Guard")},
+	{name: "generic", body: []byte("Access Denied")},
+}
+
+// DetectBlock reports whether a response looks like a WAF/CDN block or rate
+// limit rather than real content, and why (e.g. "rate-limited", "waf:cloudflare").
+func DetectBlock(status int, header http.Header, body []byte) (string, bool) {
+	if status == http.StatusTooManyRequests {
+		return "rate-limited", true
+	}
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotAcceptable, http.StatusServiceUnavailable:
+	default:
+		return "", false
+	}
+
+	for _, sig := range blockSignatures {
+		if sig.header != "" {
+			v := header.Get(sig.header)
+			if v != "" && (sig.value == "" || v == sig.value) {
+				return "waf:" + sig.name, true
+			}
+			continue
+		}
+		if bytes.Contains(body, sig.body) {
+			return "waf:" + sig.name, true
+		}
+	}
+	return "", false
+}
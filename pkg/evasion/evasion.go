@@ -0,0 +1,167 @@
+package evasion
+
+import (
+	"fmt"
+	"math/rand"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// userAgents are rotated through for hosts under evasion.
+var userAgents = []string{
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Safari/605.1.15",
+	"Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0",
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:121.0) Gecko/20100101 Firefox/121.0",
+	"Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Mobile/15E148 Safari/604.1",
+	"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0",
+}
+
+// Profile is what gets applied to a host once it is considered blocking.
+type Profile struct {
+	Threshold int           // Consecutive blocked responses before evasion kicks in
+	Delay     time.Duration // Minimum interval between requests to the host
+	Jitter    float64       // Random +/- fraction applied to Delay (0.5 = +/-50%)
+	Proxies   []*url.URL    // Alternate proxies rotated per request (optional)
+}
+
+// Plan is the evasion applied to a single request.
+type Plan struct {
+	Wait      time.Duration // Sleep before sending the request
+	UserAgent string        // User-Agent override ("" = default)
+	Proxy     *url.URL      // Proxy override (nil = default)
+	Applied   []string      // Human-readable record of what was applied
+}
+
+// hostState tracks blocks and evasion for one host.
+type hostState struct {
+	consecutive int       // Blocked responses in a row
+	blocked     int       // Total blocked responses
+	active      bool      // Evasion profile applied
+	next        time.Time // Earliest time for the next request
+	rotation    int       // UA/proxy rotation counter
+}
+
+// Controller keeps per-host block state and hands out evasion plans.
+// It is safe for concurrent use by all workers.
+type Controller struct {
+	profile Profile
+	mu      sync.Mutex
+	hosts   map[string]*hostState
+	rng     *rand.Rand
+}
+
+// NewController creates a controller applying profile to blocking hosts.
+func NewController(profile Profile) *Controller {
+	if profile.Threshold <= 0 {
+		profile.Threshold = 3
+	}
+	return &Controller{
+		profile: profile,
+		hosts:   make(map[string]*hostState),
+		rng:     rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// state returns the host's state, creating it. Caller holds mu.
+func (c *Controller) state(host string) *hostState {
+	st, ok := c.hosts[host]
+	if !ok {
+		st = &hostState{}
+		c.hosts[host] = st
+	}
+	return st
+}
+
+// Plan returns the evasion to apply to the next request to host. Hosts that
+// have not crossed the block threshold get an empty plan. A nil controller
+// always returns an empty plan.
+func (c *Controller) Plan(host string) Plan {
+	if c == nil {
+		return Plan{}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	st := c.state(host)
+	if !st.active {
+		return Plan{}
+	}
+
+	var plan Plan
+	now := time.Now()
+	if st.next.After(now) {
+		plan.Wait = st.next.Sub(now)
+	}
+	interval := c.profile.Delay
+	if c.profile.Jitter > 0 && interval > 0 {
+		interval += time.Duration((c.rng.Float64()*2 - 1) * c.profile.Jitter * float64(interval))
+	}
+	st.next = now.Add(plan.Wait + interval)
+	if plan.Wait > 0 {
+		plan.Applied = append(plan.Applied, fmt.Sprintf("waited=%s", plan.Wait.Round(time.Millisecond)))
+	}
+	if c.profile.Delay > 0 {
+		plan.Applied = append(plan.Applied, fmt.Sprintf("interval=%s+/-%.0f%%", c.profile.Delay, c.profile.Jitter*100))
+	}
+
+	plan.UserAgent = userAgents[st.rotation%len(userAgents)]
+	plan.Applied = append(plan.Applied, "ua-rotated")
+	if len(c.profile.Proxies) > 0 {
+		plan.Proxy = c.profile.Proxies[st.rotation%len(c.profile.Proxies)]
+		plan.Applied = append(plan.Applied, "proxy="+plan.Proxy.Redacted())
+	}
+	st.rotation++
+	return plan
+}
+
+// Observe records whether a response from host was blocked. It returns true
+// if this observation switched the host into evasion mode.
+func (c *Controller) Observe(host string, blocked bool) bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	st := c.state(host)
+	if !blocked {
+		st.consecutive = 0
+		return false
+	}
+	st.consecutive++
+	st.blocked++
+	if !st.active && st.consecutive >= c.profile.Threshold {
+		st.active = true
+		return true
+	}
+	return false
+}
+
+// Active reports whether host is currently under evasion.
+func (c *Controller) Active(host string) bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	st, ok := c.hosts[host]
+	return ok && st.active
+}
+
+// EvadedHosts returns the hosts that were switched into evasion mode.
+func (c *Controller) EvadedHosts() []string {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var hosts []string
+	for h, st := range c.hosts {
+		if st.active {
+			hosts = append(hosts, h)
+		}
+	}
+	return hosts
+}
@@ -0,0 +1,43 @@
+package fingerprint
+
+// defaults are the built-in fingerprints for common servers, frameworks and CMSs.
+var defaults = []Fingerprint{
+	// Web servers and proxies
+	{Name: "nginx", Headers: map[string]string{"Server": `nginx(?:/([\d.]+))?`}},
+	{Name: "Apache", Headers: map[string]string{"Server": `apache(?:/([\d.]+))?`}},
+	{Name: "Microsoft-IIS", Headers: map[string]string{"Server": `Microsoft-IIS(?:/([\d.]+))?`}},
+	{Name: "LiteSpeed", Headers: map[string]string{"Server": `LiteSpeed`}},
+	{Name: "Caddy", Headers: map[string]string{"Server": `^Caddy`}},
+	{Name: "Apache Tomcat", Headers: map[string]string{"Server": `Apache-Coyote`}, Body: []string{`Apache Tomcat/([\d.]+)`}},
+	{Name: "Cloudflare", Headers: map[string]string{"Server": `cloudflare`, "CF-RAY": ``}},
+	{Name: "Varnish", Headers: map[string]string{"X-Varnish": ``, "Via": `varnish`}},
+	{Name: "Amazon CloudFront", Headers: map[string]string{"X-Amz-Cf-Id": ``}},
+	{Name: "Akamai", Headers: map[string]string{"X-Akamai-Transformed": ``, "Server": `AkamaiGHost`}},
+
+	// Languages and frameworks
+	{Name: "PHP", Headers: map[string]string{"X-Powered-By": `PHP(?:/([\d.]+))?`}, Cookies: map[string]string{"PHPSESSID": ``}},
+	{Name: "ASP.NET", Headers: map[string]string{"X-AspNet-Version": `([\d.]+)`, "X-Powered-By": `ASP\.NET`}, Cookies: map[string]string{"ASP.NET_SessionId": ``}},
+	{Name: "Express", Headers: map[string]string{"X-Powered-By": `^Express$`}},
+	{Name: "Next.js", Headers: map[string]string{"X-Powered-By": `Next\.js ?([\d.]+)?`}, Body: []string{`<script id="__NEXT_DATA__"`}},
+	{Name: "Laravel", Cookies: map[string]string{"laravel_session": ``}},
+	{Name: "Django", Cookies: map[string]string{"csrftoken": ``}, Body: []string{`name=["']csrfmiddlewaretoken["']`}},
+	{Name: "Ruby on Rails", Headers: map[string]string{"X-Powered-By": `Phusion Passenger`}, Cookies: map[string]string{"_rails_session": ``}, Meta: map[string]string{"csrf-param": `^authenticity_token$`}},
+	{Name: "Java Servlet", Cookies: map[string]string{"JSESSIONID": ``}},
+	{Name: "Spring Boot", Body: []string{`Whitelabel Error Page`}},
+
+	// CMSs and applications
+	{Name: "WordPress", Meta: map[string]string{"generator": `WordPress ?([\d.]+)?`}, Body: []string{`/wp-(?:content|includes)/`}},
+	{Name: "Drupal", Headers: map[string]string{"X-Generator": `Drupal ?(\d+)?`, "X-Drupal-Cache": ``}, Meta: map[string]string{"generator": `Drupal ?(\d+)?`}},
+	{Name: "Joomla", Meta: map[string]string{"generator": `Joomla!? ?([\d.]+)?`}},
+	{Name: "Magento", Cookies: map[string]string{"frontend": ``}, Body: []string{`Mage\.Cookies`}},
+	{Name: "Shopify", Headers: map[string]string{"X-ShopId": ``}, Body: []string{`cdn\.shopify\.com`}},
+	{Name: "Jenkins", Headers: map[string]string{"X-Jenkins": `([\d.]+)`}},
+	{Name: "GitLab", Meta: map[string]string{"og:site_name": `^GitLab$`}, Body: []string{`gon\.gitlab_url`}},
+	{Name: "Grafana", Body: []string{`window\.grafanaBootData`}},
+
+	// JavaScript libraries
+	{Name: "jQuery", Body: []string{`jquery[.-]([\d.]+)(?:\.min)?\.js`, `/jquery(?:\.min)?\.js`}},
+	{Name: "React", Body: []string{`data-reactroot`}},
+	{Name: "Angular", Body: []string{`ng-version="([\d.]+)"`}},
+	{Name: "Vue.js", Body: []string{`data-v-[0-9a-f]{8}`, `vue(?:\.min)?\.js`}},
+}
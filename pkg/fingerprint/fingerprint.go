@@ -0,0 +1,224 @@
+package fingerprint
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Fingerprint is a Wappalyzer-style technology signature. A technology is
+// detected when any of its header, cookie, meta or body patterns matches.
+// Patterns are regexes; an empty pattern only checks for presence. The first
+// capture group of a matching pattern, if any, is reported as the version.
+type Fingerprint struct {
+	Name    string            `yaml:"name"`
+	Headers map[string]string `yaml:"headers,omitempty"` // Header name -> value pattern
+	Cookies map[string]string `yaml:"cookies,omitempty"` // Cookie name -> value pattern
+	Meta    map[string]string `yaml:"meta,omitempty"`    // <meta name=...> -> content pattern
+	Body    []string          `yaml:"body,omitempty"`    // Patterns matched against the response body
+
+	headers map[string]*regexp.Regexp
+	cookies map[string]*regexp.Regexp
+	meta    map[string]*regexp.Regexp
+	body    []*regexp.Regexp
+}
+
+// File is the top-level layout of a fingerprints YAML file.
+type File struct {
+	Fingerprints []Fingerprint `yaml:"fingerprints"`
+}
+
+// metaTag captures name/content pairs of <meta> tags (either attribute order).
+var metaTag = regexp.MustCompile(`(?is)<meta\s[^>]*?(?:name=["']([^"']+)["'][^>]*?content=["']([^"']*)["']|content=["']([^"']*)["'][^>]*?name=["']([^"']+)["'])`)
+
+// Load reads and compiles the fingerprints in a YAML file.
+func Load(path string) ([]Fingerprint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f File
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parsing fingerprints file %s: %w", path, err)
+	}
+	if err := Compile(f.Fingerprints); err != nil {
+		return nil, fmt.Errorf("fingerprints file %s: %w", path, err)
+	}
+	return f.Fingerprints, nil
+}
+
+// Defaults returns the compiled built-in fingerprints.
+func Defaults() []Fingerprint {
+	fps := make([]Fingerprint, len(defaults))
+	copy(fps, defaults)
+	if err := Compile(fps); err != nil {
+		panic("fingerprint: invalid built-in fingerprint: " + err.Error())
+	}
+	return fps
+}
+
+// Compile validates the fingerprints and compiles their patterns in place.
+func Compile(fps []Fingerprint) error {
+	for i := range fps {
+		fp := &fps[i]
+		if fp.Name == "" {
+			return fmt.Errorf("fingerprint #%d has no name", i+1)
+		}
+		var err error
+		if fp.headers, err = compileMap(fp.Headers, true); err != nil {
+			return fmt.Errorf("fingerprint %q: %w", fp.Name, err)
+		}
+		if fp.cookies, err = compileMap(fp.Cookies, false); err != nil {
+			return fmt.Errorf("fingerprint %q: %w", fp.Name, err)
+		}
+		if fp.meta, err = compileMap(fp.Meta, true); err != nil {
+			return fmt.Errorf("fingerprint %q: %w", fp.Name, err)
+		}
+		fp.body = nil
+		for _, p := range fp.Body {
+			re, err := regexp.Compile(p)
+			if err != nil {
+				return fmt.Errorf("fingerprint %q: invalid body pattern: %w", fp.Name, err)
+			}
+			fp.body = append(fp.body, re)
+		}
+	}
+	return nil
+}
+
+// compileMap compiles name -> pattern pairs, lower-casing names if asked.
+func compileMap(patterns map[string]string, lowerNames bool) (map[string]*regexp.Regexp, error) {
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+	out := make(map[string]*regexp.Regexp, len(patterns))
+	for name, p := range patterns {
+		if lowerNames {
+			name = strings.ToLower(name)
+		}
+		re, err := regexp.Compile("(?i)" + p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern for %q: %w", name, err)
+		}
+		out[name] = re
+	}
+	return out, nil
+}
+
+// Detect returns the technologies found in a response, in fingerprint order,
+// as "Name" or "Name/version".
+func Detect(fps []Fingerprint, header http.Header, body []byte) []string {
+	cookies := parseCookies(header)
+	var meta map[string]string
+	var found []string
+
+	for i := range fps {
+		fp := &fps[i]
+		version, ok := fp.matchHeaders(header)
+		if !ok {
+			version, ok = matchValues(fp.cookies, cookies)
+		}
+		if !ok && len(fp.meta) > 0 {
+			if meta == nil {
+				meta = parseMeta(body)
+			}
+			version, ok = matchValues(fp.meta, meta)
+		}
+		if !ok {
+			version, ok = fp.matchBody(body)
+		}
+		if !ok {
+			continue
+		}
+		if version != "" {
+			found = append(found, fp.Name+"/"+version)
+		} else {
+			found = append(found, fp.Name)
+		}
+	}
+	return found
+}
+
+// matchHeaders checks every header value (headers may repeat). Matches
+// carrying a version win over bare ones.
+func (fp *Fingerprint) matchHeaders(header http.Header) (string, bool) {
+	found := false
+	for name, re := range fp.headers {
+		for _, v := range header.Values(name) {
+			if m := re.FindStringSubmatch(v); m != nil {
+				if ver := version(m); ver != "" {
+					return ver, true
+				}
+				found = true
+			}
+		}
+	}
+	return "", found
+}
+
+// matchBody checks the body patterns.
+func (fp *Fingerprint) matchBody(body []byte) (string, bool) {
+	for _, re := range fp.body {
+		if m := re.FindSubmatch(body); m != nil {
+			if len(m) > 1 {
+				return string(m[1]), true
+			}
+			return "", true
+		}
+	}
+	return "", false
+}
+
+// matchValues checks name -> pattern pairs against parsed name -> value
+// pairs. Matches carrying a version win over bare ones.
+func matchValues(patterns map[string]*regexp.Regexp, values map[string]string) (string, bool) {
+	found := false
+	for name, re := range patterns {
+		v, ok := values[name]
+		if !ok {
+			continue
+		}
+		if m := re.FindStringSubmatch(v); m != nil {
+			if ver := version(m); ver != "" {
+				return ver, true
+			}
+			found = true
+		}
+	}
+	return "", found
+}
+
+// version returns the first capture group of a match, or "".
+func version(m []string) string {
+	if len(m) > 1 {
+		return m[1]
+	}
+	return ""
+}
+
+// parseCookies returns the cookies set by the response, by name.
+func parseCookies(header http.Header) map[string]string {
+	resp := http.Response{Header: header}
+	cookies := make(map[string]string)
+	for _, c := range resp.Cookies() {
+		cookies[c.Name] = c.Value
+	}
+	return cookies
+}
+
+// parseMeta returns the <meta name=... content=...> pairs of an HTML body, by lower-cased name.
+func parseMeta(body []byte) map[string]string {
+	meta := make(map[string]string)
+	for _, m := range metaTag.FindAllSubmatch(body, -1) {
+		if len(m[1]) > 0 {
+			meta[strings.ToLower(string(m[1]))] = string(m[2])
+		} else {
+			meta[strings.ToLower(string(m[4]))] = string(m[3])
+		}
+	}
+	return meta
+}
@@ -0,0 +1,109 @@
+package httpclient
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+// Validators are the cache validators remembered for a URL, with what its
+// response matched.
+type Validators struct {
+	ETag         string          `json:"etag,omitempty"`
+	LastModified string          `json:"last_modified,omitempty"`
+	Scope        string          `json:"scope,omitempty"`    // Keywords and rules Findings were matched with
+	Findings     *CachedFindings `json:"findings,omitempty"` // Reported again when the server answers 304
+}
+
+// CachedFindings are the matches of a cached response.
+type CachedFindings struct {
+	IsVulnerable    bool              `json:"is_vulnerable,omitempty"`
+	MatchedKeywords []string          `json:"matched_keywords,omitempty"`
+	MatchedRules    []types.RuleMatch `json:"matched_rules,omitempty"`
+	KeywordSeverity map[string]string `json:"keyword_severity,omitempty"`
+	Title           string            `json:"title,omitempty"`
+}
+
+// ResponseCache stores validators per URL across runs so repeated scans can
+// send conditional requests and skip unchanged responses (304 Not Modified).
+// Entries matched with other keywords or rules than Scope are not used.
+type ResponseCache struct {
+	Scope   string // Digest of this run's keywords and rules
+	path    string
+	mu      sync.Mutex
+	entries map[string]Validators
+}
+
+// LoadCache reads the cache file at path. A missing file yields an empty cache.
+func LoadCache(path string) (*ResponseCache, error) {
+	c := &ResponseCache{path: path, entries: make(map[string]Validators)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return c, err
+	}
+	if err := json.Unmarshal(data, &c.entries); err != nil {
+		return c, err
+	}
+	return c, nil
+}
+
+// Get returns the validators stored for url, if their findings were matched
+// with the cache's Scope.
+func (c *ResponseCache) Get(url string) (Validators, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, ok := c.entries[url]
+	if !ok || v.Scope != c.Scope || v.Findings == nil {
+		return Validators{}, false
+	}
+	return v, true
+}
+
+// Set stores validators for url under the cache's Scope, or forgets it if
+// they are empty.
+func (c *ResponseCache) Set(url string, v Validators) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if v.ETag == "" && v.LastModified == "" {
+		delete(c.entries, url)
+		return
+	}
+	v.Scope = c.Scope
+	c.entries[url] = v
+}
+
+// CacheFindings remembers the validators of a 200 response to a GET, read
+// without error, with what it matched, for conditional requests on later
+// runs.
+func (c *CustomClient) CacheFindings(urlStr string, resp *Response, findings CachedFindings) {
+	if c.Cache == nil || (c.Method != "" && c.Method != http.MethodGet) || resp.StatusCode != http.StatusOK {
+		return
+	}
+	c.Cache.Set(urlStr, Validators{ETag: resp.Header.Get("ETag"), LastModified: resp.Header.Get("Last-Modified"), Findings: &findings})
+}
+
+// Len returns the number of cached URLs.
+func (c *ResponseCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Save writes the cache back to its file.
+func (c *ResponseCache) Save() error {
+	c.mu.Lock()
+	data, err := json.MarshalIndent(c.entries, "", "  ")
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, append(data, '\n'), 0644)
+}
@@ -0,0 +1,37 @@
+package httpclient
+
+import (
+	"golang.org/x/net/html/charset"
+	"golang.org/x/text/transform"
+)
+
+// DecodeToUTF8 converts a body to UTF-8 using the charset from the BOM, the
+// Content-Type header or an HTML <meta> tag (falling back to the HTML5
+// default of windows-1252 for undeclared, non-UTF-8 content). It returns the
+// decoded body and the source charset name, or the original body and ""
+// if no conversion was needed or possible.
+func DecodeToUTF8(contentType string, body []byte) ([]byte, string) {
+	if len(body) == 0 || isASCII(body) {
+		return body, ""
+	}
+	enc, name, _ := charset.DetermineEncoding(body, contentType)
+	if enc == nil || name == "utf-8" {
+		return body, ""
+	}
+	decoded, _, err := transform.Bytes(enc.NewDecoder(), body)
+	if err != nil {
+		return body, ""
+	}
+	return decoded, name
+}
+
+// isASCII reports whether b contains only 7-bit bytes (identical in every
+// ASCII-compatible charset, so decoding can be skipped).
+func isASCII(b []byte) bool {
+	for _, c := range b {
+		if c >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
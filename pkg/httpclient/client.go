@@ -1,79 +1,425 @@
 package httpclient
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
+	"encoding/hex"
 	"io"
 	"log"
+	"net"
 	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"strings"
 	"time"
+
+	"github.com/nxneeraj/hx-hawks/pkg/config"
+	"github.com/nxneeraj/hx-hawks/pkg/evasion"
+	"github.com/nxneeraj/hx-hawks/pkg/types"
 )
 
 // CustomClient holds the configured HTTP client.
 type CustomClient struct {
-	Client *http.Client
+	Client     *http.Client
+	SkipBinary bool           // Stop reading bodies that are detected as non-text content
+	Cache      *ResponseCache // Optional validator cache for conditional requests
+	MaxBodySize int64         // Maximum bytes read per body (0 = unlimited)
+	MeasureSize bool          // Keep reading (and discarding) past MaxBodySize to learn a body's full size (--match-size/--filter-size)
+	Evasion    *evasion.Controller // Per-host block tracking and evasion profile (nil = disabled)
+	Method     string              // Request method ("" = GET)
+	Body       []byte              // Request body sent with every request (nil = none)
+	ContentType string             // Content-Type header for Body
+	Headers    http.Header         // Extra headers for every request (-H); "Host" sets the request host
+	Cookies    []*http.Cookie      // Cookies added to every request (--cookie)
+	AuthBasic  string              // "user:pass" for HTTP Basic auth
+	AuthBearer string              // Bearer token
+	ReadTimeout time.Duration      // Budget for reading a body once the headers arrived (0 = none besides Client.Timeout)
+	Retries    int                 // Retries of a request that got no response (--retries)
+	tls        *tlsVerifier        // Records certificate problems when verification is off
+}
+
+// Response holds the parts of an HTTP response the scanner works with.
+type Response struct {
+	FinalURL   string      // URL after any redirects
+	StatusCode int         // HTTP status code (0 if the request failed)
+	Header     http.Header // Response headers
+	Proto      string      // Response protocol, e.g. "HTTP/1.1"
+	Request    *http.Request // The request that got the final response (after redirects)
+	Body       []byte      // Response body (nil if skipped or failed)
+	Duration   float64     // Time taken for the request in seconds
+	Binary     bool        // Body was detected as binary and not read (SkipBinary)
+	NotModified bool       // Server answered 304 to a conditional request
+	Cached     *CachedFindings // With NotModified: what the cached response matched
+	Truncated  bool        // Body download stopped before the end
+	Size       int64       // Full body size in bytes, including what MaxBodySize cut off (Content-Length or counted with MeasureSize)
+	CertSHA256 string      // Hex SHA-256 of the leaf TLS certificate (HTTPS only)
+	TLSError   string      // Why the certificate would fail verification ("" if valid or not HTTPS)
+	Redirects  []types.RedirectHop // Every redirect followed, in order (empty if none)
+	BlockReason string     // Why the response looks like a WAF/rate-limit block ("" if not)
+	Evasion    []string    // Evasion applied to the request (see WithPlan)
+	RemoteIP   string      // Address of the socket that served the final response (the proxy's when proxied)
+	RemotePort int         // Port of that socket
+}
+
+// redirectChainKey is the context key under which Fetch collects redirect hops.
+type redirectChainKey struct{}
+
+// planKey is the context key carrying the evasion plan of a request.
+type planKey struct{}
+
+// WithPlan returns a context that makes Fetch apply an evasion plan
+// (User-Agent and proxy overrides). Waiting is left to the caller.
+func WithPlan(ctx context.Context, plan evasion.Plan) context.Context {
+	return context.WithValue(ctx, planKey{}, plan)
+}
+
+// hostKey is the context key carrying a Host header override.
+type hostKey struct{}
+
+// WithHost returns a context that makes Fetch send "Host: host" (virtual host
+// scanning) while connecting to the URL's own host.
+func WithHost(ctx context.Context, host string) context.Context {
+	return context.WithValue(ctx, hostKey{}, host)
+}
+
+// planFrom returns the evasion plan stored in ctx, if any.
+func planFrom(ctx context.Context) evasion.Plan {
+	plan, _ := ctx.Value(planKey{}).(evasion.Plan)
+	return plan
+}
+
+// retryWait is the wait before the first retry of a request that got no
+// response; it doubles with every further retry.
+const retryWait = 500 * time.Millisecond
+
+// proxyFor uses the evasion plan's proxy if set, else proxy (--proxy) if
+// set, else the environment settings.
+func proxyFor(proxy *url.URL) func(*http.Request) (*url.URL, error) {
+	return func(req *http.Request) (*url.URL, error) {
+		if plan := planFrom(req.Context()); plan.Proxy != nil {
+			return plan.Proxy, nil
+		}
+		if proxy != nil {
+			return proxy, nil
+		}
+		return http.ProxyFromEnvironment(req)
+	}
 }
 
-// NewClient creates a new HTTP client with custom settings.
-func NewClient(timeout time.Duration) *CustomClient {
-	// Allow insecure connections (often needed for pentesting)
+// NewClient creates a new HTTP client with custom settings taken from cfg.
+func NewClient(cfg *config.Config) *CustomClient {
+	timeout := cfg.Timeout
+	// Allow insecure connections (often needed for pentesting) unless
+	// --tls-verify; either way certificate problems are recorded per result
+	tlsConfig := &tls.Config{InsecureSkipVerify: !cfg.TLSVerify, RootCAs: cfg.RootCAs}
 	transport := &http.Transport{
-		TLSClientConfig:       &tls.Config{InsecureSkipVerify: true},
-		Proxy:                 http.ProxyFromEnvironment, // Respect environment proxy settings
+		TLSClientConfig:       tlsConfig,
+		Proxy:                 proxyFor(cfg.Proxy), // --proxy or the environment settings (or the evasion plan's proxy)
+		DialContext:           dialer(cfg.ConnectTimeout), // Honours --resolve and --resolvers
 		MaxIdleConns:          100,
 		IdleConnTimeout:       90 * time.Second,
-		TLSHandshakeTimeout:   10 * time.Second,
+		TLSHandshakeTimeout:   cfg.TLSTimeout,
+		ResponseHeaderTimeout: cfg.HeaderTimeout,
 		ExpectContinueTimeout: 1 * time.Second,
 	}
 
+	maxRedirects := cfg.MaxRedirects
+	if maxRedirects <= 0 {
+		maxRedirects = 10
+	}
 	client := &http.Client{
 		Timeout:   timeout,
 		Transport: transport,
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if cfg.NoRedirects {
+				return http.ErrUseLastResponse // Scan the redirect response itself
+			}
+			// Record the hop we're leaving (URL + redirect status) for the result
+			if chain, ok := req.Context().Value(redirectChainKey{}).(*[]types.RedirectHop); ok && req.Response != nil {
+				*chain = append(*chain, types.RedirectHop{URL: via[len(via)-1].URL.String(), StatusCode: req.Response.StatusCode})
+			}
 			// Follow redirects by default, but prevent infinite loops
-			if len(via) >= 10 {
+			if len(via) >= maxRedirects {
 				return http.ErrUseLastResponse // Or a custom error
 			}
 			return nil
 		},
 	}
 
-	return &CustomClient{Client: client}
+	c := &CustomClient{Client: client, SkipBinary: cfg.SkipBinary, MaxBodySize: cfg.MaxBodySize, MeasureSize: len(cfg.MatchSizes) > 0 || len(cfg.FilterSizes) > 0, ReadTimeout: cfg.ReadTimeout, Retries: cfg.Retries}
+	if !cfg.TLSVerify {
+		c.tls = &tlsVerifier{roots: cfg.RootCAs}
+	}
+	c.Method, c.Body, c.ContentType = cfg.Method, cfg.Data, cfg.ContentType
+	c.Headers, c.Cookies = cfg.Headers, cfg.Cookies
+	c.AuthBasic, c.AuthBearer = cfg.AuthBasic, cfg.AuthBearer
+	if len(cfg.FileCookies) > 0 || cfg.CookieJar {
+		jar, err := NewCookieJar(cfg.FileCookies, cfg.CookieJar)
+		if err != nil {
+			log.Printf("[!] Could not create cookie jar, file cookies disabled: %v", err)
+		} else {
+			client.Jar = jar
+		}
+	}
+	if cfg.Evasion {
+		c.Evasion = evasion.NewController(evasion.Profile{
+			Threshold: cfg.EvasionThreshold,
+			Delay:     cfg.EvasionDelay,
+			Jitter:    0.5,
+			Proxies:   cfg.Proxies,
+		})
+	}
+	return c
 }
 
-// Fetch performs a GET request to the specified URL.
-// It returns the final URL after redirects, the HTTP status code, the response body,
-// the duration of the request, and any error encountered.
-func (c *CustomClient) Fetch(ctx context.Context, urlStr string) (string, int, []byte, float64, error) {
+// BodySink receives the response body while it is downloaded. Once Done
+// reports true the rest of the body is not read.
+type BodySink interface {
+	io.Writer
+	Done() bool
+}
+
+// bodyChunkSize is the read size used while streaming bodies.
+const bodyChunkSize = 32 * 1024
+
+// Fetch performs a request (GET unless the client has another method or a
+// body configured) to the specified URL.
+// It always returns a non-nil Response carrying the final URL after redirects
+// and the request duration, plus the status code, headers and body on success.
+// If sink is non-nil the body is streamed through it and the download stops
+// early once the sink is done (e.g. every keyword has matched).
+func (c *CustomClient) Fetch(ctx context.Context, urlStr string, sink BodySink) (*Response, error) {
 	startTime := time.Now()
+	result := &Response{FinalURL: urlStr}
+	var chain []types.RedirectHop
+	ctx = context.WithValue(ctx, redirectChainKey{}, &chain)
+	defer func() { result.Redirects = chain }()
 
-	req, err := http.NewRequestWithContext(ctx, "GET", urlStr, nil)
+	// Record the peer of the connection actually used, rather than a separate
+	// DNS lookup that may answer differently (round-robin, changes mid-scan).
+	// The last connection obtained is the one that served the final response.
+	ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if addr, ok := info.Conn.RemoteAddr().(*net.TCPAddr); ok {
+				result.RemoteIP, result.RemotePort = addr.IP.String(), addr.Port
+			}
+		},
+	})
+
+	method := c.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	var reqBody io.Reader
+	if len(c.Body) > 0 {
+		reqBody = bytes.NewReader(c.Body) // Also lets 307/308 redirects resend it
+	}
+	req, err := http.NewRequestWithContext(ctx, method, urlStr, reqBody)
 	if err != nil {
-		duration := time.Since(startTime).Seconds()
-		return urlStr, 0, nil, duration, err
+		result.Duration = time.Since(startTime).Seconds()
+		return result, err
 	}
 
 	// Set a common user-agent
 	req.Header.Set("User-Agent", "Hx-H.A.W.K.S Scanner (github.com/nxneeraj/hx-hawks)") // Updated path
-	// Add other headers if needed
+	if c.ContentType != "" && len(c.Body) > 0 {
+		req.Header.Set("Content-Type", c.ContentType)
+	}
+	// Built-in auth (Go drops the header on redirects to other hosts)
+	if user, pass, ok := strings.Cut(c.AuthBasic, ":"); ok {
+		req.SetBasicAuth(user, pass)
+	} else if c.AuthBearer != "" {
+		req.Header.Set("Authorization", "Bearer "+c.AuthBearer)
+	}
+	// Custom headers (-H) override the defaults above
+	for name, values := range c.Headers {
+		if http.CanonicalHeaderKey(name) == "Host" {
+			req.Host = values[len(values)-1]
+			continue
+		}
+		req.Header[http.CanonicalHeaderKey(name)] = values
+	}
+	if host, _ := ctx.Value(hostKey{}).(string); host != "" {
+		req.Host = host // Per-target virtual host beats -H/--host-header
+	}
+	for _, cookie := range c.Cookies {
+		req.AddCookie(cookie) // The jar (if any) adds its own per-host cookies on send
+	}
+	plan := planFrom(ctx)
+	if plan.UserAgent != "" {
+		req.Header.Set("User-Agent", plan.UserAgent) // Rotated by the evasion profile
+	}
+	result.Evasion = plan.Applied
+
+	// Send conditional request headers if we've seen this URL before
+	// (only for GETs, other methods aren't cached)
+	cacheable := c.Cache != nil && method == http.MethodGet
+	var cached Validators
+	if cacheable {
+		cached, _ = c.Cache.Get(urlStr)
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
 
-	resp, err := c.Client.Do(req)
+	// The body read budget (--read-timeout) starts once the headers arrive
+	var readTimer *bodyTimer
+	if c.ReadTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		defer cancel()
+		req = req.WithContext(ctx)
+		readTimer = &bodyTimer{budget: c.ReadTimeout, cancel: cancel}
+	}
+
+	resp, err := c.do(req, &chain)
 	if err != nil {
-		duration := time.Since(startTime).Seconds()
-		return urlStr, 0, nil, duration, err
+		result.Duration = time.Since(startTime).Seconds()
+		return result, err
 	}
 	defer resp.Body.Close()
+	readTimer.Start()
+	defer readTimer.Stop()
+
+	result.Duration = time.Since(startTime).Seconds()
+	result.FinalURL = resp.Request.URL.String() // Get the URL after any redirects
+	result.StatusCode = resp.StatusCode
+	result.Header = resp.Header
+	result.Proto = resp.Proto
+	result.Request = resp.Request
+	if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
+		sum := sha256.Sum256(resp.TLS.PeerCertificates[0].Raw)
+		result.CertSHA256 = hex.EncodeToString(sum[:])
+		if c.tls != nil {
+			result.TLSError = c.tls.check(resp.TLS, resp.Request.URL.Hostname())
+		}
+	}
+
+	if cacheable {
+		if resp.StatusCode == http.StatusNotModified {
+			// Unchanged since the last run, no need to download the body
+			result.NotModified = true
+			result.Cached = cached.Findings
+			return result, nil
+		}
+	}
+
+	var body io.Reader = resp.Body
+	if c.SkipBinary {
+		// Sniff the first bytes before committing to reading the whole body
+		head := make([]byte, sniffLen)
+		n, err := io.ReadFull(resp.Body, head)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			err = readTimer.Err(err)
+			log.Printf("[!] Error reading response body for %s: %v", result.FinalURL, err)
+			return result, err
+		}
+		head = head[:n]
+		if IsBinary(resp.Header.Get("Content-Type"), head) {
+			result.Binary = true
+			return result, nil
+		}
+		body = io.MultiReader(bytes.NewReader(head), resp.Body)
+	}
 
-	duration := time.Since(startTime).Seconds()
-	finalURL := resp.Request.URL.String() // Get the URL after any redirects
+	// Cap the body so a single huge response can't exhaust memory. One extra
+	// byte is allowed through to detect that the cap was actually exceeded.
+	if c.MaxBodySize > 0 {
+		body = io.LimitReader(body, c.MaxBodySize+1)
+	}
 
-	bodyBytes, err := io.ReadAll(resp.Body)
+	bodyBytes, stopped, err := readBody(body, sink)
+	result.Truncated = stopped
+	result.Size = int64(len(bodyBytes))
+	if c.MaxBodySize > 0 && int64(len(bodyBytes)) > c.MaxBodySize {
+		// The size filters need the real size, not the cap: take it from
+		// Content-Length, or count the rest of the body without keeping it
+		if resp.ContentLength >= 0 {
+			result.Size = resp.ContentLength
+		} else if c.MeasureSize && err == nil {
+			var rest int64
+			rest, err = io.Copy(io.Discard, resp.Body)
+			result.Size += rest
+		}
+		bodyBytes = bodyBytes[:c.MaxBodySize]
+		result.Truncated = true
+	}
 	if err != nil {
+		err = readTimer.Err(err)
 		// Log error reading body, but might still return status code
-		log.Printf("[!] Error reading response body for %s: %v", finalURL, err)
+		log.Printf("[!] Error reading response body for %s: %v", result.FinalURL, err)
 		// Optionally return a partial result or just the error
-		return finalURL, resp.StatusCode, nil, duration, err
+		return result, err
+	}
+	result.Body = bodyBytes
+
+	// Spot WAF/CDN block pages and rate limits; hosts that keep blocking get
+	// switched to the evasion profile for their next requests
+	reason, blocked := evasion.DetectBlock(resp.StatusCode, resp.Header, bodyBytes)
+	result.BlockReason = reason
+	if c.Evasion.Observe(req.URL.Hostname(), blocked) {
+		log.Printf("[!] %s keeps blocking requests (%s), applying evasion profile", req.URL.Hostname(), reason)
 	}
 
-	return finalURL, resp.StatusCode, bodyBytes, duration, nil
+	return result, nil
+}
+
+// do sends req, retrying up to c.Retries times while no response arrives
+// (connection errors, timeouts). Each attempt has the whole Client.Timeout
+// (http.Client applies it per Do), so a timed-out attempt can be retried;
+// callers must not put one deadline over all attempts. Hops recorded in
+// chain by a failed attempt are dropped.
+func (c *CustomClient) do(req *http.Request, chain *[]types.RedirectHop) (*http.Response, error) {
+	wait := retryWait
+	for attempt := 0; ; attempt++ {
+		resp, err := c.Client.Do(req)
+		if err == nil || attempt >= c.Retries || req.Context().Err() != nil {
+			return resp, err
+		}
+		select {
+		case <-time.After(wait):
+		case <-req.Context().Done():
+			return nil, err
+		}
+		wait *= 2
+		*chain = (*chain)[:0]
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// readBody reads r to the end, feeding every chunk to sink (if any). It
+// reports stopped=true when the sink finished before the body did.
+func readBody(r io.Reader, sink BodySink) (body []byte, stopped bool, err error) {
+	if sink == nil {
+		body, err = io.ReadAll(r)
+		return body, false, err
+	}
+
+	var buf bytes.Buffer
+	chunk := make([]byte, bodyChunkSize)
+	for {
+		n, readErr := r.Read(chunk)
+		if n > 0 {
+			buf.Write(chunk[:n])
+			sink.Write(chunk[:n])
+			if sink.Done() {
+				return buf.Bytes(), true, nil
+			}
+		}
+		if readErr == io.EOF {
+			return buf.Bytes(), false, nil
+		}
+		if readErr != nil {
+			return buf.Bytes(), false, readErr
+		}
+	}
 }
@@ -0,0 +1,78 @@
+package httpclient
+
+import (
+	"bytes"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// sniffLen is the number of leading body bytes inspected by IsBinary.
+const sniffLen = 512
+
+// IsBinary reports whether a response looks like non-text content (images,
+// PDFs, archives, executables) based on its Content-Type header and the
+// first bytes of the body. Unknown or missing types fall back to sniffing.
+func IsBinary(contentType string, head []byte) bool {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		if textMediaType(mediaType) {
+			return false
+		}
+		if binaryMediaType(mediaType) {
+			return true
+		}
+	}
+
+	if len(head) == 0 {
+		return false
+	}
+	if len(head) > sniffLen {
+		head = head[:sniffLen]
+	}
+	sniffed, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	if textMediaType(sniffed) {
+		return false
+	}
+	// DetectContentType falls back to octet-stream for anything it can't
+	// classify; only treat that as binary if the bytes contain NULs.
+	if sniffed == "application/octet-stream" {
+		return bytes.IndexByte(head, 0) >= 0
+	}
+	return true
+}
+
+func textMediaType(mediaType string) bool {
+	switch {
+	case strings.HasPrefix(mediaType, "text/"),
+		strings.HasSuffix(mediaType, "+json"),
+		strings.HasSuffix(mediaType, "+xml"),
+		strings.Contains(mediaType, "javascript"),
+		strings.Contains(mediaType, "ecmascript"):
+		return true
+	}
+	switch mediaType {
+	case "application/json", "application/xml", "application/x-www-form-urlencoded",
+		"application/x-yaml", "application/yaml", "application/graphql", "application/x-sh",
+		"application/x-httpd-php", "application/xhtml+xml":
+		return true
+	}
+	return false
+}
+
+func binaryMediaType(mediaType string) bool {
+	for _, prefix := range []string{"image/", "audio/", "video/", "font/"} {
+		if strings.HasPrefix(mediaType, prefix) {
+			return true
+		}
+	}
+	switch mediaType {
+	case "application/pdf", "application/zip", "application/gzip", "application/x-gzip",
+		"application/x-tar", "application/x-7z-compressed", "application/x-rar-compressed",
+		"application/vnd.rar", "application/x-bzip2", "application/x-xz", "application/wasm",
+		"application/x-msdownload", "application/x-executable", "application/java-archive",
+		"application/vnd.ms-fontobject", "application/x-shockwave-flash", "application/msword":
+		return true
+	}
+	return strings.HasPrefix(mediaType, "application/vnd.openxmlformats") ||
+		strings.HasPrefix(mediaType, "application/vnd.ms-")
+}
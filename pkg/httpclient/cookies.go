@@ -0,0 +1,50 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+
+	"github.com/nxneeraj/hx-hawks/pkg/config"
+	"golang.org/x/net/publicsuffix"
+)
+
+// seededJar serves cookies loaded from a cookie file but, unless persist is
+// set, ignores cookies set by the scanned servers.
+type seededJar struct {
+	*cookiejar.Jar
+	persist bool
+}
+
+// SetCookies stores cookies from responses only when the jar persists them.
+func (j *seededJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	if j.persist {
+		j.Jar.SetCookies(u, cookies)
+	}
+}
+
+// NewCookieJar creates a per-host cookie jar seeded with file cookies. With
+// persist set, cookies set during the scan are kept and sent on later
+// requests to the same host.
+func NewCookieJar(seed []config.FileCookie, persist bool) (http.CookieJar, error) {
+	jar, err := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
+	if err != nil {
+		return nil, err
+	}
+	for _, fc := range seed {
+		cookie := *fc.Cookie
+		if !fc.HostOnly {
+			cookie.Domain = fc.Host // A Domain attribute makes the jar send it to subdomains too
+		}
+		scheme := "http"
+		if cookie.Secure {
+			scheme = "https"
+		}
+		path := cookie.Path
+		if path == "" {
+			path = "/"
+		}
+		jar.SetCookies(&url.URL{Scheme: scheme, Host: fc.Host, Path: path}, []*http.Cookie{&cookie})
+	}
+	return &seededJar{Jar: jar, persist: persist}, nil
+}
@@ -0,0 +1,60 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/nxneeraj/hx-hawks/pkg/utils"
+)
+
+// dialer returns the transport's dial function, with connection attempts
+// limited to timeout (--connect-timeout; 0 = only the request timeout).
+func dialer(timeout time.Duration) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	if timeout <= 0 {
+		return utils.DialContext
+	}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel() // Only bounds the dial; the connection outlives ctx
+		return utils.DialContext(ctx, network, addr)
+	}
+}
+
+// bodyTimer cancels a request whose body takes longer than budget to read
+// after the headers arrived (--read-timeout). A nil *bodyTimer does nothing.
+type bodyTimer struct {
+	budget  time.Duration
+	cancel  context.CancelFunc
+	timer   *time.Timer
+	expired atomic.Bool
+}
+
+// Start starts the budget; call it once the headers have arrived.
+func (t *bodyTimer) Start() {
+	if t == nil {
+		return
+	}
+	t.timer = time.AfterFunc(t.budget, func() {
+		t.expired.Store(true)
+		t.cancel()
+	})
+}
+
+// Stop releases the timer.
+func (t *bodyTimer) Stop() {
+	if t != nil && t.timer != nil {
+		t.timer.Stop()
+	}
+}
+
+// Err replaces a body read error caused by the expired budget with a timeout
+// error; other errors are returned unchanged.
+func (t *bodyTimer) Err(err error) error {
+	if t == nil || !t.expired.Load() {
+		return err
+	}
+	return fmt.Errorf("body not read within the %s read timeout: %w", t.budget, context.DeadlineExceeded)
+}
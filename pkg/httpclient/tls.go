@@ -0,0 +1,42 @@
+package httpclient
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"sync"
+)
+
+// tlsVerifier checks server certificates against a root pool without failing
+// the request, so results can record why a certificate would be rejected.
+// Outcomes are cached per certificate and host, as kept-alive connections
+// present the same chain again.
+type tlsVerifier struct {
+	roots   *x509.CertPool // nil = system roots
+	results sync.Map       // sha256(leaf) + host -> error string ("" = valid)
+}
+
+// check returns why the connection's certificate chain is not valid for
+// host, or "" if it is.
+func (v *tlsVerifier) check(state *tls.ConnectionState, host string) string {
+	if len(state.PeerCertificates) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256(state.PeerCertificates[0].Raw)
+	key := hex.EncodeToString(sum[:]) + "|" + host
+	if cached, ok := v.results.Load(key); ok {
+		return cached.(string)
+	}
+
+	opts := x509.VerifyOptions{DNSName: host, Roots: v.roots, Intermediates: x509.NewCertPool()}
+	for _, cert := range state.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	reason := ""
+	if _, err := state.PeerCertificates[0].Verify(opts); err != nil {
+		reason = err.Error()
+	}
+	v.results.Store(key, reason)
+	return reason
+}
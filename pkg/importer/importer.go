@@ -0,0 +1,263 @@
+// Package importer converts the JSON output of other scanners (httpx,
+// nuclei, ffuf) into hx-hawks results, so reporting and diffing can cover a
+// multi-tool pipeline.
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+// httpxRecord is one line of `httpx -json` output.
+type httpxRecord struct {
+	URL          string    `json:"url"`
+	Input        string    `json:"input"`
+	StatusCode   int       `json:"status_code"`
+	Title        string    `json:"title"`
+	ContentType  string    `json:"content_type"`
+	Tech         []string  `json:"tech"`
+	Host         string    `json:"host"`
+	A            []string  `json:"a"`
+	CNAME        []string  `json:"cname"`
+	Body         string    `json:"body"`
+	Failed       bool      `json:"failed"`
+	Error        string    `json:"error"`
+	FinalURL     string    `json:"final_url"`
+	Timestamp    time.Time `json:"timestamp"`
+	ResponseTime string    `json:"time"`
+}
+
+// nucleiRecord is one line of `nuclei -jsonl` output.
+type nucleiRecord struct {
+	TemplateID string `json:"template-id"`
+	Info       struct {
+		Name        string      `json:"name"`
+		Severity    string      `json:"severity"`
+		Remediation string      `json:"remediation"`
+		Reference   interface{} `json:"reference"` // String or list, depending on the template
+	} `json:"info"`
+	MatcherName      string    `json:"matcher-name"`
+	Host             string    `json:"host"`
+	MatchedAt        string    `json:"matched-at"`
+	IP               string    `json:"ip"`
+	ExtractedResults []string  `json:"extracted-results"`
+	Response         string    `json:"response"`
+	Timestamp        time.Time `json:"timestamp"`
+}
+
+// ffufFile is the layout of `ffuf -of json` output.
+type ffufFile struct {
+	Results []struct {
+		URL         string `json:"url"`
+		Status      int    `json:"status"`
+		ContentType string `json:"content-type"`
+		Host        string `json:"host"`
+		Duration    int64  `json:"duration"` // Nanoseconds
+	} `json:"results"`
+}
+
+// ImportFile reads a file in the given format ("auto" detects it) and
+// returns the converted results.
+func ImportFile(path, format string) ([]types.ScanResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if format == "auto" {
+		format = Detect(data)
+		if format == "" {
+			return nil, fmt.Errorf("%s: cannot detect format (use --format)", path)
+		}
+	}
+
+	var results []types.ScanResult
+	switch format {
+	case "httpx":
+		results, err = parseHTTPX(data)
+	case "nuclei":
+		results, err = parseNuclei(data)
+	case "ffuf":
+		results, err = parseFFUF(data)
+	default:
+		return nil, fmt.Errorf("unknown import format %q", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s (%s): %w", path, format, err)
+	}
+	return results, nil
+}
+
+// Detect guesses the tool that produced data from its first record, or
+// returns "" if it doesn't look like any supported format.
+func Detect(data []byte) string {
+	trimmed := bytes.TrimSpace(data)
+	var first map[string]json.RawMessage
+	if err := json.Unmarshal(trimmed, &first); err != nil {
+		line := trimmed
+		if i := bytes.IndexByte(trimmed, '\n'); i >= 0 {
+			line = trimmed[:i]
+		}
+		if err := json.Unmarshal(line, &first); err != nil {
+			return ""
+		}
+	}
+	switch {
+	case first["results"] != nil && first["commandline"] != nil:
+		return "ffuf"
+	case first["template-id"] != nil:
+		return "nuclei"
+	case first["status_code"] != nil || first["input"] != nil:
+		return "httpx"
+	}
+	return ""
+}
+
+// eachLine decodes every non-empty JSON line of data into a new T.
+func eachLine[T any](data []byte, fn func(T)) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024) // Records may embed whole responses
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var rec T
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		fn(rec)
+	}
+	return scanner.Err()
+}
+
+// parseHTTPX converts httpx probes into (non-vulnerable) results.
+func parseHTTPX(data []byte) ([]types.ScanResult, error) {
+	var results []types.ScanResult
+	err := eachLine(data, func(rec httpxRecord) {
+		r := types.ScanResult{
+			URL:          firstNonEmpty(rec.FinalURL, rec.URL, rec.Input),
+			Title:        rec.Title,
+			StatusCode:   rec.StatusCode,
+			ContentType:  rec.ContentType,
+			Technologies: rec.Tech,
+			IPs:          rec.A,
+			CNAMEs:       rec.CNAME,
+			ResponseBody: rec.Body,
+			Timestamp:    rec.Timestamp,
+		}
+		if len(rec.A) > 0 {
+			r.IP = rec.A[0]
+		}
+		if d, err := time.ParseDuration(rec.ResponseTime); err == nil {
+			r.RequestDuration = d.Seconds()
+		}
+		if rec.Failed {
+			r.Error = firstNonEmpty(rec.Error, "httpx: request failed")
+		}
+		results = append(results, r)
+	})
+	return results, err
+}
+
+// parseNuclei converts nuclei findings into vulnerable results, merging all
+// findings for the same URL into one result with a rule match per template.
+func parseNuclei(data []byte) ([]types.ScanResult, error) {
+	var results []types.ScanResult
+	byURL := make(map[string]int)
+	skipped := 0
+	err := eachLine(data, func(rec nucleiRecord) {
+		if rec.TemplateID == "" {
+			skipped++ // Not a nuclei finding
+			return
+		}
+		target := firstNonEmpty(rec.MatchedAt, rec.Host)
+		i, ok := byURL[target]
+		if !ok {
+			results = append(results, types.ScanResult{
+				URL:          target,
+				IP:           rec.IP,
+				Timestamp:    rec.Timestamp,
+				ResponseBody: rec.Response,
+				IsVulnerable: true,
+			})
+			i = len(results) - 1
+			byURL[target] = i
+		}
+		r := &results[i]
+
+		id := rec.TemplateID
+		if rec.MatcherName != "" {
+			id += ":" + rec.MatcherName
+		}
+		r.MatchedRules = append(r.MatchedRules, types.RuleMatch{
+			ID:          id,
+			Name:        rec.Info.Name,
+			Severity:    rec.Info.Severity,
+			Remediation: rec.Info.Remediation,
+			References:  references(rec.Info.Reference),
+		})
+		r.Severity = types.HighestSeverity(r.MatchedRules)
+		r.MatchedKeywords = append(r.MatchedKeywords, rec.ExtractedResults...)
+	})
+	if err == nil && len(results) == 0 && skipped > 0 {
+		err = fmt.Errorf("no records with a template-id")
+	}
+	return results, err
+}
+
+// parseFFUF converts ffuf hits into (non-vulnerable) results.
+func parseFFUF(data []byte) ([]types.ScanResult, error) {
+	var f ffufFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	results := make([]types.ScanResult, 0, len(f.Results))
+	for _, rec := range f.Results {
+		r := types.ScanResult{
+			URL:             rec.URL,
+			StatusCode:      rec.Status,
+			ContentType:     rec.ContentType,
+			RequestDuration: time.Duration(rec.Duration).Seconds(),
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// references normalizes nuclei's reference field (string or list).
+func references(v interface{}) []string {
+	switch ref := v.(type) {
+	case string:
+		if ref = strings.TrimSpace(ref); ref != "" {
+			return []string{ref}
+		}
+	case []interface{}:
+		var out []string
+		for _, item := range ref {
+			if s, ok := item.(string); ok && s != "" {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// firstNonEmpty returns the first non-empty string.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,313 @@
+// Package jsonpath evaluates a small JSONPath dialect against decoded JSON:
+// $.a.b, $['a'], $.list[0], $.list[*], $.*, $..key (recursive descent),
+// optionally followed by a comparison with a JSON literal
+// (==, !=, >, >=, <, <=) or a regex (=~ "pattern").
+package jsonpath
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// step is one segment of a path.
+type step struct {
+	key       string // Object key ("" with index/wildcard)
+	index     int    // Array index when isIndex
+	isIndex   bool
+	wildcard  bool // [*] or .*
+	recursive bool // ..key / ..*
+}
+
+// Expr is a compiled JSONPath expression.
+type Expr struct {
+	src   string
+	steps []step
+	op    string         // "" means "path selects at least one truthy value"
+	value interface{}    // Comparison literal (decoded JSON)
+	re    *regexp.Regexp // For =~
+}
+
+// String returns the expression source.
+func (e *Expr) String() string { return e.src }
+
+// comparison operators, longest first so ">=" wins over ">".
+var operators = []string{"==", "!=", ">=", "<=", "=~", ">", "<"}
+
+// Compile parses an expression such as `$.debug == true`.
+func Compile(src string) (*Expr, error) {
+	e := &Expr{src: src}
+	path := strings.TrimSpace(src)
+
+	if i, op := findOperator(path); i >= 0 {
+		literal := strings.TrimSpace(path[i+len(op):])
+		path = strings.TrimSpace(path[:i])
+		e.op = op
+		if literal == "" {
+			return nil, fmt.Errorf("jsonpath %q: missing value after %s", src, op)
+		}
+		if err := json.Unmarshal([]byte(literal), &e.value); err != nil {
+			return nil, fmt.Errorf("jsonpath %q: value %s is not a JSON literal", src, literal)
+		}
+		if op == "=~" {
+			pattern, ok := e.value.(string)
+			if !ok {
+				return nil, fmt.Errorf("jsonpath %q: =~ needs a quoted regex", src)
+			}
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("jsonpath %q: invalid regex: %w", src, err)
+			}
+			e.re = re
+		}
+	}
+
+	steps, err := parsePath(path)
+	if err != nil {
+		return nil, fmt.Errorf("jsonpath %q: %w", src, err)
+	}
+	e.steps = steps
+	return e, nil
+}
+
+// findOperator locates the first comparison operator outside brackets and quotes.
+func findOperator(s string) (int, string) {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		case depth == 0:
+			for _, op := range operators {
+				if strings.HasPrefix(s[i:], op) {
+					return i, op
+				}
+			}
+		}
+	}
+	return -1, ""
+}
+
+// parsePath parses the path part of an expression.
+func parsePath(p string) ([]step, error) {
+	if !strings.HasPrefix(p, "$") {
+		return nil, fmt.Errorf("path must start with $")
+	}
+	p = p[1:]
+	var steps []step
+	for len(p) > 0 {
+		recursive := false
+		switch {
+		case strings.HasPrefix(p, ".."):
+			recursive = true
+			p = p[2:]
+		case p[0] == '.':
+			p = p[1:]
+		case p[0] == '[':
+		default:
+			return nil, fmt.Errorf("unexpected %q", p)
+		}
+
+		if len(p) > 0 && p[0] == '[' {
+			end := strings.IndexByte(p, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated [")
+			}
+			inner := strings.TrimSpace(p[1:end])
+			p = p[end+1:]
+			st := step{recursive: recursive}
+			switch {
+			case inner == "*":
+				st.wildcard = true
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				st.key = inner[1 : len(inner)-1]
+			default:
+				n, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("invalid index [%s]", inner)
+				}
+				st.index, st.isIndex = n, true
+			}
+			steps = append(steps, st)
+			continue
+		}
+
+		end := strings.IndexAny(p, ".[")
+		if end < 0 {
+			end = len(p)
+		}
+		name := p[:end]
+		p = p[end:]
+		if name == "" {
+			return nil, fmt.Errorf("empty key")
+		}
+		if name == "*" {
+			steps = append(steps, step{wildcard: true, recursive: recursive})
+		} else {
+			steps = append(steps, step{key: name, recursive: recursive})
+		}
+	}
+	return steps, nil
+}
+
+// Select returns every value the path selects in doc.
+func (e *Expr) Select(doc interface{}) []interface{} {
+	nodes := []interface{}{doc}
+	for _, st := range e.steps {
+		var next []interface{}
+		for _, n := range nodes {
+			if st.recursive {
+				walk(n, func(v interface{}) { next = append(next, apply(st, v)...) })
+			} else {
+				next = append(next, apply(st, n)...)
+			}
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+// apply evaluates one (non-recursive) step on a node.
+func apply(st step, n interface{}) []interface{} {
+	switch v := n.(type) {
+	case map[string]interface{}:
+		if st.wildcard {
+			out := make([]interface{}, 0, len(v))
+			for _, child := range v {
+				out = append(out, child)
+			}
+			return out
+		}
+		if child, ok := v[st.key]; ok && !st.isIndex {
+			return []interface{}{child}
+		}
+	case []interface{}:
+		if st.wildcard {
+			return v
+		}
+		if st.isIndex {
+			i := st.index
+			if i < 0 {
+				i += len(v)
+			}
+			if i >= 0 && i < len(v) {
+				return []interface{}{v[i]}
+			}
+		}
+	}
+	return nil
+}
+
+// walk calls fn on n and every value nested below it.
+func walk(n interface{}, fn func(interface{})) {
+	fn(n)
+	switch v := n.(type) {
+	case map[string]interface{}:
+		for _, child := range v {
+			walk(child, fn)
+		}
+	case []interface{}:
+		for _, child := range v {
+			walk(child, fn)
+		}
+	}
+}
+
+// Match reports whether any selected value satisfies the expression.
+func (e *Expr) Match(doc interface{}) bool {
+	for _, v := range e.Select(doc) {
+		if e.test(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// test applies the comparison to a single value.
+func (e *Expr) test(v interface{}) bool {
+	switch e.op {
+	case "":
+		return truthy(v)
+	case "==":
+		return equal(v, e.value)
+	case "!=":
+		return !equal(v, e.value)
+	case "=~":
+		s, ok := v.(string)
+		if !ok {
+			s = fmt.Sprint(v)
+		}
+		return e.re.MatchString(s)
+	}
+	a, okA := v.(float64)
+	b, okB := e.value.(float64)
+	if !okA || !okB {
+		sa, okA := v.(string)
+		sb, okB := e.value.(string)
+		if !okA || !okB {
+			return false
+		}
+		return compare(strings.Compare(sa, sb), e.op)
+	}
+	switch {
+	case a < b:
+		return compare(-1, e.op)
+	case a > b:
+		return compare(1, e.op)
+	}
+	return compare(0, e.op)
+}
+
+// compare maps a three-way comparison result onto an ordering operator.
+func compare(c int, op string) bool {
+	switch op {
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	}
+	return false
+}
+
+// equal compares two decoded JSON values.
+func equal(a, b interface{}) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(ja) == string(jb)
+}
+
+// truthy reports whether a selected value counts as present: anything but
+// null, false, 0, "" and empty arrays/objects.
+func truthy(v interface{}) bool {
+	switch x := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return x
+	case float64:
+		return x != 0
+	case string:
+		return x != ""
+	case []interface{}:
+		return len(x) > 0
+	case map[string]interface{}:
+		return len(x) > 0
+	}
+	return true
+}
@@ -0,0 +1,141 @@
+package matcher
+
+// Automaton is an Aho-Corasick automaton that finds every keyword in a
+// text with a single pass over it, independent of the number of keywords.
+// It is safe for concurrent use once built.
+type Automaton struct {
+	patterns []string
+	delta    []int32 // Full transition table: delta[state*256+byte] = next state
+	out      [][]int // Pattern indices ending at each state (including suffix matches)
+}
+
+// New builds an automaton for the given keywords. Empty and duplicate
+// keywords are ignored.
+func New(keywords []string) *Automaton {
+	a := &Automaton{}
+	seen := make(map[string]bool, len(keywords))
+	for _, k := range keywords {
+		if k == "" || seen[k] {
+			continue
+		}
+		seen[k] = true
+		a.patterns = append(a.patterns, k)
+	}
+
+	// Build the trie; -1 marks a missing edge.
+	a.delta = make([]int32, 256)
+	for i := range a.delta {
+		a.delta[i] = -1
+	}
+	a.out = [][]int{nil}
+	for idx, p := range a.patterns {
+		state := int32(0)
+		for i := 0; i < len(p); i++ {
+			next := a.delta[int(state)*256+int(p[i])]
+			if next < 0 {
+				next = int32(len(a.out))
+				a.out = append(a.out, nil)
+				row := make([]int32, 256)
+				for j := range row {
+					row[j] = -1
+				}
+				a.delta = append(a.delta, row...)
+				a.delta[int(state)*256+int(p[i])] = next
+			}
+			state = next
+		}
+		a.out[state] = append(a.out[state], idx)
+	}
+
+	// Breadth-first pass computing failure links and completing the
+	// transition table so that matching never has to backtrack.
+	fail := make([]int32, len(a.out))
+	queue := make([]int32, 0, len(a.out))
+	for c := 0; c < 256; c++ {
+		next := a.delta[c]
+		if next < 0 {
+			a.delta[c] = 0
+			continue
+		}
+		fail[next] = 0
+		queue = append(queue, next)
+	}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		a.out[state] = append(a.out[state], a.out[fail[state]]...)
+		for c := 0; c < 256; c++ {
+			next := a.delta[int(state)*256+c]
+			if next < 0 {
+				a.delta[int(state)*256+c] = a.delta[int(fail[state])*256+c]
+				continue
+			}
+			fail[next] = a.delta[int(fail[state])*256+c]
+			queue = append(queue, next)
+		}
+	}
+
+	return a
+}
+
+// Patterns returns the deduplicated keywords the automaton was built from.
+func (a *Automaton) Patterns() []string {
+	return a.patterns
+}
+
+// Match returns the distinct keywords found in text, in keyword order.
+func (a *Automaton) Match(text []byte) []string {
+	s := a.NewStream()
+	s.Write(text)
+	return s.Matched()
+}
+
+// Stream matches keywords incrementally over a body delivered in chunks,
+// carrying automaton state across chunk boundaries. It is not safe for
+// concurrent use; create one per body.
+type Stream struct {
+	a         *Automaton
+	state     int32
+	found     []bool
+	remaining int
+}
+
+// NewStream starts an incremental match.
+func (a *Automaton) NewStream() *Stream {
+	return &Stream{a: a, found: make([]bool, len(a.patterns)), remaining: len(a.patterns)}
+}
+
+// Write feeds the next chunk of the body. It never fails.
+func (s *Stream) Write(p []byte) (int, error) {
+	a := s.a
+	for _, b := range p {
+		if s.remaining == 0 {
+			return len(p), nil // Every keyword already matched
+		}
+		s.state = a.delta[int(s.state)*256+int(b)]
+		for _, idx := range a.out[s.state] {
+			if !s.found[idx] {
+				s.found[idx] = true
+				s.remaining--
+			}
+		}
+	}
+	return len(p), nil
+}
+
+// Done reports whether every keyword has been matched, meaning the rest of
+// the body cannot change the outcome.
+func (s *Stream) Done() bool {
+	return len(s.found) > 0 && s.remaining == 0
+}
+
+// Matched returns the distinct keywords seen so far, in keyword order.
+func (s *Stream) Matched() []string {
+	var matched []string
+	for idx, ok := range s.found {
+		if ok {
+			matched = append(matched, s.a.patterns[idx])
+		}
+	}
+	return matched
+}
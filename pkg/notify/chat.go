@@ -0,0 +1,157 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+// Message length limits of the chat services (in characters).
+const (
+	slackMaxLen    = 3000 // Longer messages are collapsed behind "Show more"
+	discordMaxLen  = 2000
+	telegramMaxLen = 4096
+	maxLineLen     = 300 // Per finding, so one long URL can't crowd out the rest
+)
+
+// Slack posts messages to a Slack incoming webhook (--notify-slack).
+type Slack struct {
+	URL string
+}
+
+// Name implements Provider.
+func (s *Slack) Name() string { return "slack" }
+
+// Findings implements Provider.
+func (s *Slack) Findings(ctx context.Context, results []types.ScanResult) error {
+	return postJSON(ctx, s.URL, map[string]string{"text": findingsMessage(results, slackMaxLen)})
+}
+
+// Complete implements Provider.
+func (s *Slack) Complete(ctx context.Context, summary Summary) error {
+	return postJSON(ctx, s.URL, map[string]string{"text": completeMessage(summary)})
+}
+
+// Discord posts messages to a Discord channel webhook (--notify-discord).
+type Discord struct {
+	URL string
+}
+
+// Name implements Provider.
+func (d *Discord) Name() string { return "discord" }
+
+// Findings implements Provider.
+func (d *Discord) Findings(ctx context.Context, results []types.ScanResult) error {
+	return postJSON(ctx, d.URL, map[string]string{"content": findingsMessage(results, discordMaxLen)})
+}
+
+// Complete implements Provider.
+func (d *Discord) Complete(ctx context.Context, summary Summary) error {
+	return postJSON(ctx, d.URL, map[string]string{"content": completeMessage(summary)})
+}
+
+// Telegram sends messages through a Telegram bot (--notify-telegram-token,
+// --notify-telegram-chat).
+type Telegram struct {
+	Token  string
+	ChatID string
+	API    string // Bot API base URL; "" = https://api.telegram.org
+}
+
+// Name implements Provider.
+func (t *Telegram) Name() string { return "telegram" }
+
+// Findings implements Provider.
+func (t *Telegram) Findings(ctx context.Context, results []types.ScanResult) error {
+	return t.send(ctx, findingsMessage(results, telegramMaxLen))
+}
+
+// Complete implements Provider.
+func (t *Telegram) Complete(ctx context.Context, summary Summary) error {
+	return t.send(ctx, completeMessage(summary))
+}
+
+func (t *Telegram) send(ctx context.Context, text string) error {
+	api := t.API
+	if api == "" {
+		api = "https://api.telegram.org"
+	}
+	return postJSON(ctx, strings.TrimRight(api, "/")+"/bot"+t.Token+"/sendMessage", map[string]interface{}{
+		"chat_id":                  t.ChatID,
+		"text":                     text,
+		"disable_web_page_preview": true,
+	})
+}
+
+// findingsMessage formats a batch of findings as plain text, one line per
+// result, cut to maxLen characters:
+//
+//	🚨 hx-hawks: 2 findings
+//	• https://example.com/.env [high] DB_PASSWORD, env-file (200)
+//	• https://example.com/debug secret_key (500)
+func findingsMessage(results []types.ScanResult, maxLen int) string {
+	noun := "findings"
+	if len(results) == 1 {
+		noun = "finding"
+	}
+	header := fmt.Sprintf("🚨 hx-hawks: %d %s", len(results), noun)
+	lines := []string{header}
+	length := len([]rune(header))
+	for i, r := range results {
+		line := truncate("• "+findingLine(r), maxLineLen)
+		more := fmt.Sprintf("… and %d more", len(results)-i)
+		need := len([]rune(line)) + 1
+		if i < len(results)-1 {
+			need += len([]rune(more)) + 1 // Keep room to say what was left out
+		}
+		if length+need > maxLen {
+			lines = append(lines, more)
+			break
+		}
+		lines = append(lines, line)
+		length += len([]rune(line)) + 1
+	}
+	return strings.Join(lines, "\n")
+}
+
+// findingLine describes one finding: URL, severity, what matched, status.
+func findingLine(r types.ScanResult) string {
+	var b strings.Builder
+	b.WriteString(r.URL)
+	if r.Severity != "" {
+		fmt.Fprintf(&b, " [%s]", r.Severity)
+	}
+	matched := append([]string{}, r.MatchedKeywords...)
+	for _, m := range r.MatchedRules {
+		matched = append(matched, m.ID)
+	}
+	if len(matched) > 0 {
+		b.WriteString(" " + strings.Join(matched, ", "))
+	}
+	fmt.Fprintf(&b, " (%d)", r.StatusCode)
+	return b.String()
+}
+
+// completeMessage formats the scan summary:
+//
+//	✅ hx-hawks scan complete: 120 URLs, 2 vulnerable, 5 interesting, 3 failed in 1m4s
+func completeMessage(s Summary) string {
+	icon, verb := "✅", "complete"
+	if s.Interrupted {
+		icon, verb = "⚠️", "interrupted"
+	}
+	return fmt.Sprintf("%s hx-hawks scan %s: %d URLs, %d vulnerable, %d interesting, %d failed in %s",
+		icon, verb, s.Scanned, s.Vulnerable, s.Interesting, s.Errors, s.Finished.Sub(s.Started).Round(time.Second))
+}
+
+// truncate cuts s to at most n characters, marking the cut with "…".
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
@@ -0,0 +1,84 @@
+package notify
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProviderConfig configures one provider in a --notify-config file. Values
+// may reference environment variables ($VAR or ${VAR}), to keep tokens out
+// of the file.
+type ProviderConfig struct {
+	Type   string `yaml:"type"`              // slack, discord, telegram or webhook
+	URL    string `yaml:"url,omitempty"`     // Incoming webhook URL (slack, discord, webhook)
+	Token  string `yaml:"token,omitempty"`   // Bot token (telegram)
+	ChatID string `yaml:"chat_id,omitempty"` // Chat or channel ID (telegram)
+}
+
+// File is the top-level layout of a --notify-config file:
+//
+//	on: [findings, complete]
+//	providers:
+//	  - type: slack
+//	    url: https://hooks.slack.com/services/T000/B000/XXXX
+//	  - type: telegram
+//	    token: ${TELEGRAM_TOKEN}
+//	    chat_id: "-1001234567890"
+type File struct {
+	On        []string         `yaml:"on,omitempty"` // Events to notify; empty = all
+	Providers []ProviderConfig `yaml:"providers"`
+}
+
+// Load reads a --notify-config file and returns its providers and events.
+func Load(path string) ([]Provider, []string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	var f File
+	if err := yaml.Unmarshal([]byte(os.ExpandEnv(string(data))), &f); err != nil {
+		return nil, nil, fmt.Errorf("parsing notify config %s: %w", path, err)
+	}
+	if err := ValidateEvents(f.On); err != nil {
+		return nil, nil, fmt.Errorf("notify config %s: %w", path, err)
+	}
+	providers := make([]Provider, 0, len(f.Providers))
+	for i, pc := range f.Providers {
+		p, err := pc.Provider()
+		if err != nil {
+			return nil, nil, fmt.Errorf("notify config %s: provider #%d: %w", path, i+1, err)
+		}
+		providers = append(providers, p)
+	}
+	return providers, f.On, nil
+}
+
+// Provider validates the configuration and returns the provider it describes.
+func (pc ProviderConfig) Provider() (Provider, error) {
+	switch pc.Type {
+	case "slack", "discord", "webhook":
+		if u, err := url.Parse(pc.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("%s: url must be an http(s) URL, got %q", pc.Type, pc.URL)
+		}
+	case "telegram":
+		if pc.Token == "" || pc.ChatID == "" {
+			return nil, fmt.Errorf("telegram: token and chat_id are required")
+		}
+	default:
+		return nil, fmt.Errorf("unknown type %q (use slack, discord, telegram or webhook)", pc.Type)
+	}
+
+	switch pc.Type {
+	case "slack":
+		return &Slack{URL: pc.URL}, nil
+	case "discord":
+		return &Discord{URL: pc.URL}, nil
+	case "telegram":
+		return &Telegram{Token: pc.Token, ChatID: pc.ChatID}, nil
+	default:
+		return &Webhook{URL: pc.URL}, nil
+	}
+}
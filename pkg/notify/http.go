@@ -0,0 +1,70 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// httpClient sends every notification.
+var httpClient = &http.Client{Timeout: 15 * time.Second}
+
+// StatusError is a notification endpoint answering with an HTTP error.
+type StatusError struct {
+	Code       int
+	Status     string
+	RetryAfter time.Duration // From the Retry-After header (0 if absent), capped at a minute
+}
+
+func (e *StatusError) Error() string {
+	return "server answered " + e.Status
+}
+
+// Temporary reports whether retrying may help: 429 and 5xx answers. Other
+// 4xx answers (bad URL, revoked token) fail the same way every time.
+func (e *StatusError) Temporary() bool {
+	return e.Code == http.StatusTooManyRequests || e.Code >= 500
+}
+
+// postJSON POSTs payload as JSON to endpoint. Non-2xx answers return a *StatusError.
+func postJSON(ctx context.Context, endpoint string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encoding payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return &StatusError{Status: err.Error()} // Bad URL, retrying won't help
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Hx-H.A.W.K.S Scanner (github.com/nxneeraj/hx-hawks)")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err // The URL may carry a secret (Telegram bot token)
+		}
+		return err
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	resp.Body.Close()
+	if resp.StatusCode < 300 {
+		return nil
+	}
+
+	statusErr := &StatusError{Code: resp.StatusCode, Status: resp.Status}
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+		statusErr.RetryAfter = time.Duration(secs) * time.Second
+		if statusErr.RetryAfter > time.Minute {
+			statusErr.RetryAfter = time.Minute // Don't stall the scan's shutdown for long
+		}
+	}
+	return statusErr
+}
@@ -0,0 +1,275 @@
+// Package notify sends alerts about a scan while it runs: vulnerable results
+// as they are found and a summary when the scan completes, to a JSON
+// webhook or chat (Slack, Discord, Telegram).
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+// Delivery defaults.
+const (
+	DefaultBatchSize = 10              // Results per notification
+	DefaultInterval  = 5 * time.Second // Longest a result waits for its batch to fill
+	maxAttempts      = 4               // First try plus retries
+	retryBase        = time.Second     // Backoff before the first retry, doubled each time
+	queueSize        = 1024            // Results waiting while a batch is being delivered
+)
+
+// Events that can be notified (--notify-on).
+const (
+	EventFindings = "findings" // Vulnerable results, batched
+	EventComplete = "complete" // Scan summary when the scan ends
+)
+
+// Provider delivers notifications to one destination.
+type Provider interface {
+	Name() string
+	Findings(ctx context.Context, results []types.ScanResult) error
+	Complete(ctx context.Context, summary Summary) error
+}
+
+// Summary describes a finished (or interrupted) scan.
+type Summary struct {
+	Started     time.Time `json:"started"`
+	Finished    time.Time `json:"finished"`
+	Scanned     int       `json:"scanned"`
+	Vulnerable  int       `json:"vulnerable"`
+	Interesting int       `json:"interesting"`
+	Errors      int       `json:"errors"`
+	Interrupted bool      `json:"interrupted,omitempty"`
+}
+
+// Summarize counts results for a completion notification.
+func Summarize(results []types.ScanResult, started time.Time, interrupted bool) Summary {
+	s := Summary{Started: started.UTC(), Finished: time.Now().UTC(), Interrupted: interrupted}
+	for _, r := range results {
+		s.Add(r)
+	}
+	return s
+}
+
+// Add counts one more result, for scans that don't keep them all at hand.
+func (s *Summary) Add(r types.ScanResult) {
+	s.Scanned++
+	switch {
+	case r.Error != "":
+		s.Errors++
+	case r.IsVulnerable:
+		s.Vulnerable++
+	case len(r.Interesting) > 0:
+		s.Interesting++
+	}
+}
+
+// ValidateEvents checks a --notify-on list.
+func ValidateEvents(events []string) error {
+	for _, e := range events {
+		if e != EventFindings && e != EventComplete {
+			return fmt.Errorf("unknown event %q (use %s or %s)", e, EventFindings, EventComplete)
+		}
+	}
+	return nil
+}
+
+// Dispatcher batches vulnerable results and hands them to every provider,
+// retrying failed deliveries (network errors, 429 and 5xx answers) with
+// exponential backoff. A nil *Dispatcher ignores every call.
+type Dispatcher struct {
+	Providers []Provider
+	BatchSize int
+	Interval  time.Duration
+	Logger    *log.Logger
+	findings  bool // Notify EventFindings
+	complete  bool // Notify EventComplete
+
+	results chan types.ScanResult
+	done    chan struct{}
+	mu      sync.Mutex
+	sent    []int // Results delivered, per provider
+	failed  []int // Results given up on, per provider
+	dropped int   // Results never queued because the queue was full
+}
+
+// NewDispatcher starts delivering to providers the given events (nil = all).
+// It returns nil when there are no providers.
+func NewDispatcher(providers []Provider, events []string, batchSize int, interval time.Duration) *Dispatcher {
+	if len(providers) == 0 {
+		return nil
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	d := &Dispatcher{
+		Providers: providers,
+		BatchSize: batchSize,
+		Interval:  interval,
+		Logger:    log.Default(),
+		findings:  len(events) == 0,
+		complete:  len(events) == 0,
+		results:   make(chan types.ScanResult, queueSize),
+		done:      make(chan struct{}),
+		sent:      make([]int, len(providers)),
+		failed:    make([]int, len(providers)),
+	}
+	for _, e := range events {
+		d.findings = d.findings || e == EventFindings
+		d.complete = d.complete || e == EventComplete
+	}
+	go d.run()
+	return d
+}
+
+// Notify queues a result; results that aren't vulnerable are ignored. It
+// never blocks the scan: while a destination is down and the queue is full,
+// results are dropped (and counted).
+func (d *Dispatcher) Notify(result types.ScanResult) {
+	if d == nil || !d.findings || !result.IsVulnerable {
+		return
+	}
+	result.ResponseBody = "" // Keep payloads small; response_file still points at it
+	select {
+	case d.results <- result:
+	default:
+		d.mu.Lock()
+		d.dropped++
+		d.mu.Unlock()
+	}
+}
+
+// Close delivers the queued results, then the summary (when not nil and
+// EventComplete is on), and stops the dispatcher. Retries give up early
+// when ctx is done.
+func (d *Dispatcher) Close(ctx context.Context, summary *Summary) {
+	if d == nil {
+		return
+	}
+	close(d.results)
+	select {
+	case <-d.done:
+	case <-ctx.Done():
+		d.Logger.Printf("[!] Notifications: gave up delivering the last findings: %v", ctx.Err())
+		return
+	}
+	if summary == nil || !d.complete {
+		return
+	}
+	for i, p := range d.Providers {
+		if err := d.retry(ctx, p, func(ctx context.Context) error { return p.Complete(ctx, *summary) }); err != nil {
+			d.Logger.Printf("[!] Notifications: %s: scan summary not delivered: %v", d.name(i), err)
+		}
+	}
+}
+
+// Report returns one line of delivery counts per provider, for the log.
+func (d *Dispatcher) Report() string {
+	if d == nil {
+		return ""
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	parts := make([]string, 0, len(d.Providers)+1)
+	for i := range d.Providers {
+		parts = append(parts, fmt.Sprintf("%s %d sent, %d failed", d.name(i), d.sent[i], d.failed[i]))
+	}
+	if d.dropped > 0 {
+		parts = append(parts, fmt.Sprintf("%d dropped (queue full)", d.dropped))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// name labels provider i in logs.
+func (d *Dispatcher) name(i int) string {
+	return d.Providers[i].Name()
+}
+
+// run batches queued results and delivers each batch when it is full, when
+// Interval has passed since its first result, and on Close.
+func (d *Dispatcher) run() {
+	defer close(d.done)
+	var batch []types.ScanResult
+	timer := time.NewTimer(d.Interval)
+	timer.Stop()
+	flush := func() {
+		if len(batch) > 0 {
+			d.deliver(batch)
+			batch = nil
+		}
+	}
+	for {
+		select {
+		case result, ok := <-d.results:
+			if !ok {
+				flush()
+				return
+			}
+			if len(batch) == 0 {
+				timer.Reset(d.Interval)
+			}
+			batch = append(batch, result)
+			if len(batch) >= d.BatchSize {
+				timer.Stop()
+				flush()
+			}
+		case <-timer.C:
+			flush()
+		}
+	}
+}
+
+// deliver hands one batch to every provider.
+func (d *Dispatcher) deliver(batch []types.ScanResult) {
+	for i, p := range d.Providers {
+		err := d.retry(context.Background(), p, func(ctx context.Context) error { return p.Findings(ctx, batch) })
+		d.mu.Lock()
+		if err != nil {
+			d.failed[i] += len(batch)
+		} else {
+			d.sent[i] += len(batch)
+		}
+		d.mu.Unlock()
+		if err != nil {
+			d.Logger.Printf("[!] Notifications: %s: dropping %d findings: %v", d.name(i), len(batch), err)
+		}
+	}
+}
+
+// retry calls send until it succeeds, fails permanently (see StatusError)
+// or maxAttempts is reached.
+func (d *Dispatcher) retry(ctx context.Context, p Provider, send func(ctx context.Context) error) error {
+	delay := retryBase
+	for attempt := 1; ; attempt++ {
+		err := send(ctx)
+		if err == nil {
+			return nil
+		}
+		var status *StatusError
+		if errors.As(err, &status) && !status.Temporary() {
+			return err
+		}
+		if attempt == maxAttempts {
+			return fmt.Errorf("%w (after %d attempts)", err, attempt)
+		}
+		if status != nil && status.RetryAfter > delay {
+			delay = status.RetryAfter
+		}
+		d.Logger.Printf("[!] Notifications: %s: delivery failed (%v), retrying in %s", p.Name(), err, delay)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		delay *= 2
+	}
+}
@@ -0,0 +1,36 @@
+package notify
+
+import (
+	"context"
+	"time"
+
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+// Payload is the JSON body POSTed by a Webhook.
+type Payload struct {
+	Source  string             `json:"source"` // Always "hx-hawks"
+	Event   string             `json:"event"`  // EventFindings or EventComplete
+	SentAt  time.Time          `json:"sent_at"`
+	Count   int                `json:"count,omitempty"`
+	Results []types.ScanResult `json:"results,omitempty"` // Response bodies are left out
+	Summary *Summary           `json:"summary,omitempty"` // EventComplete only
+}
+
+// Webhook POSTs findings and the scan summary as JSON (--notify-webhook).
+type Webhook struct {
+	URL string
+}
+
+// Name implements Provider.
+func (w *Webhook) Name() string { return "webhook" }
+
+// Findings implements Provider.
+func (w *Webhook) Findings(ctx context.Context, results []types.ScanResult) error {
+	return postJSON(ctx, w.URL, Payload{Source: "hx-hawks", Event: EventFindings, SentAt: time.Now().UTC(), Count: len(results), Results: results})
+}
+
+// Complete implements Provider.
+func (w *Webhook) Complete(ctx context.Context, summary Summary) error {
+	return postJSON(ctx, w.URL, Payload{Source: "hx-hawks", Event: EventComplete, SentAt: time.Now().UTC(), Summary: &summary})
+}
@@ -0,0 +1,120 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+)
+
+// createOutput opens a text output file: truncated, or with appendMode
+// (--append) positioned at the end of what an earlier run wrote.
+func createOutput(filename string, appendMode bool) (*os.File, error) {
+	if appendMode {
+		return os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	}
+	return os.Create(filename)
+}
+
+// writeJSONArray writes data, an indented JSON array, to filename. With
+// appendMode the elements of the array already in the file (if any) come
+// first, so chunked scans build up one valid report.
+func writeJSONArray(filename string, data []byte, appendMode bool) error {
+	if appendMode {
+		existing, err := readExisting(filename)
+		if err != nil {
+			return err
+		}
+		if len(bytes.TrimSpace(existing)) > 0 {
+			var merged, added []json.RawMessage
+			if err := json.Unmarshal(existing, &merged); err != nil {
+				return fmt.Errorf("--append: %s doesn't hold a JSON array: %w", filename, err)
+			}
+			if err := json.Unmarshal(data, &added); err != nil {
+				return err
+			}
+			if data, err = json.MarshalIndent(append(merged, added...), "", "  "); err != nil {
+				return err
+			}
+			data = append(data, '\n')
+		}
+	}
+	return os.WriteFile(filename, data, 0644)
+}
+
+// writeReport writes a text report to filename. With appendMode it is added
+// after the reports already in the file, separated by a horizontal rule.
+func writeReport(filename, report string, appendMode bool) error {
+	file, err := createOutput(filename, appendMode)
+	if err != nil {
+		return err
+	}
+	if info, err := file.Stat(); err == nil && appendMode && info.Size() > 0 {
+		report = "\n---\n\n" + report
+	}
+	if _, err := file.WriteString(report); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// mergeJUnit adds the suites of an earlier report in filename (--append) to
+// report: suites for the same host are combined, and every count and time
+// is summed.
+func mergeJUnit(filename string, report *junitTestSuites) error {
+	existing, err := readExisting(filename)
+	if err != nil || len(bytes.TrimSpace(existing)) == 0 {
+		return err
+	}
+	var earlier junitTestSuites
+	if err := xml.Unmarshal(existing, &earlier); err != nil {
+		return fmt.Errorf("--append: %s isn't a JUnit report: %w", filename, err)
+	}
+	suites := earlier.Suites
+	index := make(map[string]int, len(suites))
+	for i, s := range suites {
+		index[s.Name] = i
+	}
+	for _, s := range report.Suites {
+		i, ok := index[s.Name]
+		if !ok {
+			index[s.Name] = len(suites)
+			suites = append(suites, s)
+			continue
+		}
+		merged := &suites[i]
+		merged.Tests += s.Tests
+		merged.Failures += s.Failures
+		merged.Errors += s.Errors
+		merged.Time = addSeconds(merged.Time, s.Time)
+		merged.Cases = append(merged.Cases, s.Cases...)
+	}
+	report.Suites = suites
+	report.Tests += earlier.Tests
+	report.Failures += earlier.Failures
+	report.Errors += earlier.Errors
+	report.Time = addSeconds(report.Time, earlier.Time)
+	return nil
+}
+
+// addSeconds adds two JUnit time attributes.
+func addSeconds(a, b string) string {
+	x, _ := strconv.ParseFloat(a, 64)
+	y, _ := strconv.ParseFloat(b, 64)
+	return junitSeconds(x + y)
+}
+
+// readExisting returns the contents of filename, or nothing when it doesn't
+// exist yet.
+func readExisting(filename string) ([]byte, error) {
+	data, err := os.ReadFile(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return data, err
+}
@@ -1,6 +1,10 @@
 package output
 
-import "github.com/fatih/color"
+import (
+	"strings"
+
+	"github.com/fatih/color"
+)
 
 // Define color functions for terminal output
 var (
@@ -12,3 +16,17 @@ var (
 	ColorYellow  = color.New(color.FgYellow).SprintFunc()  // For warnings or info
 	ColorCyan    = color.New(color.FgCyan).SprintFunc()    // For details like keywords
 )
+
+// ColorSeverity colors a severity label by how bad it is: critical and high
+// in red, medium in yellow, low in cyan, anything else in white.
+func ColorSeverity(severity string) string {
+	switch strings.ToLower(severity) {
+	case "critical", "high":
+		return ColorRed(severity)
+	case "medium":
+		return ColorYellow(severity)
+	case "low":
+		return ColorCyan(severity)
+	}
+	return ColorWhite(severity)
+}
@@ -0,0 +1,97 @@
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+// csvColumns are the -o-csv columns without --fields.
+var csvColumns = []string{"url", "status_code", "is_vulnerable", "severity", "matched_keywords", "matched_rules", "title", "content_type", "ip", "error", "request_duration_seconds", "timestamp"}
+
+// writeOutputCSV saves all results as CSV (-o-csv), one row per URL with the
+// --fields columns, or csvColumns. With appendMode (--append) rows are added
+// to the file without repeating the header.
+func writeOutputCSV(filename string, results []types.ScanResult, fields []string, appendMode bool) error {
+	file, err := createOutput(filename, appendMode)
+	if err != nil {
+		return err
+	}
+	header := true
+	if info, err := file.Stat(); err == nil && appendMode && info.Size() > 0 {
+		header = false
+	}
+	if err := writeCSV(file, results, fields, header); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// writeCSV writes results as CSV rows, after a header row if header is set.
+// List values are joined with "; ", and matched rules are given by ID.
+func writeCSV(w io.Writer, results []types.ScanResult, fields []string, header bool) error {
+	if len(fields) == 0 {
+		fields = csvColumns
+	}
+	records, err := selectFields(results, fields)
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	if header {
+		if err := cw.Write(fields); err != nil {
+			return err
+		}
+	}
+	row := make([]string, len(fields))
+	for _, record := range records {
+		for i, f := range fields {
+			row[i] = csvValue(record.values[f])
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvValue formats a JSON value of a result for a CSV cell.
+func csvValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return csvCell(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			if m, ok := item.(map[string]interface{}); ok && m["id"] != nil {
+				item = m["id"] // Rule matches
+			}
+			parts = append(parts, csvValue(item))
+		}
+		return strings.Join(parts, "; ")
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
+
+// csvCell defuses values a spreadsheet would run as a formula: titles and
+// URLs come from the scanned sites.
+func csvCell(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
@@ -0,0 +1,59 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+// fieldRecord is a result reduced to the --fields keys, marshalled in the
+// order they were requested.
+type fieldRecord struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+// MarshalJSON writes the keys in --fields order.
+func (r fieldRecord) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, k := range r.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, _ := json.Marshal(k)
+		value, err := json.Marshal(r.values[k])
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// selectFields reduces results to the requested JSON keys (--fields). Keys a
+// result has no value for are written as null so every record has the same
+// shape.
+func selectFields(results []types.ScanResult, fields []string) ([]fieldRecord, error) {
+	out := make([]fieldRecord, 0, len(results))
+	for _, r := range results {
+		data, err := json.Marshal(r)
+		if err != nil {
+			return nil, err
+		}
+		var all map[string]interface{}
+		if err := json.Unmarshal(data, &all); err != nil {
+			return nil, err
+		}
+		record := fieldRecord{keys: fields, values: make(map[string]interface{}, len(fields))}
+		for _, f := range fields {
+			record.values[f] = all[f] // nil when omitted
+		}
+		out = append(out, record)
+	}
+	return out, nil
+}
@@ -1,10 +1,13 @@
 package output
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
-	"os"
+	"net"
+	"strconv"
 	"strings"
 
 	
@@ -13,12 +16,19 @@ import (
 )
 
 // WriteResultsToFile handles writing scan results to various output files based on config.
-func WriteResultsToFile(cfg *config.Config, results []types.ScanResult) error {
+// Nothing is written once ctx is cancelled (e.g. the scan was interrupted).
+func WriteResultsToFile(ctx context.Context, cfg *config.Config, results []types.ScanResult) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	var writeErr error
+	// Findings reports list the most severe results first
+	bySeverity := append([]types.ScanResult(nil), results...)
+	types.SortBySeverity(bySeverity)
 
 	// -o: Plain text vulnerable URLs
 	if cfg.OutputFile != "" {
-		if err := writeOutputPlain(cfg.OutputFile, results); err != nil {
+		if err := writeOutputPlain(cfg.OutputFile, bySeverity, cfg.Append); err != nil {
 			log.Printf("[!] Failed to write plain output to %s: %v", cfg.OutputFile, err)
 			writeErr = err // Keep track of the first error
 		} else {
@@ -28,7 +38,7 @@ func WriteResultsToFile(cfg *config.Config, results []types.ScanResult) error {
 
 	// -o-json: JSON for vulnerable URLs (url, matched_keywords, response)
 	if cfg.OutputJSON != "" {
-		if err := writeOutputJSON(cfg.OutputJSON, results); err != nil {
+		if err := writeOutputJSON(cfg.OutputJSON, bySeverity, cfg.Fields, cfg.Append); err != nil {
 			log.Printf("[!] Failed to write JSON output to %s: %v", cfg.OutputJSON, err)
 			if writeErr == nil {
 				writeErr = err
@@ -40,7 +50,7 @@ func WriteResultsToFile(cfg *config.Config, results []types.ScanResult) error {
 
 	// -o-response: Plain text vulnerable URLs + response
 	if cfg.OutputResponse != "" {
-		if err := writeOutputResponse(cfg.OutputResponse, results); err != nil {
+		if err := writeOutputResponse(cfg.OutputResponse, bySeverity, cfg.Append); err != nil {
 			log.Printf("[!] Failed to write response output to %s: %v", cfg.OutputResponse, err)
 			if writeErr == nil {
 				writeErr = err
@@ -50,9 +60,21 @@ func WriteResultsToFile(cfg *config.Config, results []types.ScanResult) error {
 		}
 	}
 
+	// -o-interesting: JSON for leads that aren't findings (the "interesting" tier)
+	if cfg.OutputInteresting != "" {
+		if err := writeOutputInteresting(cfg.OutputInteresting, results, cfg.Fields, cfg.Append); err != nil {
+			log.Printf("[!] Failed to write interesting results to %s: %v", cfg.OutputInteresting, err)
+			if writeErr == nil {
+				writeErr = err
+			}
+		} else {
+			log.Printf("[+] Interesting results (JSON) saved to: %s", cfg.OutputInteresting)
+		}
+	}
+
 	// -o-all: Plain text all URLs (vulnerable + safe)
 	if cfg.OutputAll != "" {
-		if err := writeOutputAll(cfg.OutputAll, results); err != nil {
+		if err := writeOutputAll(cfg.OutputAll, results, cfg.Append); err != nil {
 			log.Printf("[!] Failed to write all output to %s: %v", cfg.OutputAll, err)
 			if writeErr == nil {
 				writeErr = err
@@ -64,7 +86,7 @@ func WriteResultsToFile(cfg *config.Config, results []types.ScanResult) error {
 
 	// -o-all-json: Full JSON report for all URLs
 	if cfg.OutputAllJSON != "" {
-		if err := writeOutputAllJSON(cfg.OutputAllJSON, results); err != nil {
+		if err := writeOutputAllJSON(cfg.OutputAllJSON, results, cfg.Fields, cfg.Append); err != nil {
 			log.Printf("[!] Failed to write full JSON output to %s: %v", cfg.OutputAllJSON, err)
 			if writeErr == nil {
 				writeErr = err
@@ -74,49 +96,123 @@ func WriteResultsToFile(cfg *config.Config, results []types.ScanResult) error {
 		}
 	}
 
+	// -o-junit: JUnit XML report for CI
+	if cfg.OutputJUnit != "" {
+		if err := writeOutputJUnit(cfg.OutputJUnit, results, cfg.Append); err != nil {
+			log.Printf("[!] Failed to write JUnit report to %s: %v", cfg.OutputJUnit, err)
+			if writeErr == nil {
+				writeErr = err
+			}
+		} else {
+			log.Printf("[+] JUnit report saved to: %s", cfg.OutputJUnit)
+		}
+	}
+
+	// -o-md: Markdown findings report
+	if cfg.OutputMarkdown != "" {
+		if err := writeOutputMarkdown(cfg.OutputMarkdown, results, cfg.Append); err != nil {
+			log.Printf("[!] Failed to write Markdown report to %s: %v", cfg.OutputMarkdown, err)
+			if writeErr == nil {
+				writeErr = err
+			}
+		} else {
+			log.Printf("[+] Markdown report saved to: %s", cfg.OutputMarkdown)
+		}
+	}
+
+	// -o-csv: CSV of all results
+	if cfg.OutputCSV != "" {
+		if err := writeOutputCSV(cfg.OutputCSV, results, cfg.Fields, cfg.Append); err != nil {
+			log.Printf("[!] Failed to write CSV output to %s: %v", cfg.OutputCSV, err)
+			if writeErr == nil {
+				writeErr = err
+			}
+		} else {
+			log.Printf("[+] CSV report saved to: %s", cfg.OutputCSV)
+		}
+	}
+
+	// -o-html: HTML findings report
+	if cfg.OutputHTML != "" {
+		if err := writeOutputHTML(cfg.OutputHTML, results); err != nil {
+			log.Printf("[!] Failed to write HTML report to %s: %v", cfg.OutputHTML, err)
+			if writeErr == nil {
+				writeErr = err
+			}
+		} else {
+			log.Printf("[+] HTML report saved to: %s", cfg.OutputHTML)
+		}
+	}
+
 	return writeErr
 }
 
 // writeOutputPlain saves only vulnerable URLs to a file.
-func writeOutputPlain(filename string, results []types.ScanResult) error {
-	file, err := os.Create(filename)
+func writeOutputPlain(filename string, results []types.ScanResult, appendMode bool) error {
+	file, err := createOutput(filename, appendMode)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
 
+	count, err := writePlain(file, results)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+        log.Printf("[i] No vulnerable results to write to %s", filename)
+    }
+	return nil
+}
+
+// writePlain writes the vulnerable URLs, one per line, and returns how many.
+func writePlain(w io.Writer, results []types.ScanResult) (int, error) {
 	count := 0
 	for _, r := range results {
 		if r.IsVulnerable && r.Error == "" {
-			if _, err := fmt.Fprintln(file, r.URL); err != nil {
-				return err // Return on first write error
+			if _, err := fmt.Fprintln(w, r.URL); err != nil {
+				return count, err // Return on first write error
 			}
 			count++
 		}
 	}
-	if count == 0 {
-        log.Printf("[i] No vulnerable results to write to %s", filename)
-    }
-	return nil
+	return count, nil
 }
 
-// writeOutputJSON saves vulnerable results in JSON format.
-func writeOutputJSON(filename string, results []types.ScanResult) error {
+// writeOutputJSON saves vulnerable results in JSON format. With fields
+// (--fields) only those keys are written instead of url/keywords/response.
+func writeOutputJSON(filename string, results []types.ScanResult, fields []string, appendMode bool) error {
+	if len(fields) > 0 {
+		var vulnerable []types.ScanResult
+		for _, r := range results {
+			if r.IsVulnerable && r.Error == "" {
+				vulnerable = append(vulnerable, r)
+			}
+		}
+		return writeFieldsJSON(filename, vulnerable, fields, appendMode)
+	}
 	vulnerableResults := make([]map[string]interface{}, 0)
 	for _, r := range results {
 		if r.IsVulnerable && r.Error == "" {
-			vulnerableResults = append(vulnerableResults, map[string]interface{}{
+			record := map[string]interface{}{
 				"url":              r.URL,
 				"matched_keywords": r.MatchedKeywords,
 				"response":         r.ResponseBody, // Includes full response here
-			})
+			}
+			if r.Severity != "" {
+				record["severity"] = r.Severity
+			}
+			if r.Inputs != nil {
+				record["inputs"] = r.Inputs // Lets `hx-hawks verify` check the report
+			}
+			vulnerableResults = append(vulnerableResults, record)
 		}
 	}
 
 	if len(vulnerableResults) == 0 {
 		log.Printf("[i] No vulnerable results to write to %s", filename)
 		// Create an empty JSON array file.
-		return os.WriteFile(filename, []byte("[]\n"), 0644)
+		return writeJSONArray(filename, []byte("[]\n"), appendMode)
 	}
 
 	jsonData, err := json.MarshalIndent(vulnerableResults, "", "  ")
@@ -125,12 +221,12 @@ func writeOutputJSON(filename string, results []types.ScanResult) error {
 	}
 	// Add trailing newline for POSIX compatibility
 	jsonData = append(jsonData, '\n')
-	return os.WriteFile(filename, jsonData, 0644)
+	return writeJSONArray(filename, jsonData, appendMode)
 }
 
 // writeOutputResponse saves vulnerable URLs and their full responses.
-func writeOutputResponse(filename string, results []types.ScanResult) error {
-	file, err := os.Create(filename)
+func writeOutputResponse(filename string, results []types.ScanResult, appendMode bool) error {
+	file, err := createOutput(filename, appendMode)
 	if err != nil {
 		return err
 	}
@@ -140,10 +236,13 @@ func writeOutputResponse(filename string, results []types.ScanResult) error {
 	for _, r := range results {
 		if r.IsVulnerable && r.Error == "" {
 			separator := strings.Repeat("=", 80)
-			output := fmt.Sprintf("URL: %s\nStatus Code: %d\nMatched Keywords: %s\nResponse:\n%s\n%s\n\n",
+			output := fmt.Sprintf("URL: %s\nTitle: %s\nStatus Code: %d\nSeverity: %s\nMatched Keywords: %s\n%sResponse:\n%s\n%s\n\n",
 				r.URL,
+				r.Title,
 				r.StatusCode,
-				strings.Join(r.MatchedKeywords, ", "),
+				orDash(r.Severity),
+				strings.Join(keywordLabels(r), ", "),
+				formatRuleMatches(r.MatchedRules),
 				r.ResponseBody,
 				separator,
 			)
@@ -159,9 +258,33 @@ func writeOutputResponse(filename string, results []types.ScanResult) error {
 	return nil
 }
 
+// writeOutputInteresting saves the results of the "interesting" tier (not
+// vulnerable, but worth a manual look) as a JSON array, or only their
+// --fields keys.
+func writeOutputInteresting(filename string, results []types.ScanResult, fields []string, appendMode bool) error {
+	interesting := make([]types.ScanResult, 0)
+	for _, r := range results {
+		if !r.IsVulnerable && len(r.Interesting) > 0 {
+			interesting = append(interesting, r)
+		}
+	}
+	if len(fields) > 0 {
+		return writeFieldsJSON(filename, interesting, fields, appendMode)
+	}
+	if len(interesting) == 0 {
+		log.Printf("[i] No interesting results to write to %s", filename)
+	}
+	jsonData, err := json.MarshalIndent(interesting, "", "  ")
+	if err != nil {
+		return err
+	}
+	jsonData = append(jsonData, '\n')
+	return writeJSONArray(filename, jsonData, appendMode)
+}
+
 // writeOutputAll saves basic info for all scanned URLs.
-func writeOutputAll(filename string, results []types.ScanResult) error {
-	file, err := os.Create(filename)
+func writeOutputAll(filename string, results []types.ScanResult, appendMode bool) error {
+	file, err := createOutput(filename, appendMode)
 	if err != nil {
 		return err
 	}
@@ -180,7 +303,23 @@ func writeOutputAll(filename string, results []types.ScanResult) error {
 			details = fmt.Sprintf("Error: %s", r.Error)
 		} else if r.IsVulnerable {
 			status = "VULNERABLE"
-			details = fmt.Sprintf("Matched: %s", strings.Join(r.MatchedKeywords, ", "))
+			details = fmt.Sprintf("Matched: %s", strings.Join(keywordLabels(r), ", "))
+		} else if len(r.Interesting) > 0 {
+			status = "INTERESTING"
+			details = fmt.Sprintf("Why: %s", strings.Join(r.Interesting, "; "))
+		}
+
+		if r.Title != "" {
+			details = strings.TrimSpace(fmt.Sprintf("[Title: %s] %s", r.Title, details))
+		}
+		if len(r.Technologies) > 0 {
+			details = strings.TrimSpace(fmt.Sprintf("%s [Tech: %s]", details, strings.Join(r.Technologies, ", ")))
+		}
+		if len(r.RedirectChain) > 0 {
+			details = strings.TrimSpace(fmt.Sprintf("%s [Redirects: %s]", details, FormatRedirectChain(r.RedirectChain, r.URL)))
+		}
+		if r.RemoteIP != "" {
+			details = strings.TrimSpace(fmt.Sprintf("%s [Remote: %s]", details, net.JoinHostPort(r.RemoteIP, strconv.Itoa(r.RemotePort))))
 		}
 
 		line := fmt.Sprintf("[%s] %s (Status: %d) %s\n", status, r.URL, r.StatusCode, details)
@@ -191,12 +330,16 @@ func writeOutputAll(filename string, results []types.ScanResult) error {
 	return nil
 }
 
-// writeOutputAllJSON saves a full JSON report of all results.
-func writeOutputAllJSON(filename string, results []types.ScanResult) error {
+// writeOutputAllJSON saves a full JSON report of all results, or only the
+// --fields keys of each.
+func writeOutputAllJSON(filename string, results []types.ScanResult, fields []string, appendMode bool) error {
+	if len(fields) > 0 {
+		return writeFieldsJSON(filename, results, fields, appendMode)
+	}
 	if len(results) == 0 {
 		log.Printf("[i] No results to write to %s", filename)
 		// Create an empty JSON array file.
-		return os.WriteFile(filename, []byte("[]\n"), 0644)
+		return writeJSONArray(filename, []byte("[]\n"), appendMode)
 	}
 	jsonData, err := json.MarshalIndent(results, "", "  ")
 	if err != nil {
@@ -204,5 +347,55 @@ func writeOutputAllJSON(filename string, results []types.ScanResult) error {
 	}
     // Add trailing newline
     jsonData = append(jsonData, '\n')
-	return os.WriteFile(filename, jsonData, 0644)
+	return writeJSONArray(filename, jsonData, appendMode)
+}
+
+// writeFieldsJSON saves results reduced to fields as a JSON array.
+func writeFieldsJSON(filename string, results []types.ScanResult, fields []string, appendMode bool) error {
+	records, err := selectFields(results, fields)
+	if err != nil {
+		return err
+	}
+	jsonData, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+	jsonData = append(jsonData, '\n')
+	return writeJSONArray(filename, jsonData, appendMode)
+}
+
+// keywordLabels returns the matched keywords of r, each followed by its
+// severity when it has one: "AWS_SECRET (critical)".
+func keywordLabels(r types.ScanResult) []string {
+	labels := make([]string, 0, len(r.MatchedKeywords))
+	for _, k := range r.MatchedKeywords {
+		if sev := r.KeywordSeverity[k]; sev != "" {
+			k += " (" + sev + ")"
+		}
+		labels = append(labels, k)
+	}
+	return labels
+}
+
+// formatRuleMatches renders matched rules (with remediation hints and
+// references) as text lines, or "" if no rules matched.
+func formatRuleMatches(matches []types.RuleMatch) string {
+	var b strings.Builder
+	for _, m := range matches {
+		fmt.Fprintf(&b, "Rule: %s", m.ID)
+		if m.Name != "" {
+			fmt.Fprintf(&b, " - %s", m.Name)
+		}
+		if m.Severity != "" {
+			fmt.Fprintf(&b, " (%s)", m.Severity)
+		}
+		b.WriteString("\n")
+		if m.Remediation != "" {
+			fmt.Fprintf(&b, "  Remediation: %s\n", m.Remediation)
+		}
+		for _, ref := range m.References {
+			fmt.Fprintf(&b, "  Reference: %s\n", ref)
+		}
+	}
+	return b.String()
 }
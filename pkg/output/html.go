@@ -0,0 +1,147 @@
+package output
+
+import (
+	"html/template"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+// htmlReportTemplate is the -o-html report: a self-contained page (no
+// external assets) with the findings, most severe first, and every scanned URL.
+var htmlReportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"join":    strings.Join,
+	"inc":     func(i int) int { return i + 1 },
+	"outcome": plainOutcome,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Hx-H.A.W.K.S Findings Report</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #f0f0f0; }
+td.url { word-break: break-all; }
+pre { background: #f6f6f6; padding: 8px; overflow-x: auto; }
+.critical, .high { color: #b00020; font-weight: bold; }
+.medium { color: #c77700; font-weight: bold; }
+.low, .info { color: #336; }
+</style>
+</head>
+<body>
+<h1>Hx-H.A.W.K.S Findings Report</h1>
+<p>Generated {{.Generated}}. Scanned {{len .Results}} URLs: <strong>{{len .Findings}} vulnerable</strong>, {{.Interesting}} interesting, {{.Errors}} failed.</p>
+{{- with .Inputs}}
+<p>Inputs: targets sha256:{{.TargetsSHA256}}, rules sha256:{{.RulesSHA256}}</p>
+{{- end}}
+{{if .Findings -}}
+<h2>Findings</h2>
+{{range $i, $f := .Findings -}}
+<h3>{{inc $i}}. <a href="{{$f.Result.URL}}">{{$f.Result.URL}}</a></h3>
+<ul>
+<li>Status: {{$f.Result.StatusCode}}</li>
+{{- with $f.Result.Title}}
+<li>Title: {{.}}</li>
+{{- end}}
+{{- with $f.Result.Severity}}
+<li>Severity: <span class="{{.}}">{{.}}</span></li>
+{{- end}}
+{{- with $f.Matched}}
+<li>Matched: {{join . ", "}}</li>
+{{- end}}
+{{- range $f.Result.MatchedRules}}
+<li>Rule: <code>{{.ID}}</code>{{with .Name}} - {{.}}{{end}}{{with .Severity}} ({{.}}){{end}}
+{{- if or .Remediation .References}}
+<ul>
+{{- with .Remediation}}
+<li>Remediation: {{.}}</li>
+{{- end}}
+{{- range .References}}
+<li>Reference: <a href="{{.}}">{{.}}</a></li>
+{{- end}}
+</ul>
+{{- end}}
+</li>
+{{- end}}
+{{- with $f.Result.Technologies}}
+<li>Technologies: {{join . ", "}}</li>
+{{- end}}
+</ul>
+{{- with $f.Evidence}}
+<pre>{{join . "\n"}}</pre>
+{{- end}}
+{{end -}}
+{{else -}}
+<p>No vulnerable URLs found.</p>
+{{end -}}
+<h2>All Results</h2>
+<table>
+<tr><th>URL</th><th>Status</th><th>Outcome</th><th>Title</th></tr>
+{{- range .Results}}
+<tr><td class="url">{{.URL}}</td><td>{{.StatusCode}}</td><td>{{outcome .}}</td><td>{{.Title}}</td></tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+// htmlFinding is a vulnerable result with what the report shows of it.
+type htmlFinding struct {
+	Result   types.ScanResult
+	Matched  []string
+	Evidence []string
+}
+
+// writeOutputHTML saves an HTML findings report (-o-html).
+func writeOutputHTML(filename string, results []types.ScanResult) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := writeHTML(file, results); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// writeHTML renders the -o-html report of results.
+func writeHTML(w io.Writer, results []types.ScanResult) error {
+	data := struct {
+		Generated   string
+		Results     []types.ScanResult
+		Findings    []htmlFinding
+		Interesting int
+		Errors      int
+		Inputs      *types.InputDigest
+	}{Generated: time.Now().UTC().Format(time.RFC3339), Results: results}
+
+	var vulnerable []types.ScanResult
+	for _, r := range results {
+		switch {
+		case r.Error != "":
+			data.Errors++
+		case r.IsVulnerable:
+			vulnerable = append(vulnerable, r)
+		case len(r.Interesting) > 0:
+			data.Interesting++
+		}
+	}
+	types.SortBySeverity(vulnerable)
+	for _, r := range vulnerable {
+		data.Findings = append(data.Findings, htmlFinding{
+			Result:   r,
+			Matched:  matchedNames(r),
+			Evidence: evidenceSnippets([]byte(r.ResponseBody), r.MatchedKeywords),
+		})
+	}
+	if len(vulnerable) > 0 {
+		data.Inputs = vulnerable[0].Inputs
+	}
+	return htmlReportTemplate.Execute(w, data)
+}
@@ -0,0 +1,82 @@
+package output
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+// JSONLWriter streams results to a JSON Lines file (-o-jsonl) as they are
+// collected, one object per line, so a crash mid-scan keeps everything
+// written so far. Lines are written unbuffered. A nil *JSONLWriter discards
+// results, so callers don't need to check whether the output is enabled.
+type JSONLWriter struct {
+	mu     sync.Mutex
+	file   *os.File
+	fields []string // --fields keys to keep (nil = whole result)
+	count  int
+}
+
+// CreateJSONL creates (or truncates) filename for streaming results reduced
+// to fields, or whole results when fields is empty. With appendMode
+// (--append) lines are added after the ones already in filename.
+func CreateJSONL(filename string, fields []string, appendMode bool) (*JSONLWriter, error) {
+	file, err := createOutput(filename, appendMode)
+	if err != nil {
+		return nil, err
+	}
+	return &JSONLWriter{file: file, fields: fields}, nil
+}
+
+// Write appends one result as a line.
+func (w *JSONLWriter) Write(result types.ScanResult) error {
+	if w == nil {
+		return nil
+	}
+	line, err := marshalRecord(result, w.fields)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, err := w.file.Write(line); err != nil {
+		return err
+	}
+	w.count++
+	return nil
+}
+
+// marshalRecord encodes a result reduced to fields, or the whole result when
+// fields is empty.
+func marshalRecord(result types.ScanResult, fields []string) ([]byte, error) {
+	if len(fields) == 0 {
+		return json.Marshal(result)
+	}
+	records, err := selectFields([]types.ScanResult{result}, fields)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(records[0])
+}
+
+// Count returns the number of lines written.
+func (w *JSONLWriter) Count() int {
+	if w == nil {
+		return 0
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.count
+}
+
+// Close closes the file.
+func (w *JSONLWriter) Close() error {
+	if w == nil {
+		return nil
+	}
+	return w.file.Close()
+}
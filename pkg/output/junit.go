@@ -0,0 +1,162 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+// JUnit XML report (-o-junit): one test suite per host and one test case per
+// scanned URL, failing when the URL is vulnerable and erroring when the
+// request failed, so CI servers (Jenkins, GitLab) can show and gate on it.
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",cdata"`
+}
+
+// writeOutputJUnit saves all results as a JUnit XML report. With appendMode
+// (--append) they are merged into the report already in filename.
+func writeOutputJUnit(filename string, results []types.ScanResult, appendMode bool) error {
+	report := junitReport(results)
+	if appendMode {
+		if err := mergeJUnit(filename, &report); err != nil {
+			return err
+		}
+	}
+	data, err := marshalJUnit(report)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, data, 0644)
+}
+
+// junitReport builds the -o-junit report of results.
+func junitReport(results []types.ScanResult) junitTestSuites {
+	report := junitTestSuites{Name: "hx-hawks"}
+	var total float64
+	var elapsed []float64 // Request time per suite
+	suiteIndex := make(map[string]int)
+	for _, r := range results {
+		host := junitHost(r.URL)
+		i, ok := suiteIndex[host]
+		if !ok {
+			i = len(report.Suites)
+			suiteIndex[host] = i
+			suite := junitTestSuite{Name: host}
+			if !r.Timestamp.IsZero() {
+				suite.Timestamp = r.Timestamp.UTC().Format(time.RFC3339)
+			}
+			report.Suites = append(report.Suites, suite)
+			elapsed = append(elapsed, 0)
+		}
+		suite := &report.Suites[i]
+
+		tc := junitTestCase{Name: r.URL, ClassName: host, Time: junitSeconds(r.RequestDuration)}
+		switch {
+		case r.Error != "":
+			tc.Error = &junitProblem{Message: r.Error, Type: r.ErrorClass}
+			suite.Errors++
+		case r.IsVulnerable:
+			tc.Failure = junitFailure(r)
+			suite.Failures++
+		}
+		if len(r.Interesting) > 0 {
+			tc.SystemOut = "Interesting: " + strings.Join(r.Interesting, "; ")
+		}
+		suite.Cases = append(suite.Cases, tc)
+		suite.Tests++
+		elapsed[i] += r.RequestDuration
+		total += r.RequestDuration
+	}
+
+	for i := range report.Suites {
+		suite := &report.Suites[i]
+		suite.Time = junitSeconds(elapsed[i])
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Errors += suite.Errors
+	}
+	report.Time = junitSeconds(total)
+	return report
+}
+
+// marshalJUnit encodes a report as an XML document.
+func marshalJUnit(report junitTestSuites) ([]byte, error) {
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	data = append([]byte(xml.Header), data...)
+	return append(data, '\n'), nil
+}
+
+// junitFailure describes why a vulnerable result fails its test case.
+func junitFailure(r types.ScanResult) *junitProblem {
+	var matched []string
+	matched = append(matched, r.MatchedKeywords...)
+	for _, rule := range r.MatchedRules {
+		matched = append(matched, rule.ID)
+	}
+	var text strings.Builder
+	fmt.Fprintf(&text, "Status Code: %d\n", r.StatusCode)
+	if r.Title != "" {
+		fmt.Fprintf(&text, "Title: %s\n", r.Title)
+	}
+	if len(r.MatchedKeywords) > 0 {
+		fmt.Fprintf(&text, "Matched Keywords: %s\n", strings.Join(keywordLabels(r), ", "))
+	}
+	text.WriteString(formatRuleMatches(r.MatchedRules))
+	return &junitProblem{
+		Message: "Matched: " + strings.Join(matched, ", "),
+		Type:    r.Severity,
+		Text:    text.String(),
+	}
+}
+
+// junitHost returns the host of a result URL, the test suite it belongs to.
+func junitHost(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return "unknown"
+}
+
+// junitSeconds formats a duration in seconds the way JUnit reports do.
+func junitSeconds(seconds float64) string {
+	return fmt.Sprintf("%.3f", seconds)
+}
@@ -0,0 +1,207 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+// evidenceContext is how many bytes around a keyword an evidence snippet shows.
+const evidenceContext = 60
+
+// writeOutputMarkdown saves a Markdown findings report (-o-md): a summary
+// table of the vulnerable URLs, most severe first, then one section per
+// finding with its matches, rule details and evidence snippets, ready to
+// paste into a bug-bounty submission or GitHub issue. With appendMode
+// (--append) the report is added below the ones already in filename.
+func writeOutputMarkdown(filename string, results []types.ScanResult, appendMode bool) error {
+	return writeReport(filename, markdownReport(results), appendMode)
+}
+
+// markdownReport renders the -o-md report of results.
+func markdownReport(results []types.ScanResult) string {
+	var findings []types.ScanResult
+	interesting, errors := 0, 0
+	for _, r := range results {
+		switch {
+		case r.Error != "":
+			errors++
+		case r.IsVulnerable:
+			findings = append(findings, r)
+		case len(r.Interesting) > 0:
+			interesting++
+		}
+	}
+	types.SortBySeverity(findings)
+
+	var b strings.Builder
+	b.WriteString("# Hx-H.A.W.K.S Findings Report\n\n")
+	fmt.Fprintf(&b, "Generated %s. Scanned %d URLs: **%d vulnerable**, %d interesting, %d failed.\n",
+		time.Now().UTC().Format(time.RFC3339), len(results), len(findings), interesting, errors)
+	if len(findings) > 0 && findings[0].Inputs != nil {
+		fmt.Fprintf(&b, "\nInputs: targets sha256:%s, rules sha256:%s\n", findings[0].Inputs.TargetsSHA256, findings[0].Inputs.RulesSHA256)
+	}
+	if len(findings) == 0 {
+		b.WriteString("\nNo vulnerable URLs found.\n")
+		return b.String()
+	}
+
+	b.WriteString("\n## Summary\n\n")
+	b.WriteString("| # | URL | Status | Severity | Matched |\n")
+	b.WriteString("|---|-----|--------|----------|---------|\n")
+	for i, r := range findings {
+		fmt.Fprintf(&b, "| %d | %s | %d | %s | %s |\n", i+1, markdownCell(r.URL), r.StatusCode,
+			markdownCell(orDash(r.Severity)), markdownCell(strings.Join(matchedNames(r), ", ")))
+	}
+
+	b.WriteString("\n## Findings\n")
+	for i, r := range findings {
+		fmt.Fprintf(&b, "\n### %d. %s\n\n", i+1, markdownInline(r.URL))
+		fmt.Fprintf(&b, "- **Status:** %d\n", r.StatusCode)
+		if r.Title != "" {
+			fmt.Fprintf(&b, "- **Title:** %s\n", markdownInline(r.Title))
+		}
+		if r.Severity != "" {
+			fmt.Fprintf(&b, "- **Severity:** %s\n", r.Severity)
+		}
+		if len(r.MatchedKeywords) > 0 {
+			fmt.Fprintf(&b, "- **Matched keywords:** %s\n", markdownKeywords(r))
+		}
+		for _, m := range r.MatchedRules {
+			fmt.Fprintf(&b, "- **Rule:** %s", markdownCode(m.ID))
+			if m.Name != "" {
+				fmt.Fprintf(&b, " - %s", markdownInline(m.Name))
+			}
+			if m.Severity != "" {
+				fmt.Fprintf(&b, " (%s)", m.Severity)
+			}
+			b.WriteString("\n")
+			if m.Remediation != "" {
+				fmt.Fprintf(&b, "  - Remediation: %s\n", markdownInline(m.Remediation))
+			}
+			for _, ref := range m.References {
+				fmt.Fprintf(&b, "  - Reference: %s\n", ref)
+			}
+		}
+		if len(r.Technologies) > 0 {
+			fmt.Fprintf(&b, "- **Technologies:** %s\n", markdownInline(strings.Join(r.Technologies, ", ")))
+		}
+		if len(r.RedirectChain) > 0 {
+			fmt.Fprintf(&b, "- **Redirects:** %s\n", markdownInline(FormatRedirectChain(r.RedirectChain, r.URL)))
+		}
+		if !r.Timestamp.IsZero() {
+			fmt.Fprintf(&b, "- **Scanned:** %s\n", r.Timestamp.UTC().Format(time.RFC3339))
+		}
+		if snippets := evidenceSnippets([]byte(r.ResponseBody), r.MatchedKeywords); len(snippets) > 0 {
+			b.WriteString("\n**Evidence**\n\n")
+			fence := codeFence(snippets)
+			b.WriteString(fence + "\n")
+			for _, s := range snippets {
+				b.WriteString(s + "\n")
+			}
+			b.WriteString(fence + "\n")
+		}
+	}
+	return b.String()
+}
+
+// evidenceSnippets returns one line of context around the first occurrence
+// of each keyword in body, whitespace collapsed, with "..." where it was cut.
+func evidenceSnippets(body []byte, keywords []string) []string {
+	var snippets []string
+	seen := make(map[string]bool)
+	for _, kw := range keywords {
+		i := bytes.Index(body, []byte(kw))
+		if kw == "" || i < 0 {
+			continue
+		}
+		start, end := i-evidenceContext, i+len(kw)+evidenceContext
+		prefix, suffix := "...", "..."
+		if start <= 0 {
+			start, prefix = 0, ""
+		}
+		if end >= len(body) {
+			end, suffix = len(body), ""
+		}
+		snippet := strings.Join(strings.Fields(strings.ToValidUTF8(string(body[start:end]), "")), " ")
+		if snippet = prefix + snippet + suffix; !seen[snippet] {
+			seen[snippet] = true // Keywords close together share a snippet
+			snippets = append(snippets, snippet)
+		}
+	}
+	return snippets
+}
+
+// codeFence returns a backtick fence longer than any backtick run in lines.
+func codeFence(lines []string) string {
+	longest := 0
+	for _, line := range lines {
+		run := 0
+		for _, c := range line {
+			if c == '`' {
+				run++
+				if run > longest {
+					longest = run
+				}
+			} else {
+				run = 0
+			}
+		}
+	}
+	if longest < 3 {
+		return "```"
+	}
+	return strings.Repeat("`", longest+1)
+}
+
+// matchedNames lists the keywords and rule IDs a result matched.
+func matchedNames(r types.ScanResult) []string {
+	names := append([]string(nil), r.MatchedKeywords...)
+	for _, m := range r.MatchedRules {
+		names = append(names, m.ID)
+	}
+	return names
+}
+
+// markdownCell escapes text for a table cell, where a pipe would end the cell.
+func markdownCell(s string) string {
+	return strings.ReplaceAll(markdownInline(s), "|", "\\|")
+}
+
+// markdownInline escapes the characters that would turn text from a scanned
+// page into Markdown or HTML markup.
+func markdownInline(s string) string {
+	return strings.NewReplacer("\\", "\\\\", "`", "\\`", "*", "\\*", "_", "\\_", "[", "\\[", "]", "\\]", "<", "&lt;", ">", "&gt;", "\r", " ", "\n", " ").Replace(s)
+}
+
+// markdownCode renders s as inline code.
+func markdownCode(s string) string {
+	if strings.Contains(s, "`") {
+		return "`` " + s + " ``"
+	}
+	return "`" + s + "`"
+}
+
+// markdownKeywords renders the matched keywords of r as code spans, each
+// followed by its severity when it has one.
+func markdownKeywords(r types.ScanResult) string {
+	codes := make([]string, len(r.MatchedKeywords))
+	for i, k := range r.MatchedKeywords {
+		codes[i] = markdownCode(k)
+		if sev := r.KeywordSeverity[k]; sev != "" {
+			codes[i] += " (" + sev + ")"
+		}
+	}
+	return strings.Join(codes, ", ")
+}
+
+// orDash returns s, or "-" when it is empty.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
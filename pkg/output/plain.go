@@ -0,0 +1,102 @@
+package output
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+// plainLog switches PrintResultTerminal to one structured line per result (--plain-log).
+var plainLog bool
+
+// SetPlainLog enables or disables plain log mode. Plain mode also turns off
+// colors, so log collectors (journald, CloudWatch) receive no ANSI codes.
+func SetPlainLog(on bool) {
+	plainLog = on
+	if on {
+		color.NoColor = true
+	}
+}
+
+// FormatPlain renders a result as a single logfmt line, e.g.
+//
+//	result=vulnerable url=https://a.example/ status=200 title="Admin" keywords=admin severity=high rules=exposed-admin duration=0.120
+//
+// Values with spaces, quotes or control characters are quoted, so a line never
+// spans more than one log record. Response bodies are never included.
+func FormatPlain(result types.ScanResult) string {
+	var b strings.Builder
+	field := func(key, value string) {
+		if value == "" {
+			return
+		}
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(key)
+		b.WriteByte('=')
+		b.WriteString(plainValue(value))
+	}
+
+	field("result", plainOutcome(result))
+	field("url", result.URL)
+	if result.StatusCode != 0 {
+		field("status", strconv.Itoa(result.StatusCode))
+	}
+	field("title", result.Title)
+	field("keywords", strings.Join(result.MatchedKeywords, ","))
+	field("severity", result.Severity)
+	ruleIDs := make([]string, 0, len(result.MatchedRules))
+	for _, rule := range result.MatchedRules {
+		ruleIDs = append(ruleIDs, rule.ID)
+	}
+	field("rules", strings.Join(ruleIDs, ","))
+	field("tech", strings.Join(result.Technologies, ","))
+	field("interesting", strings.Join(result.Interesting, "; "))
+	if len(result.RedirectChain) > 0 {
+		field("redirected_from", result.RedirectChain[0].URL)
+	}
+	if result.LoginRedirect {
+		field("login_redirect", "true")
+	}
+	field("duplicate_of", result.DuplicateOf)
+	field("blocked", result.Blocked)
+	field("tls_error", result.TLSError)
+	field("high_entropy", strings.Join(result.HighEntropy, ","))
+	field("evasion", strings.Join(result.Evasion, ","))
+	field("error_class", result.ErrorClass)
+	field("error", result.Error)
+	field("duration", fmt.Sprintf("%.3f", result.RequestDuration))
+	return b.String()
+}
+
+// plainOutcome names the outcome of a result for the "result" field.
+func plainOutcome(result types.ScanResult) string {
+	switch {
+	case result.Error != "":
+		return "error"
+	case result.Unchanged:
+		return "unchanged"
+	case result.IsVulnerable:
+		return "vulnerable"
+	case len(result.Interesting) > 0:
+		return "interesting"
+	case result.Duplicate:
+		return "duplicate"
+	default:
+		return "safe"
+	}
+}
+
+// plainValue quotes a logfmt value when it would otherwise be ambiguous.
+func plainValue(s string) string {
+	for _, r := range s {
+		if r <= ' ' || r == '"' || r == '=' || r == '\\' || r == 0x7f || !strconv.IsPrint(r) {
+			return strconv.Quote(s)
+		}
+	}
+	return s
+}
@@ -0,0 +1,60 @@
+package output
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+// RenderFormats are the formats Render accepts, with their MIME types and
+// file extensions.
+var RenderFormats = map[string]struct{ ContentType, Ext string }{
+	"txt":   {"text/plain; charset=utf-8", "txt"},
+	"jsonl": {"application/x-ndjson", "jsonl"},
+	"csv":   {"text/csv; charset=utf-8", "csv"},
+	"html":  {"text/html; charset=utf-8", "html"},
+	"md":    {"text/markdown; charset=utf-8", "md"},
+	"junit": {"application/xml", "xml"},
+}
+
+// Render writes results to w as the CLI output of format would hold them:
+// txt (-o: vulnerable URLs), jsonl (-o-jsonl), csv (-o-csv), html
+// (-o-html), md (-o-md) or junit (-o-junit). fields (--fields) picks the
+// keys of jsonl records and csv columns.
+func Render(w io.Writer, format string, results []types.ScanResult, fields []string) error {
+	switch format {
+	case "txt":
+		bySeverity := append([]types.ScanResult(nil), results...)
+		types.SortBySeverity(bySeverity)
+		_, err := writePlain(w, bySeverity)
+		return err
+	case "jsonl":
+		for _, r := range results {
+			line, err := marshalRecord(r, fields)
+			if err != nil {
+				return err
+			}
+			if _, err := w.Write(append(line, '\n')); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "csv":
+		return writeCSV(w, results, fields, true)
+	case "html":
+		return writeHTML(w, results)
+	case "md":
+		_, err := io.WriteString(w, markdownReport(results))
+		return err
+	case "junit":
+		data, err := marshalJUnit(junitReport(results))
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
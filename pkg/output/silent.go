@@ -0,0 +1,47 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/fatih/color"
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+// silent switches PrintResultTerminal to vulnerable URLs only (--silent).
+var silent bool
+
+// SetSilent enables or disables silent mode: PrintResultTerminal prints the
+// URL of each vulnerable result, one per line and without colors, and
+// nothing else, so stdout can be piped into other tools.
+func SetSilent(on bool) {
+	silent = on
+	if on {
+		color.NoColor = true
+	}
+}
+
+// printSilent prints a result in silent mode.
+func printSilent(result types.ScanResult) {
+	if result.IsVulnerable && result.Error == "" {
+		fmt.Println(result.URL)
+	}
+}
+
+// ErrorsOnly wraps w for the standard logger in silent mode: only fatal
+// errors ("[-]" lines) get through, every other log line is dropped.
+func ErrorsOnly(w io.Writer) io.Writer {
+	return errorsOnly{w}
+}
+
+type errorsOnly struct {
+	w io.Writer
+}
+
+func (e errorsOnly) Write(p []byte) (int, error) {
+	if !bytes.Contains(p, []byte("[-]")) {
+		return len(p), nil
+	}
+	return e.w.Write(p)
+}
@@ -0,0 +1,66 @@
+package output
+
+import (
+	"encoding/json"
+	"strings"
+	"text/template"
+
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+// lineTemplate switches PrintResultTerminal to one user-formatted line per
+// result (-o-template); nil when unset.
+var lineTemplate *template.Template
+
+// templateFuncs are available in -o-template templates besides the
+// text/template builtins.
+var templateFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	// severity returns the highest severity among matched rules, or ""
+	"severity": types.HighestSeverity,
+	// rules returns the IDs of matched rules
+	"rules": func(matches []types.RuleMatch) []string {
+		ids := make([]string, 0, len(matches))
+		for _, m := range matches {
+			ids = append(ids, m.ID)
+		}
+		return ids
+	},
+	// json renders any value as compact JSON, e.g. {{json .MatchedKeywords}}
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// SetTemplate makes PrintResultTerminal render every result with a Go
+// text/template over types.ScanResult instead of the colored output, e.g.
+//
+//	{{.URL}} {{.StatusCode}} {{join .MatchedKeywords ","}}
+//
+// Results rendering to blank lines are not printed, so templates can filter
+// with {{if .IsVulnerable}}...{{end}}. An empty text turns templating off.
+func SetTemplate(text string) error {
+	if text == "" {
+		lineTemplate = nil
+		return nil
+	}
+	tmpl, err := template.New("o-template").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return err
+	}
+	lineTemplate = tmpl
+	return nil
+}
+
+// FormatTemplate renders a result with the -o-template template, without a
+// trailing newline.
+func FormatTemplate(result types.ScanResult) (string, error) {
+	var b strings.Builder
+	if err := lineTemplate.Execute(&b, result); err != nil {
+		return "", err
+	}
+	return strings.TrimRight(b.String(), "\r\n"), nil
+}
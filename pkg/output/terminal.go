@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/nxneeraj/hx-hawks/pkg/types"
 )
@@ -11,30 +13,91 @@ import (
 const MaxResponseLength = 500 // Limit response preview length in terminal
 
 // PrintResultTerminal formats and prints a single scan result to the terminal with colors.
+// With --plain-log it prints one structured line instead (see FormatPlain),
+// with -o-template the user's line (see SetTemplate), with --silent only
+// vulnerable URLs (see SetSilent).
 func PrintResultTerminal(result types.ScanResult) {
+	if lineTemplate != nil {
+		line, err := FormatTemplate(result)
+		if err != nil {
+			log.Printf("[!] -o-template failed for %s: %v", result.URL, err)
+		} else if strings.TrimSpace(line) != "" {
+			fmt.Println(line)
+		}
+		return
+	}
+	if silent {
+		printSilent(result)
+		return
+	}
+	if plainLog {
+		fmt.Println(FormatPlain(result))
+		return
+	}
+
 	if result.Error != "" {
 		log.Printf("[%s] %s - Error: %s", ColorYellow("ERROR"), result.URL, result.Error)
 		return
 	}
 
+	if result.Unchanged {
+		fmt.Printf("[%s] %s (Status: %d)\n\n", ColorCyan("UNCHANGED"), result.URL, result.StatusCode)
+		return
+	}
+
+	if result.Duplicate && !result.IsVulnerable {
+		fmt.Printf("[%s] %s (Status: %d) same as %s\n\n", ColorCyan("DUPLICATE"), result.URL, result.StatusCode, result.DuplicateOf)
+		return
+	}
+
 	if result.IsVulnerable {
-		fmt.Printf("[%s] %s (Status: %d)\n", ColorRed("VULNERABLE"), result.URL, result.StatusCode)
+		fmt.Printf("[%s] %s (Status: %d)%s%s\n", ColorRed("VULNERABLE"), result.URL, result.StatusCode, formatSeverity(result.Severity), formatTitle(result.Title))
+		printRedirectChain(result)
+		printTechnologies(result)
+		printBlocked(result)
 		// Print response preview in blue
-		responsePreview := result.ResponseBody
-		if len(responsePreview) > MaxResponseLength {
-			responsePreview = responsePreview[:MaxResponseLength] + "..."
-		}
+		responsePreview := previewText(result.ResponseBody, MaxResponseLength)
 		// Highlight keywords in the preview
 		highlightedResponse := highlightKeywords(responsePreview, result.MatchedKeywords)
 		fmt.Printf("  Response (%s):\n%s\n", ColorBlue("Vulnerable"), ColorBlue(highlightedResponse))
 
 		// Print matched keywords
 		if len(result.MatchedKeywords) > 0 {
-			fmt.Printf("  [%s]: '%s' %s\n", ColorCyan("MATCHED"), ColorMagenta(strings.Join(result.MatchedKeywords, "', '")), ColorMagenta("🔍"))
+			fmt.Printf("  [%s]: %s %s\n", ColorCyan("MATCHED"), formatKeywords(result), ColorMagenta("🔍"))
+		}
+		printHighEntropy(result)
+		// Print matched rules with their severity
+		for _, rule := range result.MatchedRules {
+			label := rule.ID
+			if rule.Name != "" {
+				label += " - " + rule.Name
+			}
+			label = ColorMagenta(label)
+			if rule.Severity != "" {
+				label += " (" + ColorSeverity(rule.Severity) + ")"
+			}
+			fmt.Printf("  [%s]: %s\n", ColorCyan("RULE"), label)
+			if rule.Remediation != "" {
+				fmt.Printf("    Fix: %s\n", rule.Remediation)
+			}
 		}
 
+	} else if len(result.Interesting) > 0 {
+		// Not a finding, but a lead worth a manual look
+		fmt.Printf("[%s] %s (Status: %d)%s\n", ColorYellow("INTERESTING"), result.URL, result.StatusCode, formatTitle(result.Title))
+		printRedirectChain(result)
+		printTechnologies(result)
+		printBlocked(result)
+		for _, reason := range result.Interesting {
+			fmt.Printf("  [%s]: %s\n", ColorYellow("LEAD"), escapeControl(reason))
+		}
+		printHighEntropy(result)
+
 	} else {
-		fmt.Printf("[%s] %s (Status: %d)\n", ColorGreen("SAFE"), result.URL, result.StatusCode)
+		fmt.Printf("[%s] %s (Status: %d)%s\n", ColorGreen("SAFE"), result.URL, result.StatusCode, formatTitle(result.Title))
+		printRedirectChain(result)
+		printTechnologies(result)
+		printBlocked(result)
 		// Optionally print safe response preview in white
 		// responsePreview := result.ResponseBody
 		// if len(responsePreview) > MaxResponseLength {
@@ -45,12 +108,153 @@ func PrintResultTerminal(result types.ScanResult) {
 	fmt.Println() // Add a blank line for separation
 }
 
+// formatSeverity renders a colored severity suffix for result lines, or ""
+// without one.
+func formatSeverity(severity string) string {
+	if severity == "" {
+		return ""
+	}
+	return " [" + ColorSeverity(strings.ToUpper(severity)) + "]"
+}
+
+// formatKeywords renders the matched keywords of a result, quoted, each
+// followed by its colored severity when it has one.
+func formatKeywords(result types.ScanResult) string {
+	parts := make([]string, 0, len(result.MatchedKeywords))
+	for _, k := range result.MatchedKeywords {
+		part := ColorMagenta("'" + k + "'")
+		if sev := result.KeywordSeverity[k]; sev != "" {
+			part += " (" + ColorSeverity(sev) + ")"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatTitle renders a page title suffix for result lines, or "" if untitled.
+func formatTitle(title string) string {
+	if title == "" {
+		return ""
+	}
+	return fmt.Sprintf(" [%s]", ColorYellow(escapeControl(title)))
+}
+
+// printRedirectChain prints the redirects followed to reach the result's URL, if any.
+func printRedirectChain(result types.ScanResult) {
+	if len(result.RedirectChain) > 0 {
+		fmt.Printf("  [%s]: %s\n", ColorCyan("REDIRECTS"), FormatRedirectChain(result.RedirectChain, result.URL))
+	}
+	if result.LoginRedirect {
+		fmt.Printf("  [%s]: matches are on a login page, not the requested one\n", ColorYellow("LOGIN REDIRECT"))
+	}
+}
+
+// printTechnologies prints the technologies detected for the result, if any.
+func printTechnologies(result types.ScanResult) {
+	if len(result.Technologies) > 0 {
+		fmt.Printf("  [%s]: %s\n", ColorCyan("TECH"), strings.Join(result.Technologies, ", "))
+	}
+}
+
+// printHighEntropy lists the possible tokens/keys found by --entropy, if any.
+func printHighEntropy(result types.ScanResult) {
+	if len(result.HighEntropy) > 0 {
+		fmt.Printf("  [%s]: %s\n", ColorCyan("ENTROPY"), ColorMagenta(escapeControl(strings.Join(result.HighEntropy, ", "))))
+	}
+}
+
+// printBlocked notes WAF/rate-limit blocks, any evasion applied and
+// certificates that fail verification.
+func printBlocked(result types.ScanResult) {
+	if result.TLSError != "" {
+		fmt.Printf("  [%s]: %s\n", ColorYellow("TLS"), result.TLSError)
+	}
+	if result.Blocked != "" {
+		fmt.Printf("  [%s]: %s\n", ColorYellow("BLOCKED"), result.Blocked)
+	}
+	if len(result.Evasion) > 0 {
+		fmt.Printf("  [%s]: %s\n", ColorYellow("EVASION"), strings.Join(result.Evasion, ", "))
+	}
+}
+
+// FormatRedirectChain renders hops as "a (301) -> b (302) -> final".
+func FormatRedirectChain(chain []types.RedirectHop, finalURL string) string {
+	parts := make([]string, 0, len(chain)+1)
+	for _, hop := range chain {
+		parts = append(parts, fmt.Sprintf("%s (%d)", hop.URL, hop.StatusCode))
+	}
+	return strings.Join(append(parts, finalURL), " -> ")
+}
+
+// previewText returns at most max bytes of body, cut on a rune boundary and
+// made safe for a terminal (see escapeControl), with "..." if it was cut.
+func previewText(body string, max int) string {
+	if len(body) <= max {
+		return escapeControl(body)
+	}
+	cut := max
+	// Back up to the start of the rune (at most utf8.UTFMax-1 continuation bytes)
+	for cut > 0 && cut > max-utf8.UTFMax+1 && !utf8.RuneStart(body[cut]) {
+		cut--
+	}
+	return escapeControl(body[:cut]) + "..."
+}
+
+// escapeControl makes semi-binary text safe to print: invalid UTF-8 bytes,
+// control characters (ESC, CR, DEL, C1) and bidi overrides are written as
+// \xNN or \uNNNN escapes. Newlines and tabs are kept.
+func escapeControl(s string) string {
+	if isPrintable(s) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			fmt.Fprintf(&b, "\\x%02x", s[i])
+		case r == '\n' || r == '\t':
+			b.WriteRune(r)
+		case unicode.IsControl(r) || unicode.Is(unicode.Bidi_Control, r):
+			if r < 0x100 {
+				fmt.Fprintf(&b, "\\x%02x", r)
+			} else {
+				fmt.Fprintf(&b, "\\u%04x", r)
+			}
+		default:
+			b.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	return b.String()
+}
+
+// isPrintable reports whether escapeControl would leave s unchanged.
+func isPrintable(s string) bool {
+	for i, r := range s {
+		if r == utf8.RuneError {
+			if _, size := utf8.DecodeRuneInString(s[i:]); size == 1 {
+				return false
+			}
+		}
+		if r != '\n' && r != '\t' && (unicode.IsControl(r) || unicode.Is(unicode.Bidi_Control, r)) {
+			return false
+		}
+	}
+	return true
+}
+
 // highlightKeywords highlights occurrences of keywords in the text using Magenta.
 // This is a simple string replacement; more sophisticated highlighting might be needed
-// for overlapping keywords or case-insensitivity if required.
+// for overlapping keywords or case-insensitivity if required. Keywords are
+// escaped like the text (see escapeControl) so they still match a preview.
 func highlightKeywords(text string, keywords []string) string {
 	highlightedText := text
 	for _, keyword := range keywords {
+		keyword = escapeControl(keyword)
+		if keyword == "" {
+			continue
+		}
 		// Simple case-sensitive replace. Use regex for case-insensitivity or complex patterns.
 		// Need to be careful here - replacing within already colored text might break ANSI codes.
 		// A more robust solution would parse ANSI codes or highlight before adding color.
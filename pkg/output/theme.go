@@ -0,0 +1,96 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"gopkg.in/yaml.v3"
+)
+
+// themeRoles maps the keys of a --theme file to the color functions they
+// replace.
+var themeRoles = map[string]*func(a ...interface{}) string{
+	"safe":       &ColorGreen,   // SAFE results; the default is green
+	"vulnerable": &ColorRed,     // VULNERABLE results, critical/high severities; red
+	"response":   &ColorBlue,    // Response previews; blue
+	"keyword":    &ColorMagenta, // Matched keywords and rules; magenta
+	"warning":    &ColorYellow,  // Errors, leads, blocks, medium severity; yellow
+	"detail":     &ColorCyan,    // Labels such as MATCHED and TECH, low severity; cyan
+	"neutral":    &ColorWhite,   // Info severity; white
+}
+
+// colorAttributes are the names accepted in a --theme file.
+var colorAttributes = map[string]color.Attribute{
+	"black": color.FgBlack, "red": color.FgRed, "green": color.FgGreen, "yellow": color.FgYellow,
+	"blue": color.FgBlue, "magenta": color.FgMagenta, "cyan": color.FgCyan, "white": color.FgWhite,
+	"hi-black": color.FgHiBlack, "hi-red": color.FgHiRed, "hi-green": color.FgHiGreen, "hi-yellow": color.FgHiYellow,
+	"hi-blue": color.FgHiBlue, "hi-magenta": color.FgHiMagenta, "hi-cyan": color.FgHiCyan, "hi-white": color.FgHiWhite,
+	"bg-black": color.BgBlack, "bg-red": color.BgRed, "bg-green": color.BgGreen, "bg-yellow": color.BgYellow,
+	"bg-blue": color.BgBlue, "bg-magenta": color.BgMagenta, "bg-cyan": color.BgCyan, "bg-white": color.BgWhite,
+	"bold": color.Bold, "faint": color.Faint, "italic": color.Italic, "underline": color.Underline,
+	"reset": color.Reset, // Plain text, e.g. "response: reset"
+}
+
+// DisableColor turns off ANSI colors everywhere (--no-color, NO_COLOR).
+// Colors are already off when stdout isn't a terminal.
+func DisableColor() {
+	color.NoColor = true
+}
+
+// LoadTheme replaces the terminal colors with those of a YAML theme file
+// mapping roles to space-separated color names, e.g.
+//
+//	vulnerable: hi-red bold
+//	keyword: black bg-yellow
+//	response: reset
+//
+// Roles left out keep their default color. An empty path does nothing.
+func LoadTheme(path string) error {
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var theme map[string]string
+	if err := yaml.Unmarshal(data, &theme); err != nil {
+		return fmt.Errorf("parsing theme %s: %w", path, err)
+	}
+	funcs := make(map[string]func(a ...interface{}) string, len(theme))
+	for role, value := range theme {
+		if themeRoles[role] == nil {
+			return fmt.Errorf("theme %s: unknown role %q (use %s)", path, role, strings.Join(sortedKeys(themeRoles), ", "))
+		}
+		var attrs []color.Attribute
+		for _, name := range strings.Fields(strings.ReplaceAll(value, ",", " ")) {
+			attr, ok := colorAttributes[strings.ToLower(name)]
+			if !ok {
+				return fmt.Errorf("theme %s: %s: unknown color %q", path, role, name)
+			}
+			attrs = append(attrs, attr)
+		}
+		if len(attrs) == 0 {
+			return fmt.Errorf("theme %s: %s: no color given", path, role)
+		}
+		funcs[role] = color.New(attrs...).SprintFunc()
+	}
+	// Only apply a theme that is valid as a whole
+	for role, fn := range funcs {
+		*themeRoles[role] = fn
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of m in order, for messages.
+func sortedKeys(m map[string]*func(a ...interface{}) string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
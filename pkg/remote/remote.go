@@ -0,0 +1,200 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/nxneeraj/hx-hawks/pkg/client"
+	"github.com/nxneeraj/hx-hawks/pkg/config"
+	"github.com/nxneeraj/hx-hawks/pkg/notify"
+	"github.com/nxneeraj/hx-hawks/pkg/output"
+	"github.com/nxneeraj/hx-hawks/pkg/scanner"
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+// Run submits the scan to the API server at cfg.Remote (or reattaches to
+// cfg.Attach), handles every result as the server streams it (terminal,
+// JSONL, Elasticsearch, notifications) and writes the other output files
+// once the job finishes.
+func Run(ctx context.Context, cfg *config.Config, urls []string) error {
+	c := client.New(cfg.Remote)
+	if len(cfg.APIKeys) > 0 {
+		c.APIKey = cfg.APIKeys[0].Key
+	}
+
+	if cfg.RulesFile != "" {
+		log.Printf("[!] Rules files are not sent to remote servers; only --ck keywords and --recipe are used")
+	}
+
+	jobID := cfg.Attach
+	if jobID == "" {
+		if cfg.CookieFile != "" {
+			log.Println("[!] --cookie-file is not sent to remote servers, pass the cookies with --cookie instead")
+		}
+		var err error
+		jobID, err = c.StartScan(ctx, BuildRequest(cfg, urls))
+		if err != nil {
+			return fmt.Errorf("starting remote scan: %w", err)
+		}
+		log.Printf("[+] Remote scan submitted to %s (Job ID: %s)", cfg.Remote, jobID)
+	} else {
+		if _, err := c.GetStatus(ctx, jobID); err != nil {
+			return fmt.Errorf("attaching to job %s: %w", jobID, err)
+		}
+		log.Printf("[+] Reattached to remote job %s on %s", jobID, cfg.Remote)
+	}
+
+	if cfg.Detach {
+		log.Printf("[+] Detached. Reattach with: hx-hawks scan --remote %s --attach %s", cfg.Remote, jobID)
+		return nil
+	}
+
+	var jsonl *output.JSONLWriter
+	if cfg.OutputJSONL != "" {
+		var err error
+		if jsonl, err = output.CreateJSONL(cfg.OutputJSONL, cfg.Fields, cfg.Append); err != nil {
+			return fmt.Errorf("creating JSONL output: %w", err)
+		}
+		defer jsonl.Close()
+	}
+	var es *output.ESIndexer
+	if cfg.ESURL != "" {
+		var err error
+		if es, err = output.NewESIndexer(cfg.ESURL, cfg.ESIndex, cfg.Fields); err != nil {
+			return fmt.Errorf("starting Elasticsearch indexing: %w", err)
+		}
+		defer func() {
+			closeCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			defer cancel()
+			if err := es.Close(closeCtx); err != nil {
+				log.Printf("[!] Elasticsearch: gave up sending the last documents: %v", err)
+			}
+			indexed, failed := es.Stats()
+			log.Printf("[+] Elasticsearch: %d results indexed into %s, %d failed", indexed, cfg.ESIndex, failed)
+		}()
+	}
+	notifier := notify.NewDispatcher(cfg.Notifiers, cfg.NotifyEvents, cfg.NotifyBatch, cfg.NotifyInterval)
+	var summary *notify.Summary // Set once the job has finished; no summary when detaching on Ctrl+C
+	if notifier != nil {
+		defer func() {
+			closeCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			notifier.Close(closeCtx, summary)
+			log.Printf("[+] Notifications: %s", notifier.Report())
+		}()
+	}
+	started := time.Now()
+	results := make([]types.ScanResult, 0)
+	// Each result is printed, written to JSONL, indexed and notified as the
+	// server reports it, not once the job is over
+	status, err := c.Stream(ctx, jobID, func(result types.ScanResult) error {
+		output.PrintResultTerminal(result)
+		results = append(results, result)
+		if err := jsonl.Write(result); err != nil {
+			log.Printf("[!] Failed to write JSONL output %s: %v", cfg.OutputJSONL, err)
+		}
+		if err := es.Write(result); err != nil {
+			log.Printf("[!] Failed to index %s: %v", result.URL, err)
+		}
+		notifier.Notify(result)
+		return nil
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			// Interrupted locally: the job keeps running on the server
+			log.Printf("[!] Interrupted. Job %s continues remotely; reattach with: hx-hawks scan --remote %s --attach %s", jobID, cfg.Remote, jobID)
+			return nil
+		}
+		return fmt.Errorf("streaming results for job %s: %w", jobID, err)
+	}
+
+	done := notify.Summarize(results, started, false)
+	summary = &done
+	numVulnerable := 0
+	for _, r := range results {
+		if r.IsVulnerable {
+			numVulnerable++
+		}
+	}
+	log.Printf("[+] Total URLs Scanned: %d", len(results))
+	log.Printf("[+] Vulnerable URLs Found: %d", numVulnerable)
+	if len(status.StatusCodes) > 0 {
+		log.Printf("[+] Status codes: %s", scanner.FormatStatusCounts(status.StatusCodes))
+	}
+	if status.Status != "Completed" {
+		log.Printf("[!] Remote job %s ended as %s %s", jobID, status.Status, status.Error)
+	}
+
+	return output.WriteResultsToFile(ctx, cfg, results)
+}
+
+// BuildRequest translates the CLI configuration into an API scan request.
+func BuildRequest(cfg *config.Config, urls []string) types.ScanRequest {
+	crawlExclude := ""
+	if cfg.CrawlExclude != nil {
+		crawlExclude = cfg.CrawlExclude.String()
+	}
+	proxy := ""
+	if cfg.Proxy != nil {
+		proxy = cfg.Proxy.String()
+	}
+	return types.ScanRequest{
+		URLs:              urls,
+		Keywords:          cfg.Keywords,
+		KeywordHosts:      cfg.KeywordScope.Hosts,
+		KeywordTech:       cfg.KeywordScope.Tech,
+		TimeoutSec:        int(cfg.Timeout.Seconds()),
+		ConnectTimeoutSec: int(cfg.ConnectTimeout.Seconds()),
+		TLSTimeoutSec:     int(cfg.TLSTimeout.Seconds()),
+		HeaderTimeoutSec:  int(cfg.HeaderTimeout.Seconds()),
+		ReadTimeoutSec:    int(cfg.ReadTimeout.Seconds()),
+		Threads:           cfg.Threads,
+		DelayMs:           int(cfg.Delay.Milliseconds()),
+		Verbose:           cfg.Verbose,
+		MatchCodes:        cfg.MatchCodes,
+		FilterCodes:       cfg.FilterCodes,
+		MatchSize:         config.FormatSizeRanges(cfg.MatchSizes),
+		FilterSize:        config.FormatSizeRanges(cfg.FilterSizes),
+		SkipBinary:        cfg.SkipBinary,
+		LoginRedirects:    cfg.LoginRedirects,
+		TLSVerify:         cfg.TLSVerify,
+		Entropy:           cfg.Entropy,
+		EntropyThreshold:  cfg.EntropyThreshold,
+		FullBody:          cfg.FullBody,
+		MaxBodySize:       cfg.MaxBodySize,
+		Recipes:           cfg.Recipes,
+		Selectors:         cfg.Selectors,
+		JSONPaths:         cfg.JSONPaths,
+		Pins:              config.FormatCertPins(cfg.CertPins),
+		DedupeResponses:   cfg.DedupeResponses,
+		TechDetect:        cfg.TechDetect,
+		Evasion:           cfg.Evasion,
+		Crawl:             cfg.Crawl,
+		CrawlDepth:        cfg.CrawlDepth,
+		CrawlMaxPages:     cfg.CrawlMaxPages,
+		CrawlScope:        cfg.CrawlScope,
+		CrawlExclude:      crawlExclude,
+		Sitemap:           cfg.Sitemap,
+		Shuffle:           cfg.Shuffle,
+		Interleave:        cfg.Interleave,
+		StallTimeoutSec:   int(cfg.StallTimeout.Seconds()),
+		StallAbort:        cfg.StallAbort,
+		Headers:           config.HeaderMap(cfg.Headers),
+		VHosts:            cfg.VHosts,
+		Campaign:          cfg.Campaign,
+		Label:             cfg.CampaignLabel,
+		AuthBasic:         cfg.AuthBasic,
+		AuthBearer:        cfg.AuthBearer,
+		Cookies:           config.FormatCookies(cfg.Cookies),
+		CookieJar:         cfg.CookieJar,
+		Method:            cfg.Method,
+		Data:              string(cfg.Data),
+		ContentType:       cfg.ContentType,
+		Proxy:             proxy,
+		Retries:           cfg.Retries,
+		MaxRedirects:      cfg.MaxRedirects,
+		NoRedirects:       cfg.NoRedirects,
+	}
+}
@@ -0,0 +1,188 @@
+package rules
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"github.com/nxneeraj/hx-hawks/pkg/matcher"
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+// Engine evaluates plain keywords (--ck) and rules against responses using
+// one shared automaton built from every keyword. It is safe for concurrent use.
+type Engine struct {
+	Keywords     []string
+	KeywordScope Scope // Where plain keywords count (--ck-hosts, --ck-tech)
+	Rules        []Rule
+	Automaton    *matcher.Automaton
+
+	plain map[string]bool // Keywords that apply everywhere (--ck)
+}
+
+// NewEngine builds an engine. The rules must already be compiled.
+func NewEngine(keywords []string, ruleSet []Rule) *Engine {
+	e := &Engine{
+		Keywords: keywords,
+		Rules:    ruleSet,
+		plain:    make(map[string]bool, len(keywords)),
+	}
+	for _, k := range keywords {
+		e.plain[k] = true
+	}
+	e.Automaton = matcher.New(append(append([]string{}, keywords...), Keywords(ruleSet)...))
+	return e
+}
+
+// NeedsFullBody reports whether matching needs the whole body rather than
+// stopping once every keyword has been seen (regex, selector and JSONPath rules do).
+func (e *Engine) NeedsFullBody() bool {
+	for i := range e.Rules {
+		if e.Rules[i].Regex != "" || len(e.Rules[i].Selectors) > 0 || len(e.Rules[i].JSONPaths) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Evaluate turns the keywords found by the automaton into the final result:
+// the matched keywords that apply to targetURL and the rules that fired.
+// technologies are the response's fingerprints, for tech-scoped rules.
+func (e *Engine) Evaluate(targetURL string, technologies, found []string, body []byte) ([]string, []types.RuleMatch) {
+	if len(e.Rules) == 0 && e.KeywordScope.IsZero() {
+		return found, nil
+	}
+
+	path, host := "/", ""
+	if u, err := url.Parse(targetURL); err == nil {
+		if u.Path != "" {
+			path = u.Path
+		}
+		host = strings.ToLower(u.Hostname())
+	}
+	plainApplies := e.KeywordScope.Applies(host, technologies)
+	foundSet := make(map[string]bool, len(found))
+	for _, k := range found {
+		foundSet[k] = true
+	}
+
+	applicable := make(map[string]bool, len(found))
+	for k := range foundSet {
+		if e.plain[k] && plainApplies {
+			applicable[k] = true
+		}
+	}
+
+	var hits []types.RuleMatch
+	var doc *html.Node // Parsed on first use by a selector rule
+	parsed := false
+	var jsonDoc interface{} // Decoded on first use by a JSONPath rule
+	decoded := false
+	for i := range e.Rules {
+		r := &e.Rules[i]
+		if !r.AppliesToPath(path) || !r.AppliesToHost(host) || !r.AppliesToTech(technologies) {
+			continue
+		}
+		hit := false
+		for _, k := range r.Keywords {
+			if foundSet[k] {
+				applicable[k] = true
+				hit = true
+			}
+		}
+		if !hit && r.MatchRegex(body) {
+			hit = true
+		}
+		if !hit && len(r.sels) > 0 {
+			if !parsed {
+				doc, parsed = parseHTML(body), true
+			}
+			hit = r.MatchSelectors(doc)
+		}
+		if !hit && len(r.jps) > 0 {
+			if !decoded {
+				jsonDoc, decoded = parseJSON(body), true
+			}
+			hit = r.MatchJSONPaths(jsonDoc)
+		}
+		if hit {
+			hits = append(hits, types.RuleMatch{
+				ID:          r.ID,
+				Name:        r.Name,
+				Severity:    r.Severity,
+				Confidence:  r.Confidence,
+				Remediation: r.Remediation,
+				References:  r.References,
+			})
+		}
+	}
+
+	// Keep the automaton's order for the surviving keywords
+	keywords := make([]string, 0, len(applicable))
+	for _, k := range found {
+		if applicable[k] {
+			keywords = append(keywords, k)
+		}
+	}
+	return keywords, hits
+}
+
+// AppliesToPath reports whether the rule should be evaluated for a request
+// path. Rules without paths apply everywhere; rules with paths only apply to
+// the paths they probe.
+func (r *Rule) AppliesToPath(path string) bool {
+	if len(r.Paths) == 0 {
+		return true
+	}
+	for _, p := range r.Paths {
+		if strings.HasSuffix(path, "/"+strings.TrimPrefix(p, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExpandTargets appends, for every distinct base URL (scheme://host) in
+// urls, one target per rule path. The original URLs are kept first.
+func ExpandTargets(urls []string, ruleSet []Rule) []string {
+	var paths []string
+	seenPath := make(map[string]bool)
+	for _, r := range ruleSet {
+		for _, p := range r.Paths {
+			p = "/" + strings.TrimPrefix(p, "/")
+			if !seenPath[p] {
+				seenPath[p] = true
+				paths = append(paths, p)
+			}
+		}
+	}
+	if len(paths) == 0 {
+		return urls
+	}
+
+	expanded := append([]string{}, urls...)
+	seenTarget := make(map[string]bool, len(urls))
+	for _, u := range urls {
+		seenTarget[u] = true
+	}
+	seenBase := make(map[string]bool)
+	for _, raw := range urls {
+		u, err := url.Parse(raw)
+		if err != nil || u.Host == "" {
+			continue
+		}
+		base := u.Scheme + "://" + u.Host
+		if seenBase[base] {
+			continue
+		}
+		seenBase[base] = true
+		for _, p := range paths {
+			if target := base + p; !seenTarget[target] {
+				seenTarget[target] = true
+				expanded = append(expanded, target)
+			}
+		}
+	}
+	return expanded
+}
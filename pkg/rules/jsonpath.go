@@ -0,0 +1,67 @@
+package rules
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/nxneeraj/hx-hawks/pkg/jsonpath"
+)
+
+// compileJSONPaths parses the rule's JSONPath expressions.
+func (r *Rule) compileJSONPaths() error {
+	r.jps = nil
+	for _, s := range r.JSONPaths {
+		expr, err := jsonpath.Compile(s)
+		if err != nil {
+			return fmt.Errorf("rule %q: %w", r.ID, err)
+		}
+		r.jps = append(r.jps, expr)
+	}
+	return nil
+}
+
+// MatchJSONPaths reports whether any of the rule's JSONPath expressions
+// matches the decoded document. A nil document (non-JSON body) never matches.
+func (r *Rule) MatchJSONPaths(doc interface{}) bool {
+	if doc == nil {
+		return false
+	}
+	for _, expr := range r.jps {
+		if expr.Match(doc) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseJSON decodes a body that looks like a JSON object or array, or
+// returns nil if it isn't JSON.
+func parseJSON(body []byte) interface{} {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return nil
+	}
+	var doc interface{}
+	if err := json.Unmarshal(trimmed, &doc); err != nil {
+		return nil
+	}
+	return doc
+}
+
+// JSONPathRules turns ad-hoc JSONPath expressions (--match-jsonpath) into
+// compiled rules, one per expression, with IDs jsonpath-1, jsonpath-2, ...
+func JSONPathRules(exprs []string) ([]Rule, error) {
+	var out []Rule
+	for i, s := range exprs {
+		out = append(out, Rule{
+			ID:        fmt.Sprintf("jsonpath-%d", i+1),
+			Name:      "JSONPath " + s,
+			JSONPaths: []string{s},
+		})
+	}
+	if err := Compile(out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,179 @@
+package rules
+
+import (
+	"fmt"
+	"regexp/syntax"
+	"sort"
+	"strings"
+)
+
+// Conflict describes redundancy found in a keyword/rule set that would
+// waste matching work or double-count findings.
+type Conflict struct {
+	Kind    string // "duplicate-keyword", "subsumed-keyword", "duplicate-regex", "duplicate-rule"
+	Message string
+}
+
+// Lint detects duplicate keywords, keywords subsumed by shorter ones,
+// regexes that are equivalent to each other or to a keyword, and rules
+// with identical matchers.
+func Lint(keywords []string, ruleSet []Rule) []Conflict {
+	var conflicts []Conflict
+	add := func(kind, format string, args ...interface{}) {
+		conflicts = append(conflicts, Conflict{Kind: kind, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// Where each keyword comes from ("--ck" or "rule <id>")
+	sources := make(map[string][]string)
+	var ordered []string
+	note := func(k, source string) {
+		if _, ok := sources[k]; !ok {
+			ordered = append(ordered, k)
+		}
+		sources[k] = append(sources[k], source)
+	}
+	for _, k := range keywords {
+		note(k, "--ck")
+	}
+	for _, r := range ruleSet {
+		for _, k := range r.Keywords {
+			note(k, "rule "+r.ID)
+		}
+	}
+
+	for _, k := range ordered {
+		if len(sources[k]) > 1 {
+			add("duplicate-keyword", "keyword %q is defined %d times (%s)", k, len(sources[k]), strings.Join(sources[k], ", "))
+		}
+	}
+	for _, long := range ordered {
+		for _, short := range ordered {
+			if short != long && strings.Contains(long, short) {
+				add("subsumed-keyword", "keyword %q (%s) always matches when %q (%s) does", short, sources[short][0], long, sources[long][0])
+			}
+		}
+	}
+
+	// Regexes: compare normalized forms, and literal regexes against keywords
+	seenRegex := make(map[string]string)
+	for _, r := range ruleSet {
+		if r.Regex == "" {
+			continue
+		}
+		norm, literal, ok := normalizeRegex(r.Regex)
+		if !ok {
+			continue
+		}
+		if other, dup := seenRegex[norm]; dup {
+			add("duplicate-regex", "rule %s regex is equivalent to rule %s regex (%s)", r.ID, other, r.Regex)
+		} else {
+			seenRegex[norm] = r.ID
+		}
+		if literal != "" {
+			if src, isKeyword := sources[literal]; isKeyword {
+				add("duplicate-regex", "rule %s regex %q is the literal keyword %q (%s)", r.ID, r.Regex, literal, src[0])
+			}
+		}
+	}
+
+	// Rules whose matchers are identical
+	seenMatcher := make(map[string]string)
+	for _, r := range ruleSet {
+		sig := matcherSignature(r)
+		if other, dup := seenMatcher[sig]; dup {
+			add("duplicate-rule", "rule %s has the same matchers as rule %s", r.ID, other)
+		} else {
+			seenMatcher[sig] = r.ID
+		}
+	}
+
+	return conflicts
+}
+
+// Dedupe removes the redundancy reported by Lint: repeated --ck keywords,
+// --ck keywords that are subsumed by a shorter --ck keyword or already
+// covered by a rule, and rules whose matchers duplicate an earlier rule.
+func Dedupe(keywords []string, ruleSet []Rule) ([]string, []Rule) {
+	var dedupedRules []Rule
+	seenMatcher := make(map[string]bool)
+	ruleKeywords := make(map[string]bool)
+	for _, r := range ruleSet {
+		sig := matcherSignature(r)
+		if seenMatcher[sig] {
+			continue
+		}
+		seenMatcher[sig] = true
+		dedupedRules = append(dedupedRules, r)
+		for _, k := range r.Keywords {
+			ruleKeywords[k] = true
+		}
+	}
+
+	var dedupedKeywords []string
+	seen := make(map[string]bool)
+	for _, k := range keywords {
+		if seen[k] || ruleKeywords[k] {
+			continue
+		}
+		seen[k] = true
+		subsumed := false
+		for _, other := range keywords {
+			if other != k && strings.Contains(k, other) {
+				subsumed = true
+				break
+			}
+		}
+		if !subsumed {
+			dedupedKeywords = append(dedupedKeywords, k)
+		}
+	}
+	return dedupedKeywords, dedupedRules
+}
+
+// normalizeRegex returns a canonical form of the regex and, if the regex
+// only matches one literal string, that literal.
+func normalizeRegex(expr string) (norm, literal string, ok bool) {
+	re, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return "", "", false
+	}
+	re = stripCaptures(re.Simplify())
+	if re.Op == syntax.OpLiteral && re.Flags&syntax.FoldCase == 0 {
+		literal = string(re.Rune)
+	}
+	return re.String(), literal, true
+}
+
+// stripCaptures replaces capture groups with their contents, since
+// grouping doesn't change what a regex matches.
+func stripCaptures(re *syntax.Regexp) *syntax.Regexp {
+	for re.Op == syntax.OpCapture {
+		re = re.Sub[0]
+	}
+	for i, sub := range re.Sub {
+		re.Sub[i] = stripCaptures(sub)
+	}
+	return re
+}
+
+// matcherSignature identifies a rule by what it matches (not its metadata).
+func matcherSignature(r Rule) string {
+	kws := append([]string{}, r.Keywords...)
+	sort.Strings(kws)
+	paths := append([]string{}, r.Paths...)
+	sort.Strings(paths)
+	regex := r.Regex
+	if norm, _, ok := normalizeRegex(r.Regex); ok && r.Regex != "" {
+		regex = norm
+	}
+	sels := append([]string{}, r.Selectors...)
+	sort.Strings(sels)
+	jps := append([]string{}, r.JSONPaths...)
+	sort.Strings(jps)
+	hosts := append([]string{}, r.Hosts...)
+	sort.Strings(hosts)
+	tech := append([]string{}, r.Tech...)
+	sort.Strings(tech)
+	return strings.Join(kws, "\x00") + "\x01" + regex + "\x01" + strings.Join(sels, "\x00") + "\x01" + strings.Join(jps, "\x00") + "\x01" + strings.Join(paths, "\x00") +
+		"\x01" + strings.Join(hosts, "\x00") + "\x01" + strings.Join(tech, "\x00")
+}
@@ -0,0 +1,99 @@
+package rules
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// recipes are built-in rule sets for well-known exposures.
+var recipes = map[string][]Rule{
+	"exposed-git": {
+		{
+			ID:          "git-config",
+			Name:        "Exposed .git/config",
+			Severity:    "high",
+			Paths:       []string{"/.git/config"},
+			Keywords:    []string{"[core]", "repositoryformatversion"},
+			Remediation: "Block access to the .git directory in the web server configuration and remove it from the deployed document root.",
+			References:  []string{"https://owasp.org/www-project-web-security-testing-guide/latest/4-Web_Application_Security_Testing/02-Configuration_and_Deployment_Management_Testing/04-Review_Old_Backup_and_Unreferenced_Files_for_Sensitive_Information"},
+		},
+		{
+			ID:          "git-head",
+			Name:        "Exposed .git/HEAD",
+			Severity:    "medium",
+			Paths:       []string{"/.git/HEAD"},
+			Regex:       `^ref: refs/heads/`,
+			Remediation: "Block access to the .git directory in the web server configuration and remove it from the deployed document root.",
+		},
+	},
+	"env-files": {
+		{
+			ID:          "dotenv",
+			Name:        "Exposed environment file",
+			Severity:    "critical",
+			Paths:       []string{"/.env", "/.env.local", "/.env.production", "/.env.dev", "/.env.backup"},
+			Keywords:    []string{"DB_PASSWORD=", "APP_KEY=", "AWS_SECRET_ACCESS_KEY=", "DATABASE_URL=", "SECRET_KEY="},
+			Remediation: "Remove .env files from the document root, deny dotfiles in the web server, and rotate every secret the file contained.",
+		},
+	},
+	"debug-endpoints": {
+		{
+			ID:          "spring-actuator",
+			Name:        "Spring Boot Actuator exposed",
+			Severity:    "high",
+			Paths:       []string{"/actuator/env", "/actuator/heapdump", "/actuator/mappings", "/env"},
+			Keywords:    []string{"activeProfiles", "propertySources", "dispatcherServlets"},
+			Remediation: "Restrict management.endpoints.web.exposure.include to health/info, or require authentication on the actuator endpoints.",
+			References:  []string{"https://docs.spring.io/spring-boot/docs/current/reference/html/actuator.html#actuator.endpoints.security"},
+		},
+		{
+			ID:          "phpinfo",
+			Name:        "phpinfo() page exposed",
+			Severity:    "medium",
+			Paths:       []string{"/phpinfo.php", "/info.php", "/php_info.php", "/test.php"},
+			Keywords:    []string{"<title>phpinfo()</title>", "PHP Version"},
+			Remediation: "Delete phpinfo() test pages from production servers.",
+		},
+		{
+			ID:          "django-debug",
+			Name:        "Django debug mode enabled",
+			Severity:    "medium",
+			Paths:       []string{"/__debug__/", "/nonexistent-hawks-probe"},
+			Keywords:    []string{"You're seeing this error because you have <code>DEBUG = True</code>"},
+			Remediation: "Set DEBUG = False in production settings.",
+			References:  []string{"https://docs.djangoproject.com/en/stable/ref/settings/#debug"},
+		},
+		{
+			ID:          "server-status",
+			Name:        "Apache server-status exposed",
+			Severity:    "low",
+			Paths:       []string{"/server-status"},
+			Keywords:    []string{"Apache Server Status for"},
+			Remediation: "Restrict mod_status (<Location /server-status>) to trusted addresses.",
+		},
+	},
+}
+
+// RecipeNames returns the names of the built-in recipes.
+func RecipeNames() []string {
+	names := make([]string, 0, len(recipes))
+	for name := range recipes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Recipe returns a compiled copy of the named built-in recipe.
+func Recipe(name string) ([]Rule, error) {
+	r, ok := recipes[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown recipe %q (available: %s)", name, strings.Join(RecipeNames(), ", "))
+	}
+	ruleSet := append([]Rule{}, r...)
+	if err := Compile(ruleSet); err != nil {
+		return nil, err
+	}
+	return ruleSet, nil
+}
@@ -0,0 +1,129 @@
+package rules
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/andybalholm/cascadia"
+	"github.com/nxneeraj/hx-hawks/pkg/jsonpath"
+	"gopkg.in/yaml.v3"
+)
+
+// Rule is a named signature matched against response bodies. A rule
+// matches when any of its keywords is present, its regex matches or one of
+// its CSS selectors matches an element of the HTML document or one of its
+// JSONPath expressions holds for a JSON body.
+// Rules with paths are only evaluated on those paths, which are added
+// to every target's base URL. Rules with hosts or tech are only evaluated
+// on matching hosts, or on responses fingerprinted with that technology.
+type Rule struct {
+	ID         string   `yaml:"id" json:"id"`
+	Name       string   `yaml:"name,omitempty" json:"name,omitempty"`
+	Keywords   []string `yaml:"keywords,omitempty" json:"keywords,omitempty"`
+	Regex      string   `yaml:"regex,omitempty" json:"regex,omitempty"`
+	Selectors  []string `yaml:"selectors,omitempty" json:"selectors,omitempty"`   // CSS selectors, e.g. form[action*="login"]
+	JSONPaths  []string `yaml:"jsonpaths,omitempty" json:"jsonpaths,omitempty"`   // JSONPath expressions, e.g. $.debug == true
+	Severity   string   `yaml:"severity,omitempty" json:"severity,omitempty"`     // e.g. info, low, medium, high, critical
+	Confidence string   `yaml:"confidence,omitempty" json:"confidence,omitempty"` // "low" = matches are leads ("interesting" tier), not findings
+	Paths      []string `yaml:"paths,omitempty" json:"paths,omitempty"`           // Paths probed on each target; scopes the rule to them
+	Hosts      []string `yaml:"hosts,omitempty" json:"hosts,omitempty"`           // Host patterns the rule applies to, e.g. *.corp.example.com
+	Tech       []string `yaml:"tech,omitempty" json:"tech,omitempty"`             // Technologies the response must be fingerprinted with, e.g. WordPress
+
+	Remediation string   `yaml:"remediation,omitempty" json:"remediation,omitempty"` // How to fix the finding, for developers
+	References  []string `yaml:"references,omitempty" json:"references,omitempty"`   // Links with background on the issue
+
+	re   *regexp.Regexp           // Compiled Regex
+	sels []cascadia.SelectorGroup // Compiled Selectors
+	jps  []*jsonpath.Expr         // Compiled JSONPaths
+}
+
+// File is the top-level layout of a rules YAML file.
+type File struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// Load reads and compiles the rules in a YAML file.
+func Load(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f File
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parsing rules file %s: %w", path, err)
+	}
+	if err := Compile(f.Rules); err != nil {
+		return nil, fmt.Errorf("rules file %s: %w", path, err)
+	}
+	return f.Rules, nil
+}
+
+// Compile validates the rules and compiles their regexes in place.
+func Compile(rules []Rule) error {
+	ids := make(map[string]bool, len(rules))
+	for i := range rules {
+		r := &rules[i]
+		if r.ID == "" {
+			return fmt.Errorf("rule #%d has no id", i+1)
+		}
+		if ids[r.ID] {
+			return fmt.Errorf("duplicate rule id %q", r.ID)
+		}
+		ids[r.ID] = true
+		if len(r.Keywords) == 0 && r.Regex == "" && len(r.Selectors) == 0 && len(r.JSONPaths) == 0 {
+			return fmt.Errorf("rule %q has no keywords, regex, selectors or jsonpaths", r.ID)
+		}
+		if r.Confidence != "" && r.Confidence != "low" && r.Confidence != "high" {
+			return fmt.Errorf("rule %q: confidence must be low or high, got %q", r.ID, r.Confidence)
+		}
+		if err := r.validateHosts(); err != nil {
+			return err
+		}
+		if err := r.compileSelectors(); err != nil {
+			return err
+		}
+		if err := r.compileJSONPaths(); err != nil {
+			return err
+		}
+		if r.Regex != "" {
+			re, err := regexp.Compile(r.Regex)
+			if err != nil {
+				return fmt.Errorf("rule %q: invalid regex: %w", r.ID, err)
+			}
+			r.re = re
+		}
+	}
+	return nil
+}
+
+// Matches reports whether the rule matches body.
+func (r *Rule) Matches(body []byte) bool {
+	for _, k := range r.Keywords {
+		if k != "" && bytes.Contains(body, []byte(k)) {
+			return true
+		}
+	}
+	if r.MatchRegex(body) {
+		return true
+	}
+	if len(r.sels) > 0 && r.MatchSelectors(parseHTML(body)) {
+		return true
+	}
+	return len(r.jps) > 0 && r.MatchJSONPaths(parseJSON(body))
+}
+
+// MatchRegex reports whether the rule's regex (if any) matches body.
+func (r *Rule) MatchRegex(body []byte) bool {
+	return r.re != nil && r.re.Match(body)
+}
+
+// Keywords returns every keyword across the rules.
+func Keywords(rules []Rule) []string {
+	var all []string
+	for _, r := range rules {
+		all = append(all, r.Keywords...)
+	}
+	return all
+}
@@ -0,0 +1,93 @@
+package rules
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// validateHosts checks the rule's host patterns.
+func (r *Rule) validateHosts() error {
+	if err := (Scope{Hosts: r.Hosts}).Validate(); err != nil {
+		return fmt.Errorf("rule %q: %w", r.ID, err)
+	}
+	return nil
+}
+
+// AppliesToHost reports whether the rule should be evaluated for host
+// (lowercase, without port). Patterns use path.Match syntax, and
+// "*.example.com" also covers nested subdomains. Rules without hosts apply
+// everywhere.
+func (r *Rule) AppliesToHost(host string) bool {
+	if len(r.Hosts) == 0 {
+		return true
+	}
+	for _, h := range r.Hosts {
+		h = strings.ToLower(h)
+		if ok, _ := path.Match(h, host); ok {
+			return true
+		}
+		if strings.HasPrefix(h, "*.") && strings.HasSuffix(host, h[1:]) {
+			return true
+		}
+	}
+	return false
+}
+
+// AppliesToTech reports whether the rule should be evaluated for a response
+// fingerprinted with technologies ("Name" or "Name/version"). Names compare
+// case-insensitively; rules without tech apply everywhere.
+func (r *Rule) AppliesToTech(technologies []string) bool {
+	if len(r.Tech) == 0 {
+		return true
+	}
+	for _, want := range r.Tech {
+		for _, t := range technologies {
+			name, _, _ := strings.Cut(t, "/")
+			if strings.EqualFold(name, want) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Scope limits where plain keywords (--ck) count, like a rule's hosts and
+// tech do. The zero Scope applies everywhere.
+type Scope struct {
+	Hosts []string `json:"hosts,omitempty"`
+	Tech  []string `json:"tech,omitempty"`
+}
+
+// Validate checks the scope's host patterns.
+func (s Scope) Validate() error {
+	for _, h := range s.Hosts {
+		if _, err := path.Match(strings.ToLower(h), ""); err != nil || h == "" {
+			return fmt.Errorf("invalid host pattern %q", h)
+		}
+	}
+	return nil
+}
+
+// IsZero reports whether the scope applies everywhere.
+func (s Scope) IsZero() bool {
+	return len(s.Hosts) == 0 && len(s.Tech) == 0
+}
+
+// Applies reports whether keywords with this scope count for host and
+// technologies, with the same matching as rules.
+func (s Scope) Applies(host string, technologies []string) bool {
+	r := Rule{Hosts: s.Hosts, Tech: s.Tech}
+	return r.AppliesToHost(host) && r.AppliesToTech(technologies)
+}
+
+// NeedsTech reports whether any rule is scoped to technologies, which
+// requires technology fingerprinting.
+func NeedsTech(ruleSet []Rule) bool {
+	for i := range ruleSet {
+		if len(ruleSet[i].Tech) > 0 {
+			return true
+		}
+	}
+	return false
+}
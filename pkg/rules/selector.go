@@ -0,0 +1,62 @@
+package rules
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/andybalholm/cascadia"
+	"golang.org/x/net/html"
+)
+
+// compileSelectors parses the rule's CSS selectors.
+func (r *Rule) compileSelectors() error {
+	r.sels = nil
+	for _, s := range r.Selectors {
+		sel, err := cascadia.ParseGroup(s)
+		if err != nil {
+			return fmt.Errorf("rule %q: invalid selector %q: %w", r.ID, s, err)
+		}
+		r.sels = append(r.sels, sel)
+	}
+	return nil
+}
+
+// MatchSelectors reports whether any of the rule's CSS selectors matches an
+// element of the parsed document.
+func (r *Rule) MatchSelectors(doc *html.Node) bool {
+	if doc == nil {
+		return false
+	}
+	for _, sel := range r.sels {
+		if cascadia.Query(doc, sel) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// parseHTML parses a body into a DOM tree, or returns nil if it can't be parsed.
+func parseHTML(body []byte) *html.Node {
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil
+	}
+	return doc
+}
+
+// SelectorRules turns ad-hoc CSS selectors (--match-selector) into compiled
+// rules, one per selector, with IDs selector-1, selector-2, ...
+func SelectorRules(selectors []string) ([]Rule, error) {
+	var out []Rule
+	for i, s := range selectors {
+		out = append(out, Rule{
+			ID:        fmt.Sprintf("selector-%d", i+1),
+			Name:      "CSS selector " + s,
+			Selectors: []string{s},
+		})
+	}
+	if err := Compile(out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,84 @@
+package scanner
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/nxneeraj/hx-hawks/pkg/httpclient"
+)
+
+// ArtifactName returns the file name a target's response is stored under
+// by --store-responses: the hex SHA-256 of the target (virtual host mark
+// included, so each Host name gets its own file).
+func ArtifactName(target string) string {
+	sum := sha256.Sum256([]byte(target))
+	return hex.EncodeToString(sum[:]) + ".txt"
+}
+
+// StoreResponse writes the request and response headers and the raw body
+// of resp to dir/ArtifactName(target), overwriting an earlier copy, and
+// returns the file's path:
+//
+//	GET /admin HTTP/1.1
+//	Host: example.com
+//	User-Agent: ...
+//
+//	HTTP/1.1 200 OK
+//	Content-Type: text/html
+//
+//	<body>
+func StoreResponse(dir, target string, resp *httpclient.Response) (string, error) {
+	path := filepath.Join(dir, ArtifactName(target))
+	file, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	w := bufio.NewWriter(file)
+	if req := resp.Request; req != nil {
+		host := req.Host
+		if host == "" {
+			host = req.URL.Host
+		}
+		proto := req.Proto // As sent; HTTP/2 is only known from the response
+		if resp.Proto == "HTTP/2.0" {
+			proto = resp.Proto
+		}
+		fmt.Fprintf(w, "%s %s %s\r\n", req.Method, req.URL.RequestURI(), proto)
+		fmt.Fprintf(w, "Host: %s\r\n", host)
+		writeHeaders(w, req.Header)
+		w.WriteString("\r\n")
+	}
+	fmt.Fprintf(w, "%s %d %s\r\n", resp.Proto, resp.StatusCode, http.StatusText(resp.StatusCode))
+	writeHeaders(w, resp.Header)
+	w.WriteString("\r\n")
+	w.Write(resp.Body)
+
+	if err := w.Flush(); err != nil {
+		file.Close()
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// writeHeaders writes headers in wire format, sorted by name.
+func writeHeaders(w *bufio.Writer, header http.Header) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range header[name] {
+			fmt.Fprintf(w, "%s: %s\r\n", name, value)
+		}
+	}
+}
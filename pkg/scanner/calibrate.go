@@ -0,0 +1,156 @@
+package scanner
+
+import (
+	"context"
+	"log"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nxneeraj/hx-hawks/pkg/config"
+	"github.com/nxneeraj/hx-hawks/pkg/httpclient"
+)
+
+// Calibration holds the measurements of a calibration probe and the
+// settings recommended for the real scan.
+type Calibration struct {
+	Sampled    int
+	Errors     int
+	ErrorRate  float64
+	LatencyP50 time.Duration
+	LatencyP95 time.Duration
+
+	Threads int
+	Delay   time.Duration
+	Timeout time.Duration
+}
+
+// Calibrate probes a random sample of targets to measure latency and error
+// rates under the current network conditions, and derives recommended
+// thread, delay and timeout settings from them.
+func Calibrate(ctx context.Context, cfg *config.Config, urls []string, sampleSize int) Calibration {
+	sample := append([]string{}, urls...)
+	rand.Shuffle(len(sample), func(i, j int) { sample[i], sample[j] = sample[j], sample[i] })
+	if sampleSize > 0 && len(sample) > sampleSize {
+		sample = sample[:sampleSize]
+	}
+
+	client := httpclient.NewClient(cfg)
+	concurrency := cfg.Threads
+	if concurrency > len(sample) {
+		concurrency = len(sample)
+	}
+
+	var mu sync.Mutex
+	var latencies []time.Duration
+	errors := 0
+
+	work := make(chan string)
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			for u := range work {
+				reqCtx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+				resp, err := client.Fetch(reqCtx, u, nil)
+				cancel()
+				mu.Lock()
+				if err != nil || resp.StatusCode == 429 || resp.StatusCode >= 500 {
+					errors++
+				} else {
+					latencies = append(latencies, time.Duration(resp.Duration*float64(time.Second)))
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+feed:
+	for _, u := range sample {
+		select {
+		case work <- u:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	c := Calibration{Sampled: len(sample), Errors: errors}
+	if c.Sampled > 0 {
+		c.ErrorRate = float64(errors) / float64(c.Sampled)
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	c.LatencyP50 = percentile(latencies, 0.50)
+	c.LatencyP95 = percentile(latencies, 0.95)
+
+	// Timeout: generous headroom over the slow tail, never below 2s
+	c.Timeout = cfg.Timeout
+	if c.LatencyP95 > 0 {
+		secs := math.Ceil(c.LatencyP95.Seconds() * 3)
+		c.Timeout = time.Duration(math.Max(secs, 2)) * time.Second
+	}
+
+	// Threads/delay: back off when targets push back, scale up when they're
+	// slow but healthy (latency-bound scans benefit from more concurrency)
+	c.Threads, c.Delay = cfg.Threads, cfg.Delay
+	switch {
+	case c.ErrorRate > 0.20:
+		c.Threads = maxInt(1, cfg.Threads/2)
+		c.Delay = maxDuration(cfg.Delay, 200*time.Millisecond)
+	case c.ErrorRate > 0.05:
+		c.Delay = maxDuration(cfg.Delay, 50*time.Millisecond)
+	case c.LatencyP50 > time.Second:
+		c.Threads = minInt(cfg.Threads*2, 200)
+	}
+	return c
+}
+
+// Apply copies the recommended settings into cfg.
+func (c Calibration) Apply(cfg *config.Config) {
+	cfg.Threads = c.Threads
+	cfg.Delay = c.Delay
+	cfg.Timeout = c.Timeout
+}
+
+// Log prints the calibration measurements and recommendations.
+func (c Calibration) Log() {
+	log.Printf("[+] Calibration: sampled %d targets, %d errors (%.1f%%), latency p50=%s p95=%s",
+		c.Sampled, c.Errors, c.ErrorRate*100, c.LatencyP50.Round(time.Millisecond), c.LatencyP95.Round(time.Millisecond))
+	log.Printf("[+] Recommended settings: --threads %d --delay %d --timeout %d",
+		c.Threads, c.Delay.Milliseconds(), int(c.Timeout.Seconds()))
+}
+
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	return sorted[idx]
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxDuration(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+	return b
+}
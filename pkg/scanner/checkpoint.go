@@ -0,0 +1,157 @@
+package scanner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"time"
+
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+// checkpointVersion is bumped when the state file format changes.
+const checkpointVersion = 1
+
+// Checkpoint is the saved progress of a CLI scan (--resume). Rerunning the
+// same command with the same state file scans only the remaining URLs and
+// reports them together with the results kept from the earlier run.
+type Checkpoint struct {
+	Version   int                `json:"version"`
+	Saved     time.Time          `json:"saved"`
+	Inputs    *types.InputDigest `json:"inputs,omitempty"` // Targets/rules the state belongs to
+	Remaining []string           `json:"remaining"`        // Targets not scanned yet, in queue order
+	Results   []types.ScanResult `json:"results"`          // Results collected so far
+}
+
+// LoadCheckpoint reads the state file at path. A missing file yields nil.
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("state file %s: %w", path, err)
+	}
+	if cp.Version != checkpointVersion {
+		return nil, fmt.Errorf("state file %s has unsupported version %d", path, cp.Version)
+	}
+	return &cp, nil
+}
+
+// Save writes the checkpoint to path atomically, so a crash while saving
+// leaves the previous state intact.
+func (cp *Checkpoint) Save(path string) error {
+	cp.Version = checkpointVersion
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Resume prepares the scan for checkpointing to path (--resume). If path holds
+// the state of an earlier run with the same inputs, its results are kept and
+// the remaining targets are returned in place of urls.
+func (s *Scanner) Resume(path string, urls []string) ([]string, error) {
+	cp, err := LoadCheckpoint(path)
+	if err != nil {
+		return nil, err
+	}
+	if cp == nil {
+		log.Printf("[+] Saving progress to %s every %s", path, s.Config.CheckpointInterval)
+		return urls, nil
+	}
+	if s.Config.Inputs != nil && cp.Inputs != nil && *cp.Inputs != *s.Config.Inputs {
+		return nil, fmt.Errorf("state file %s belongs to a scan with different targets or rules; remove it or choose another --resume file", path)
+	}
+	s.ResultMutex.Lock()
+	s.Results = append(s.Results, cp.Results...)
+	s.ResultMutex.Unlock()
+	log.Printf("[+] Resuming from %s (saved %s): %d results kept, %d URLs remaining", path, cp.Saved.Format(time.RFC3339), len(cp.Results), len(cp.Remaining))
+	return cp.Remaining, nil
+}
+
+// saveCheckpoint writes the results so far and the targets without a result
+// to the state file.
+func (s *Scanner) saveCheckpoint(p *progress) error {
+	s.ResultMutex.Lock()
+	results := append([]types.ScanResult(nil), s.Results...)
+	s.ResultMutex.Unlock()
+	cp := &Checkpoint{Saved: time.Now().UTC(), Inputs: s.Config.Inputs, Remaining: p.Remaining(), Results: results}
+	return cp.Save(s.Config.ResumeFile)
+}
+
+// finishCheckpoint removes the state file of a finished scan, or saves the
+// final state of one that stopped early (interrupted or --max-time).
+func (s *Scanner) finishCheckpoint(p *progress) {
+	path := s.Config.ResumeFile
+	if len(p.pending) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			log.Printf("[!] Could not remove state file %s: %v", path, err)
+		}
+		return
+	}
+	if err := s.saveCheckpoint(p); err != nil {
+		log.Printf("[!] Error saving state file %s: %v", path, err)
+		return
+	}
+	log.Printf("[!] %d URLs not scanned; progress saved to %s, rerun with --resume %s to continue", len(p.Remaining()), path, path)
+}
+
+// progress tracks which queued targets have produced a result, for checkpoints.
+type progress struct {
+	queue   []string
+	pending map[string]int // Target -> results still expected (targets can repeat)
+}
+
+func newProgress(urls []string) *progress {
+	p := &progress{queue: urls, pending: make(map[string]int, len(urls))}
+	for _, u := range urls {
+		p.pending[u]++
+	}
+	return p
+}
+
+// Add records targets queued during the scan (--crawl).
+func (p *progress) Add(urls []string) {
+	p.queue = append(p.queue, urls...)
+	for _, u := range urls {
+		p.pending[u]++
+	}
+}
+
+// Done records a result for target.
+func (p *progress) Done(target string) {
+	if p.pending[target] > 1 {
+		p.pending[target]--
+	} else {
+		delete(p.pending, target)
+	}
+}
+
+// Remaining returns the targets without a result, in queue order.
+func (p *progress) Remaining() []string {
+	left := make(map[string]int, len(p.pending))
+	for u, n := range p.pending {
+		left[u] = n
+	}
+	remaining := make([]string, 0, len(p.pending))
+	for _, u := range p.queue {
+		if left[u] > 0 {
+			left[u]--
+			remaining = append(remaining, u)
+		}
+	}
+	return remaining
+}
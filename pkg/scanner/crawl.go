@@ -0,0 +1,169 @@
+package scanner
+
+import (
+	"bytes"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+	"golang.org/x/net/html"
+)
+
+// Crawl scopes for --crawl-scope.
+const (
+	CrawlScopeHost       = "host"       // Same host and port as the seed
+	CrawlScopeSubdomains = "subdomains" // The seed's host and its subdomains
+)
+
+// linkAttrs are the attributes that hold followable links, per element.
+var linkAttrs = map[string]string{
+	"a": "href", "area": "href", "link": "href", "iframe": "src", "frame": "src",
+	"script": "src", "form": "action",
+}
+
+// skipExtensions are static assets that never carry keyword leaks worth a request.
+var skipExtensions = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".svg": true, ".ico": true, ".webp": true,
+	".css": true, ".woff": true, ".woff2": true, ".ttf": true, ".eot": true,
+	".mp4": true, ".webm": true, ".mp3": true, ".pdf": true, ".zip": true,
+}
+
+// ExtractLinks returns the http(s) links of an HTML page, resolved against
+// pageURL (or the page's <base href>), without fragments, in document order.
+// Non-HTML content types yield nil.
+func ExtractLinks(pageURL, contentType string, body []byte) []string {
+	if contentType != "" && !strings.Contains(strings.ToLower(contentType), "html") {
+		return nil
+	}
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil
+	}
+	var links []string
+	seen := make(map[string]bool)
+	z := html.NewTokenizer(bytes.NewReader(body))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			return links
+		}
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			continue
+		}
+		name, hasAttr := z.TagName()
+		tag := string(name)
+		want, ok := linkAttrs[tag]
+		if tag == "base" {
+			want, ok = "href", true
+		}
+		for ok && hasAttr {
+			var key, val []byte
+			key, val, hasAttr = z.TagAttr()
+			if string(key) != want {
+				continue
+			}
+			ref, err := base.Parse(strings.TrimSpace(string(val)))
+			if err != nil {
+				break
+			}
+			if tag == "base" {
+				base = ref
+				break
+			}
+			if ref.Scheme != "http" && ref.Scheme != "https" || skipExtensions[strings.ToLower(path.Ext(ref.Path))] {
+				break
+			}
+			ref.Fragment, ref.RawFragment = "", ""
+			if link := ref.String(); !seen[link] {
+				seen[link] = true
+				links = append(links, link)
+			}
+			break
+		}
+	}
+}
+
+// Crawler decides which links found on scanned pages (--crawl) are queued:
+// in scope of the seed they descend from, not queued before, at most
+// MaxDepth clicks from the seed and at most MaxPages in total.
+type Crawler struct {
+	MaxDepth int
+	MaxPages int
+	Scope    string         // CrawlScopeHost or CrawlScopeSubdomains
+	Exclude  *regexp.Regexp // Links matching it are not followed (nil = none)
+
+	pages map[string]crawlPage // Every queued target, seeds included
+	added int
+}
+
+// crawlPage is a queued target's distance from its seed and the seed's host.
+type crawlPage struct {
+	depth int
+	root  string
+}
+
+// NewCrawler returns a crawler for the given seed targets.
+func NewCrawler(maxDepth, maxPages int, scope string, exclude *regexp.Regexp, seeds []string) *Crawler {
+	c := &Crawler{MaxDepth: maxDepth, MaxPages: maxPages, Scope: scope, Exclude: exclude, pages: make(map[string]crawlPage, len(seeds))}
+	for _, seed := range seeds {
+		target, _ := SplitVHost(seed)
+		if u, err := url.Parse(target); err == nil {
+			c.pages[seed] = crawlPage{root: u.Host}
+		}
+	}
+	return c
+}
+
+// Discover returns the links of result to queue next. Links of virtual-host
+// targets keep the virtual host. Not safe for concurrent use.
+func (c *Crawler) Discover(result types.ScanResult) []string {
+	page, ok := c.pages[result.Target]
+	if !ok || page.depth >= c.MaxDepth || len(result.Links) == 0 {
+		return nil
+	}
+	var next []string
+	for _, link := range result.Links {
+		if c.MaxPages > 0 && c.added >= c.MaxPages {
+			break
+		}
+		u, err := url.Parse(link)
+		if err != nil || !c.inScope(u.Host, page.root) || (c.Exclude != nil && c.Exclude.MatchString(link)) {
+			continue
+		}
+		if result.VHost != "" {
+			link = WithVHost(link, result.VHost)
+		}
+		if _, seen := c.pages[link]; seen {
+			continue
+		}
+		c.pages[link] = crawlPage{depth: page.depth + 1, root: page.root}
+		c.added++
+		next = append(next, link)
+	}
+	return next
+}
+
+// Added returns the number of links queued so far.
+func (c *Crawler) Added() int { return c.added }
+
+// inScope reports whether host may be crawled from a seed on root.
+func (c *Crawler) inScope(host, root string) bool {
+	host, root = strings.ToLower(host), strings.ToLower(root)
+	if host == root {
+		return true
+	}
+	if c.Scope != CrawlScopeSubdomains {
+		return false
+	}
+	return strings.HasSuffix(stripPort(host), "."+stripPort(root))
+}
+
+// stripPort removes a trailing :port from host.
+func stripPort(host string) string {
+	if i := strings.LastIndex(host, ":"); i >= 0 && !strings.Contains(host[i:], "]") {
+		return host[:i]
+	}
+	return host
+}
@@ -0,0 +1,39 @@
+package scanner
+
+import "sync"
+
+// ResponseIndex remembers the first URL seen for each body hash so identical
+// responses (e.g. CDN error pages) are only matched and stored once.
+// It is safe for concurrent use by all workers of a scan.
+type ResponseIndex struct {
+	mu   sync.Mutex
+	seen map[string]string // body SHA-256 -> first URL
+}
+
+// NewResponseIndex creates an empty index.
+func NewResponseIndex() *ResponseIndex {
+	return &ResponseIndex{seen: make(map[string]string)}
+}
+
+// Check records hash for url if it is new. If the hash was already seen it
+// returns the URL of the first response with that body and true.
+// A nil index never reports duplicates.
+func (ix *ResponseIndex) Check(hash, url string) (string, bool) {
+	if ix == nil || hash == "" {
+		return "", false
+	}
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	if first, ok := ix.seen[hash]; ok {
+		return first, true
+	}
+	ix.seen[hash] = url
+	return "", false
+}
+
+// Len returns the number of distinct bodies seen.
+func (ix *ResponseIndex) Len() int {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	return len(ix.seen)
+}
@@ -0,0 +1,29 @@
+package scanner
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/nxneeraj/hx-hawks/pkg/rules"
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+	"github.com/nxneeraj/hx-hawks/pkg/utils"
+)
+
+// Digest hashes the target list (as read, one URL per line) and the
+// keywords, their scope and rules (canonical JSON, in order) of a scan. An
+// unscoped scan hashes as it did before keyword scopes existed.
+func Digest(targets, keywords []string, scope rules.Scope, ruleSet []rules.Rule) *types.InputDigest {
+	var scopeData *rules.Scope
+	if !scope.IsZero() {
+		scopeData = &scope
+	}
+	ruleData, _ := json.Marshal(struct {
+		Keywords []string     `json:"keywords"`
+		Scope    *rules.Scope `json:"keyword_scope,omitempty"`
+		Rules    []rules.Rule `json:"rules"`
+	}{keywords, scopeData, ruleSet})
+	return &types.InputDigest{
+		TargetsSHA256: utils.SHA256Hex([]byte(strings.Join(targets, "\n") + "\n")),
+		RulesSHA256:   utils.SHA256Hex(ruleData),
+	}
+}
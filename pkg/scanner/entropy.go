@@ -0,0 +1,107 @@
+package scanner
+
+import (
+	"math"
+	"strings"
+
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+const (
+	minEntropyLen     = 20 // Shorter strings are too common to report
+	maxEntropyLen     = 256
+	maxEntropyResults = 10 // Candidates kept per response
+)
+
+// highEntropyRule is reported when --entropy finds possible tokens or keys.
+var highEntropyRule = types.RuleMatch{
+	ID:          "high-entropy-string",
+	Name:        "High-entropy string (possible token or key)",
+	Severity:    "low",
+	Confidence:  "low", // Random-looking strings are leads; most are IDs or hashes
+	Remediation: "Check whether the strings in high_entropy are live credentials; if so, remove them from the response and rotate them.",
+}
+
+// sriPrefixes start Subresource Integrity hashes, which are random by design.
+var sriPrefixes = []string{"sha256-", "sha384-", "sha512-"}
+
+// FindHighEntropy returns up to maxEntropyResults distinct strings of
+// base64/hex/URL-safe characters whose Shannon entropy (bits per character)
+// reaches threshold. Hex-only strings, which can't exceed 4 bits, are held
+// to two thirds of threshold.
+func FindHighEntropy(body []byte, threshold float64) []string {
+	var found []string
+	seen := make(map[string]bool)
+	start := -1
+	for i := 0; i <= len(body); i++ {
+		if i < len(body) && isTokenByte(body[i]) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start < 0 {
+			continue
+		}
+		token := string(body[start:i])
+		start = -1
+		if len(token) < minEntropyLen || len(token) > maxEntropyLen || seen[token] || isSRIHash(token) {
+			continue
+		}
+		limit := threshold
+		if isHex(token) {
+			limit = threshold * 2 / 3
+		}
+		if shannonEntropy(token) >= limit {
+			seen[token] = true
+			found = append(found, token)
+			if len(found) == maxEntropyResults {
+				break
+			}
+		}
+	}
+	return found
+}
+
+// shannonEntropy returns the entropy of s in bits per character.
+func shannonEntropy(s string) float64 {
+	var counts [256]int
+	for i := 0; i < len(s); i++ {
+		counts[s[i]]++
+	}
+	entropy := 0.0
+	for _, n := range counts {
+		if n > 0 {
+			p := float64(n) / float64(len(s))
+			entropy -= p * math.Log2(p)
+		}
+	}
+	return entropy
+}
+
+// isTokenByte reports whether c can be part of a base64, base64url or hex token.
+func isTokenByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		c == '+' || c == '/' || c == '=' || c == '-' || c == '_'
+}
+
+// isHex reports whether s consists of hex digits only.
+func isHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
+
+// isSRIHash reports whether token is a Subresource Integrity hash.
+func isSRIHash(token string) bool {
+	for _, prefix := range sriPrefixes {
+		if strings.HasPrefix(token, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package scanner
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+)
+
+// Failure classes of a fetch. Errors recorded by the workers are
+// *FetchError values wrapping one of these, so errors.Is works on them.
+var (
+	ErrTimeout      = errors.New("timeout")
+	ErrDNS          = errors.New("dns lookup failed")
+	ErrRefused      = errors.New("connection refused")
+	ErrTLS          = errors.New("tls failure")
+	ErrNetwork      = errors.New("network error")                                 // Resets, EOFs and other transport failures
+	ErrStallAborted = errors.New("aborted: no progress before the stall timeout") // Cancelled by --stall-abort
+)
+
+// errorClasses maps each class to the name recorded in ScanResult.ErrorClass.
+var errorClasses = []struct {
+	err  error
+	name string
+}{
+	{ErrStallAborted, "aborted"},
+	{ErrDNS, "dns"},
+	{ErrRefused, "refused"},
+	{ErrTLS, "tls"},
+	{ErrTimeout, "timeout"},
+	{ErrNetwork, "network"},
+}
+
+// FetchError is a failed request tagged with its failure class. Its message
+// is that of the underlying error.
+type FetchError struct {
+	URL   string
+	Class error // ErrTimeout, ErrDNS, ErrRefused, ErrTLS, ErrNetwork or ErrStallAborted
+	Err   error
+}
+
+func (e *FetchError) Error() string { return e.Err.Error() }
+
+func (e *FetchError) Unwrap() []error { return []error{e.Class, e.Err} }
+
+// ErrorClass returns the short class name of a fetch error ("timeout",
+// "dns", "refused", "tls", "network", "aborted"), or "" if unclassified.
+func ErrorClass(err error) string {
+	for _, c := range errorClasses {
+		if errors.Is(err, c.err) {
+			return c.name
+		}
+	}
+	return ""
+}
+
+// classifyError wraps a fetch error in a *FetchError when it belongs to a
+// known class; other errors are returned unchanged.
+func classifyError(urlStr string, err error) error {
+	var (
+		dnsErr     *net.DNSError
+		netErr     net.Error
+		verifyErr  *tls.CertificateVerificationError
+		authErr    x509.UnknownAuthorityError
+		hostErr    x509.HostnameError
+		invalidErr x509.CertificateInvalidError
+		recordErr  tls.RecordHeaderError
+	)
+	var class error
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, ErrStallAborted):
+		class = ErrStallAborted
+	case errors.As(err, &dnsErr):
+		class = ErrDNS
+	case errors.Is(err, syscall.ECONNREFUSED):
+		class = ErrRefused
+	case errors.As(err, &verifyErr), errors.As(err, &authErr), errors.As(err, &hostErr),
+		errors.As(err, &invalidErr), errors.As(err, &recordErr), strings.Contains(err.Error(), "tls: "):
+		class = ErrTLS
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		class = ErrTimeout
+	case errors.Is(err, syscall.ECONNRESET), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF), errors.As(err, &netErr):
+		class = ErrNetwork
+	default:
+		return err
+	}
+	return &FetchError{URL: urlStr, Class: class, Err: err}
+}
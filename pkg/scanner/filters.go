@@ -0,0 +1,43 @@
+package scanner
+
+import "github.com/nxneeraj/hx-hawks/pkg/config"
+
+// statusFiltered reports whether a status code is discarded by --filter-code.
+func statusFiltered(cfg *config.Config, code int) bool {
+	return containsCode(cfg.FilterCodes, code)
+}
+
+// statusMatched reports whether a status code satisfies --match-code.
+// An empty match list accepts every status code.
+func statusMatched(cfg *config.Config, code int) bool {
+	return len(cfg.MatchCodes) == 0 || containsCode(cfg.MatchCodes, code)
+}
+
+// sizeFiltered reports whether a body size is discarded by --filter-size.
+func sizeFiltered(cfg *config.Config, size int64) bool {
+	return inSizeRanges(cfg.FilterSizes, size)
+}
+
+// sizeMatched reports whether a body size satisfies --match-size.
+// An empty match list accepts every size.
+func sizeMatched(cfg *config.Config, size int64) bool {
+	return len(cfg.MatchSizes) == 0 || inSizeRanges(cfg.MatchSizes, size)
+}
+
+func inSizeRanges(ranges []config.SizeRange, size int64) bool {
+	for _, r := range ranges {
+		if r.Contains(size) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsCode(codes []int, code int) bool {
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package scanner
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+// StatusKey returns the histogram bucket of a result: its status code, or
+// "error" if the request failed.
+func StatusKey(r types.ScanResult) string {
+	if r.Error != "" || r.StatusCode == 0 {
+		return "error"
+	}
+	return strconv.Itoa(r.StatusCode)
+}
+
+// FormatStatusCounts renders a histogram as "200=12 301=3 404=80 error=2",
+// ordered by status code with errors last.
+func FormatStatusCounts(counts map[string]int) string {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, errA := strconv.Atoi(keys[i])
+		b, errB := strconv.Atoi(keys[j])
+		if errA != nil || errB != nil {
+			return errA == nil // Numeric codes before "error"
+		}
+		return a < b
+	})
+
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s=%d", k, counts[k])
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,56 @@
+package scanner
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+// lowConfidence reports whether every match of a result is a low-confidence
+// rule (no keywords, no other rules), which makes it a lead, not a finding.
+func lowConfidence(keywords []string, hits []types.RuleMatch) bool {
+	if len(keywords) > 0 || len(hits) == 0 {
+		return false
+	}
+	for _, hit := range hits {
+		if hit.Confidence != "low" {
+			return false
+		}
+	}
+	return true
+}
+
+// interestingReasons explains why a result that is neither vulnerable nor
+// failed still deserves a manual look, or returns nil. Leads are
+// low-confidence rule matches, auth-protected (401/403) or failing (5xx)
+// responses, WAF/rate-limit blocks and findings suppressed on login pages.
+func interestingReasons(result *types.ScanResult) []string {
+	if result.IsVulnerable || result.Error != "" || result.Duplicate || result.Unchanged {
+		return nil
+	}
+	var reasons []string
+	if len(result.MatchedRules) > 0 {
+		ids := make([]string, 0, len(result.MatchedRules))
+		for _, rule := range result.MatchedRules {
+			ids = append(ids, rule.ID)
+		}
+		reason := "low-confidence match: " + strings.Join(ids, ", ")
+		if result.LoginRedirect {
+			reason = "match on login page: " + strings.Join(ids, ", ")
+		}
+		reasons = append(reasons, reason)
+	} else if len(result.MatchedKeywords) > 0 && result.LoginRedirect {
+		reasons = append(reasons, "match on login page: "+strings.Join(result.MatchedKeywords, ", "))
+	}
+	switch code := result.StatusCode; {
+	case code == 401 || code == 403:
+		reasons = append(reasons, fmt.Sprintf("access denied (%d)", code))
+	case code >= 500:
+		reasons = append(reasons, fmt.Sprintf("server error (%d)", code))
+	}
+	if result.Blocked != "" {
+		reasons = append(reasons, "blocked: "+result.Blocked)
+	}
+	return reasons
+}
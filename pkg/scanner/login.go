@@ -0,0 +1,82 @@
+package scanner
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+// loginPath matches URL paths of typical login and SSO pages.
+var loginPath = regexp.MustCompile(`(?i)(^|/)(log-?in|logon|sign-?in|sign_in|sso|saml2?|oauth2?|openid(-connect)?|authorize|adfs|cas|auth|wp-login\.php)(/|\.|$)`)
+
+// passwordInput matches a password field in an HTML form.
+var passwordInput = regexp.MustCompile(`(?i)<input[^>]+type\s*=\s*["']?password`)
+
+// Identity providers and conventional login host names.
+var (
+	loginHosts        = []string{"login.microsoftonline.com", "accounts.google.com", "login.live.com"}
+	loginHostPrefixes = []string{"login.", "signin.", "sso.", "auth.", "idp.", "adfs.", "accounts."}
+	loginHostSuffixes = []string{".okta.com", ".oktapreview.com", ".auth0.com", ".onelogin.com", ".pingidentity.com", ".b2clogin.com"}
+)
+
+// isLoginRedirect reports whether a response was reached by redirecting
+// away from the requested page to a login/SSO page, in which case findings
+// in body describe the login page rather than the target.
+func isLoginRedirect(result *types.ScanResult, body []byte) bool {
+	if len(result.RedirectChain) == 0 {
+		return false
+	}
+	from, err1 := url.Parse(result.RedirectChain[0].URL)
+	to, err2 := url.Parse(result.URL)
+	if err1 != nil || err2 != nil || looksLikeLogin(from) {
+		return false // Asked for the login page itself
+	}
+	if looksLikeLogin(to) {
+		return true
+	}
+	// Unrecognized URL, but the page we landed on elsewhere asks for a password
+	return (to.Host != from.Host || to.Path != from.Path) && passwordInput.Match(body)
+}
+
+// looksLikeLogin reports whether u is a conventional login or SSO URL.
+func looksLikeLogin(u *url.URL) bool {
+	if loginPath.MatchString(u.Path) {
+		return true
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, h := range loginHosts {
+		if host == h {
+			return true
+		}
+	}
+	for _, p := range loginHostPrefixes {
+		if strings.HasPrefix(host, p) {
+			return true
+		}
+	}
+	for _, s := range loginHostSuffixes {
+		if strings.HasSuffix(host, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyLoginRedirect marks a vulnerable result whose evidence comes from a
+// login page: "downgrade" lowers its rule and keyword matches to info,
+// "suppress" also clears IsVulnerable. The matches are kept for review
+// either way.
+func applyLoginRedirect(mode string, result *types.ScanResult) {
+	result.LoginRedirect = true
+	for i := range result.MatchedRules {
+		result.MatchedRules[i].Severity = "info"
+	}
+	for k := range result.KeywordSeverity {
+		result.KeywordSeverity[k] = "info"
+	}
+	if mode == "suppress" {
+		result.IsVulnerable = false
+	}
+}
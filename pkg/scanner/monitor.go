@@ -0,0 +1,151 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+)
+
+// activeRequest is the request a worker is currently busy with.
+type activeRequest struct {
+	url     string
+	host    string
+	started time.Time
+	cancel  context.CancelFunc
+}
+
+// Monitor tracks what every worker is doing so long scans can log
+// heartbeats and spot stalls (workers alive but no results for a while).
+// It is safe for concurrent use; a nil Monitor does nothing.
+type Monitor struct {
+	mu         sync.Mutex
+	active     map[int]*activeRequest // Worker ID -> in-flight request
+	done       int                    // Requests finished
+	lastResult time.Time
+	stalled    bool // A stall was reported and no result has arrived since
+}
+
+// NewMonitor creates a monitor for a scan starting now.
+func NewMonitor() *Monitor {
+	return &Monitor{active: make(map[int]*activeRequest), lastResult: time.Now()}
+}
+
+// Begin records that worker id started fetching urlStr and returns a context
+// for the request that Abort can cancel.
+func (m *Monitor) Begin(ctx context.Context, id int, urlStr string) context.Context {
+	if m == nil {
+		return ctx
+	}
+	reqCtx, cancel := context.WithCancel(ctx)
+	host := urlStr
+	if u, err := url.Parse(urlStr); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	m.mu.Lock()
+	m.active[id] = &activeRequest{url: urlStr, host: host, started: time.Now(), cancel: cancel}
+	m.mu.Unlock()
+	return reqCtx
+}
+
+// End records that worker id finished its request.
+func (m *Monitor) End(id int) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if req, ok := m.active[id]; ok {
+		req.cancel()
+		delete(m.active, id)
+	}
+	m.done++
+	m.lastResult = time.Now()
+	m.stalled = false
+}
+
+// Abort cancels the in-flight requests to the given hosts and returns how
+// many were cancelled. The workers record them as errors and move on.
+func (m *Monitor) Abort(hosts []string) int {
+	if m == nil {
+		return 0
+	}
+	abort := make(map[string]bool, len(hosts))
+	for _, h := range hosts {
+		abort[h] = true
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := 0
+	for _, req := range m.active {
+		if abort[req.host] {
+			req.cancel()
+			n++
+		}
+	}
+	return n
+}
+
+// Run logs a heartbeat every interval until ctx is done. If no request has
+// finished for stallAfter (> 0) while workers are busy, it logs each busy
+// worker's request and, with abort set, cancels the requests to those hosts.
+func (m *Monitor) Run(ctx context.Context, logger *log.Logger, interval, stallAfter time.Duration, abort bool) {
+	if m == nil || interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		m.mu.Lock()
+		busy := make([]int, 0, len(m.active))
+		for id := range m.active {
+			busy = append(busy, id)
+		}
+		sort.Ints(busy)
+		idle := time.Since(m.lastResult)
+		done := m.done
+		report := stallAfter > 0 && len(busy) > 0 && idle >= stallAfter && !m.stalled
+		var lines []string
+		var hosts []string
+		if report {
+			m.stalled = true
+			seenHost := make(map[string]bool)
+			for _, id := range busy {
+				req := m.active[id]
+				lines = append(lines, formatActive(id, req))
+				if !seenHost[req.host] {
+					seenHost[req.host] = true
+					hosts = append(hosts, req.host)
+				}
+			}
+		}
+		m.mu.Unlock()
+
+		logger.Printf("[i] Heartbeat: %d requests done, %d workers busy, last result %s ago", done, len(busy), idle.Round(time.Second))
+		if !report {
+			continue
+		}
+		logger.Printf("[!] Scan looks stalled: no results for %s while %d workers are busy", idle.Round(time.Second), len(busy))
+		for _, line := range lines {
+			logger.Printf("[!]   %s", line)
+		}
+		if abort {
+			n := m.Abort(hosts)
+			logger.Printf("[!] Aborted %d in-flight requests to stalled hosts: %v", n, hosts)
+		}
+	}
+}
+
+// formatActive describes a worker's in-flight request for stall diagnostics.
+func formatActive(id int, req *activeRequest) string {
+	return fmt.Sprintf("Worker %d: %s (running %s)", id, req.url, time.Since(req.started).Round(time.Second))
+}
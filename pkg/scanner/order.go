@@ -0,0 +1,57 @@
+package scanner
+
+import (
+	"math/rand"
+	"net/url"
+	"strings"
+)
+
+// Shuffle returns targets in random order (--shuffle), so input grouped by
+// domain doesn't send long runs of requests to one host.
+func Shuffle(targets []string) []string {
+	out := append([]string(nil), targets...)
+	rand.Shuffle(len(out), func(i, j int) { out[i], out[j] = out[j], out[i] })
+	return out
+}
+
+// InterleaveByHost reorders targets round-robin across hosts (--interleave):
+// the first target of every host, then the second of every host, and so on.
+// Hosts keep the order they first appear in and each host's targets keep
+// their relative order, so consecutive requests go to different hosts as
+// long as there are several left.
+func InterleaveByHost(targets []string) []string {
+	var hosts []string
+	groups := make(map[string][]string)
+	for _, target := range targets {
+		host := targetHost(target)
+		if _, ok := groups[host]; !ok {
+			hosts = append(hosts, host)
+		}
+		groups[host] = append(groups[host], target)
+	}
+	if len(hosts) < 2 {
+		return targets
+	}
+
+	out := make([]string, 0, len(targets))
+	for round := 0; len(out) < len(targets); round++ {
+		for _, host := range hosts {
+			if round < len(groups[host]) {
+				out = append(out, groups[host][round])
+			}
+		}
+	}
+	return out
+}
+
+// targetHost returns the lowercase host a target connects to (its virtual
+// host doesn't matter, the server is the same), or the target itself when it
+// doesn't parse.
+func targetHost(target string) string {
+	plain, _ := SplitVHost(target)
+	u, err := url.Parse(plain)
+	if err != nil || u.Host == "" {
+		return target
+	}
+	return strings.ToLower(u.Hostname())
+}
@@ -0,0 +1,37 @@
+package scanner
+
+import (
+	"net/url"
+
+	"github.com/nxneeraj/hx-hawks/pkg/config"
+	"github.com/nxneeraj/hx-hawks/pkg/types"
+)
+
+// pinMismatchRule is reported when a host presents an unexpected certificate.
+var pinMismatchRule = types.RuleMatch{
+	ID:          "cert-pin-mismatch",
+	Name:        "TLS certificate does not match pinned fingerprint",
+	Severity:    "high",
+	Remediation: "Verify whether the certificate was legitimately rotated (then update the pin) or whether traffic is being intercepted.",
+}
+
+// checkPin compares the result's certificate with any pin configured for its
+// host. On a mismatch the result is flagged vulnerable and true is returned.
+func checkPin(cfg *config.Config, result *types.ScanResult) bool {
+	if len(cfg.CertPins) == 0 {
+		return false
+	}
+	u, err := url.Parse(result.URL)
+	if err != nil || u.Scheme != "https" {
+		return false
+	}
+	pin := config.FindPin(cfg.CertPins, u.Hostname())
+	if pin == nil || pin.Accepts(result.CertSHA256) {
+		return false
+	}
+
+	result.PinMismatch = true
+	result.IsVulnerable = true
+	result.MatchedRules = append(result.MatchedRules, pinMismatchRule)
+	return true
+}
@@ -0,0 +1,102 @@
+package scanner
+
+import (
+	"context"
+	"crypto/tls"
+	"log"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/nxneeraj/hx-hawks/pkg/config"
+	"github.com/nxneeraj/hx-hawks/pkg/utils"
+)
+
+// ProbeSchemes turns bare hosts (utils.IsBareHost) into URLs: https:// when
+// the host completes a TLS handshake, else http:// when it accepts a plain
+// connection. Hosts that answer neither keep https:// so the scan records
+// the error. Targets with a scheme are returned unchanged, in order.
+func ProbeSchemes(ctx context.Context, cfg *config.Config, targets []string) []string {
+	out := make([]string, len(targets))
+	copy(out, targets)
+	var bare []int
+	for i, target := range targets {
+		if utils.IsBareHost(target) {
+			bare = append(bare, i)
+		}
+	}
+	if len(bare) == 0 {
+		return out
+	}
+
+	timeout := cfg.ConnectTimeout
+	if timeout <= 0 || timeout > cfg.Timeout {
+		timeout = cfg.Timeout
+	}
+	var mu sync.Mutex
+	counts := make(map[string]int)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < cfg.Threads && w < len(bare); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				scheme := probeScheme(ctx, targets[i], timeout)
+				mu.Lock()
+				counts[scheme]++
+				mu.Unlock()
+				if scheme == "" {
+					scheme = "https"
+				}
+				out[i] = scheme + "://" + targets[i]
+			}
+		}()
+	}
+	for _, i := range bare {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	log.Printf("[+] Probed %d bare hosts: %d https, %d http, %d unreachable (scanned as https)", len(bare), counts["https"], counts["http"], counts[""])
+	return out
+}
+
+// probeScheme returns "https" if target's host completes a TLS handshake,
+// "http" if it accepts a TCP connection on the plain port, or "".
+// Explicit ports are tried with both schemes.
+func probeScheme(ctx context.Context, target string, timeout time.Duration) string {
+	u, err := url.Parse("//" + target)
+	if err != nil {
+		return ""
+	}
+	host, port := u.Hostname(), u.Port()
+	tlsPort, plainPort := "443", "80"
+	if port != "" {
+		tlsPort, plainPort = port, port
+	}
+
+	if conn, err := dialProbe(ctx, net.JoinHostPort(host, tlsPort), timeout); err == nil {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: host, InsecureSkipVerify: true})
+		tlsConn.SetDeadline(time.Now().Add(timeout))
+		err = tlsConn.Handshake()
+		tlsConn.Close()
+		if err == nil {
+			return "https"
+		}
+	}
+	if conn, err := dialProbe(ctx, net.JoinHostPort(host, plainPort), timeout); err == nil {
+		conn.Close()
+		return "http"
+	}
+	return ""
+}
+
+// dialProbe connects to addr like the scan itself (honouring --resolve and
+// --resolvers), giving up after timeout.
+func dialProbe(ctx context.Context, addr string, timeout time.Duration) (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return utils.DialContext(ctx, "tcp", addr)
+}
@@ -0,0 +1,119 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrQueueClosed is returned when adding URLs to a queue that has been drained.
+var ErrQueueClosed = errors.New("queue is closed")
+
+// Queue is a URL queue that can grow while a scan is running. Feed drains it
+// into the workers' channel; once it is empty and every handed-out URL has
+// been acknowledged (see Ack) the queue closes and further additions are
+// rejected.
+type Queue struct {
+	mu       sync.Mutex
+	items    []string
+	inflight int // URLs sent to workers but not yet acknowledged
+	closed   bool
+	wake     chan struct{} // Signalled by Add and Ack
+}
+
+// NewQueue creates a queue holding the initial URLs.
+func NewQueue(urls []string) *Queue {
+	items := make([]string, len(urls))
+	copy(items, urls)
+	return &Queue{items: items, wake: make(chan struct{}, 1)}
+}
+
+// signal wakes a waiting Feed without blocking.
+func (q *Queue) signal() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Add appends URLs to the queue. It fails with ErrQueueClosed once the queue
+// has been drained or the scan was cancelled.
+func (q *Queue) Add(urls []string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return ErrQueueClosed
+	}
+	q.items = append(q.items, urls...)
+	q.signal()
+	return nil
+}
+
+// Ack marks one handed-out URL as processed (one per worker result).
+func (q *Queue) Ack() {
+	q.mu.Lock()
+	if q.inflight > 0 {
+		q.inflight--
+	}
+	q.mu.Unlock()
+	q.signal()
+}
+
+// Len returns the number of URLs still waiting to be handed to a worker.
+func (q *Queue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items)
+}
+
+// Closed reports whether the queue no longer accepts URLs.
+func (q *Queue) Closed() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.closed
+}
+
+// Feed sends queued URLs (including ones added while feeding) to out until
+// the queue is empty with nothing in flight, or ctx is cancelled, then closes
+// the queue and out. It returns false if ctx was cancelled.
+func (q *Queue) Feed(ctx context.Context, out chan<- string) bool {
+	defer close(out)
+	for {
+		q.mu.Lock()
+		if len(q.items) == 0 {
+			if q.inflight == 0 {
+				q.closed = true
+				q.mu.Unlock()
+				return true
+			}
+			q.mu.Unlock()
+			// Wait for new URLs or for in-flight ones to finish
+			select {
+			case <-q.wake:
+				continue
+			case <-ctx.Done():
+				q.close()
+				return false
+			}
+		}
+		next := q.items[0]
+		q.items[0] = ""
+		q.items = q.items[1:]
+		q.inflight++
+		q.mu.Unlock()
+
+		select {
+		case out <- next:
+		case <-ctx.Done():
+			q.close()
+			return false
+		}
+	}
+}
+
+// close stops the queue from accepting URLs.
+func (q *Queue) close() {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+}
@@ -11,7 +11,9 @@ import (
 	
 	"github.com/nxneeraj/hx-hawks/pkg/config"
 	"github.com/nxneeraj/hx-hawks/pkg/httpclient"
+	"github.com/nxneeraj/hx-hawks/pkg/notify"
 	"github.com/nxneeraj/hx-hawks/pkg/output"
+	"github.com/nxneeraj/hx-hawks/pkg/rules"
 	"github.com/nxneeraj/hx-hawks/pkg/types"
 )
 
@@ -25,7 +27,16 @@ type Scanner struct {
 
 // NewScanner creates a new Scanner instance.
 func NewScanner(cfg *config.Config) *Scanner {
-	client := httpclient.NewClient(cfg.Timeout)
+	client := httpclient.NewClient(cfg)
+	if cfg.CacheFile != "" {
+		cache, err := httpclient.LoadCache(cfg.CacheFile)
+		if err != nil {
+			log.Printf("[!] Could not load response cache %s, starting empty: %v", cfg.CacheFile, err)
+		}
+		// Findings cached under other keywords or rules don't apply to this run
+		cache.Scope = Digest(nil, cfg.Keywords, cfg.KeywordScope, cfg.Rules).RulesSHA256
+		client.Cache = cache
+	}
 	return &Scanner{
 		Config:  cfg,
 		Client:  client,
@@ -33,12 +44,17 @@ func NewScanner(cfg *config.Config) *Scanner {
 	}
 }
 
-// Run starts the scanning process for the given URLs.
-func (s *Scanner) Run(urls []string) []types.ScanResult {
+// Run starts the scanning process for the given URLs. Cancelling ctx stops
+// the scan; the results collected until then are still returned and written
+// to the output files.
+func (s *Scanner) Run(ctx context.Context, urls []string) []types.ScanResult {
 	startTime := time.Now()
 	log.Printf("[+] Starting Hx-H.A.W.K.S scan at %s", startTime.Format(time.RFC3339))
 	log.Printf("[+] Target URLs: %d", len(urls))
 	log.Printf("[+] Keywords: %s", strings.Join(s.Config.Keywords, ", "))
+	if len(s.Config.Rules) > 0 {
+		log.Printf("[+] Rules: %d", len(s.Config.Rules))
+	}
 	log.Printf("[+] Concurrency (Threads): %d", s.Config.Threads)
 	log.Printf("[+] Timeout per request: %s", s.Config.Timeout)
 	if s.Config.Delay > 0 {
@@ -47,6 +63,15 @@ func (s *Scanner) Run(urls []string) []types.ScanResult {
 	if s.Config.ScanDuration > 0 {
 		log.Printf("[+] Max Scan Duration: %s", s.Config.ScanDuration)
 	}
+	if len(s.Config.MatchCodes) > 0 {
+		log.Printf("[+] Match status codes: %v", s.Config.MatchCodes)
+	}
+	if len(s.Config.FilterCodes) > 0 {
+		log.Printf("[+] Filter status codes: %v", s.Config.FilterCodes)
+	}
+	if s.Config.Crawl {
+		log.Printf("[+] Crawl: depth %d, scope %s, page cap %d", s.Config.CrawlDepth, s.Config.CrawlScope, s.Config.CrawlMaxPages)
+	}
 
 	urlChan := make(chan string, s.Config.Threads)              // Buffered channel
 	resultChan := make(chan types.ScanResult, s.Config.Threads) // Buffered channel for results
@@ -56,49 +81,107 @@ func (s *Scanner) Run(urls []string) []types.ScanResult {
 	var scanCtx context.Context
 	var cancel context.CancelFunc
 	if s.Config.ScanDuration > 0 {
-		scanCtx, cancel = context.WithTimeout(context.Background(), s.Config.ScanDuration)
+		scanCtx, cancel = context.WithTimeout(ctx, s.Config.ScanDuration)
 	} else {
-		scanCtx, cancel = context.WithCancel(context.Background())
+		scanCtx, cancel = context.WithCancel(ctx)
 	}
 	defer cancel() // Ensure cancellation propagates
 
+	// Build the keyword automaton and rules once; all workers share them
+	engine := rules.NewEngine(s.Config.Keywords, s.Config.Rules)
+	engine.KeywordScope = s.Config.KeywordScope
+
+	// Shared body-hash index for --dedupe-responses
+	var seen *ResponseIndex
+	if s.Config.DedupeResponses {
+		seen = NewResponseIndex()
+	}
+
+	// Heartbeats and stall detection for long scans
+	monitor := NewMonitor()
+	monitorCtx, stopMonitor := context.WithCancel(scanCtx)
+	defer stopMonitor()
+	go monitor.Run(monitorCtx, log.Default(), s.Config.Heartbeat, s.Config.StallTimeout, s.Config.StallAbort)
+
+	// Where worker time goes (network, matching, result hand-off, delay)
+	util := NewUtilization()
+
 	// Start workers
 	wg.Add(s.Config.Threads) // Add count for all workers before starting them
 	for i := 0; i < s.Config.Threads; i++ {
 		go func(workerID int) {
 			defer wg.Done() // Signal WaitGroup when worker goroutine finishes
-			// Pass scanCtx, workerID, client, keywords, delay, channels, verbose
-			Worker(scanCtx, workerID, s.Client, s.Config.Keywords, s.Config.Delay, urlChan, resultChan, s.Config.Verbose)
+			// Pass scanCtx, workerID, client, config, channels
+			Worker(scanCtx, workerID, s.Client, s.Config, engine, seen, monitor, util, urlChan, resultChan)
 		}(i + 1)
 	}
 
 	// Feed URLs to workers in a separate goroutine
-	// This prevents blocking if urlChan fills up
+	// This prevents blocking if urlChan fills up. The queue grows with the
+	// links found by --crawl and closes urlChan once nothing is left.
+	queue := NewQueue(urls)
+	var crawler *Crawler
+	if s.Config.Crawl {
+		crawler = NewCrawler(s.Config.CrawlDepth, s.Config.CrawlMaxPages, s.Config.CrawlScope, s.Config.CrawlExclude, urls)
+	}
 	go func() {
-	feedLoop:
-		for _, url := range urls {
-			select {
-			case urlChan <- url:
-				// URL sent to a worker
-			case <-scanCtx.Done():
-				log.Println("[!] Scan duration reached or cancelled, stopping URL feed.")
-				break feedLoop // Exit loop if context is cancelled
-			}
+		if !queue.Feed(scanCtx, urlChan) {
+			log.Println("[!] Scan duration reached or cancelled, stopping URL feed.")
 		}
-		close(urlChan) // Close channel once all URLs are sent (signals workers no more input)
 		log.Println("[+] Finished feeding URLs to workers.")
 	}()
 
 	// Collect results in a separate goroutine
 	// This allows processing while workers are still running
 	var collectorWg sync.WaitGroup
+	numFiltered := 0
+	statusCounts := make(map[string]int)
+	// Targets without a result yet, saved periodically for --resume
+	var prog *progress
+	var checkpointC <-chan time.Time
+	if s.Config.ResumeFile != "" {
+		prog = newProgress(urls)
+		if s.Config.CheckpointInterval > 0 {
+			checkpointTicker := time.NewTicker(s.Config.CheckpointInterval)
+			defer checkpointTicker.Stop()
+			checkpointC = checkpointTicker.C
+		}
+	}
+	// Findings go to the --notify-* providers the moment they are collected
+	notifier := notify.NewDispatcher(s.Config.Notifiers, s.Config.NotifyEvents, s.Config.NotifyBatch, s.Config.NotifyInterval)
+	s.ResultMutex.Lock()
+	resumed := len(s.Results) // Results kept from an earlier run (--resume)
+	s.ResultMutex.Unlock()
+	// Results go to -o-jsonl as they are collected, resumed ones first
+	var jsonl *output.JSONLWriter
+	if s.Config.OutputJSONL != "" {
+		var err error
+		if jsonl, err = output.CreateJSONL(s.Config.OutputJSONL, s.Config.Fields, s.Config.Append); err != nil {
+			log.Printf("[!] Failed to create JSONL output %s: %v", s.Config.OutputJSONL, err)
+		}
+		for _, r := range s.Results[:resumed] {
+			if err := jsonl.Write(r); err != nil {
+				log.Printf("[!] Failed to write JSONL output %s: %v", s.Config.OutputJSONL, err)
+				break
+			}
+		}
+	}
+	// And to --es-url; resumed ones were indexed by the run that found them
+	var es *output.ESIndexer
+	if s.Config.ESURL != "" {
+		var err error
+		if es, err = output.NewESIndexer(s.Config.ESURL, s.Config.ESIndex, s.Config.Fields); err != nil {
+			log.Printf("[!] Failed to start Elasticsearch indexing: %v", err)
+		}
+	}
 	collectorWg.Add(1)
 	go func() {
 		defer collectorWg.Done()
 		processedCount := 0
-		totalURLs := len(urls)
+		totalURLs := resumed + len(urls)
 		progressTicker := time.NewTicker(5 * time.Second) // Update progress periodically
 		defer progressTicker.Stop()
+		cancelled := scanCtx.Done()
 
 	collectLoop:
 		for {
@@ -110,9 +193,36 @@ func (s *Scanner) Run(urls []string) []types.ScanResult {
 					break collectLoop // Exit collection loop
 				}
 
+				statusCounts[StatusKey(result)]++ // Includes filtered responses
+				if crawler != nil {
+					// Queue links before the ack, so the queue never looks drained in between
+					if links := crawler.Discover(result); len(links) > 0 {
+						queue.Add(links)
+						totalURLs += len(links)
+						if prog != nil {
+							prog.Add(links)
+						}
+					}
+				}
+				queue.Ack()
+				if prog != nil {
+					prog.Done(result.Target)
+				}
+				if result.Filtered {
+					numFiltered++ // Dropped by --filter-code/--filter-size, not stored
+					continue
+				}
+
 				s.ResultMutex.Lock()
 				s.Results = append(s.Results, result)
 				s.ResultMutex.Unlock()
+				if err := jsonl.Write(result); err != nil {
+					log.Printf("[!] Failed to write JSONL output %s: %v", s.Config.OutputJSONL, err)
+				}
+				if err := es.Write(result); err != nil {
+					log.Printf("[!] Failed to index %s: %v", result.URL, err)
+				}
+				notifier.Notify(result)
 
 				output.PrintResultTerminal(result) // Print result to terminal immediately
 				processedCount++
@@ -122,14 +232,28 @@ func (s *Scanner) Run(urls []string) []types.ScanResult {
 				s.ResultMutex.Lock()
 				currentProcessed := len(s.Results)
 				s.ResultMutex.Unlock()
-				fmt.Printf("\rProgress: %d/%d (%.2f%%)", currentProcessed, totalURLs, float64(currentProcessed)/float64(totalURLs)*100)
+				if s.Config.PlainLog || s.Config.OutputTemplate != "" || s.Config.Silent {
+					// No carriage-return updates in log files, templated or silent output
+					log.Printf("[+] Progress: %d/%d (%.2f%%)", currentProcessed, totalURLs, float64(currentProcessed)/float64(totalURLs)*100)
+				} else {
+					fmt.Printf("\rProgress: %d/%d (%.2f%%)", currentProcessed, totalURLs, float64(currentProcessed)/float64(totalURLs)*100)
+				}
+
+			case <-checkpointC:
+				if err := s.saveCheckpoint(prog); err != nil {
+					log.Printf("[!] Error saving state file %s: %v", s.Config.ResumeFile, err)
+				}
 
-			case <-scanCtx.Done():
-				log.Println("[!] Scan context cancelled during result collection.")
-				break collectLoop // Exit if context cancelled
+			case <-cancelled:
+				// Keep draining: results already sent still go to the outputs,
+				// and resultChan is closed once every worker has exited
+				log.Println("[!] Scan context cancelled during result collection, collecting results in flight.")
+				cancelled = nil
 			}
 		}
-		fmt.Println() // Newline after final progress update
+		if !s.Config.PlainLog && s.Config.OutputTemplate == "" && !s.Config.Silent {
+			fmt.Println() // Newline after final progress update
+		}
 		log.Println("[+] Finished collecting results.")
 	}()
 
@@ -137,6 +261,7 @@ func (s *Scanner) Run(urls []string) []types.ScanResult {
 	// This happens *after* feeding URLs and *before* closing resultChan fully
 	log.Println("[+] Waiting for workers to complete...")
 	wg.Wait()
+	stopMonitor()
 	log.Println("[+] All workers have completed.")
 
 	// Now that workers are done, we can safely close the resultChan
@@ -161,6 +286,32 @@ func (s *Scanner) Run(urls []string) []types.ScanResult {
 	log.Println("[+] Waiting for result collector to finish...")
 	collectorWg.Wait()
 	log.Println("[+] Result collector finished.")
+	if prog != nil {
+		s.finishCheckpoint(prog)
+	}
+	if jsonl != nil {
+		jsonl.Close()
+		log.Printf("[+] %d results streamed to: %s", jsonl.Count(), s.Config.OutputJSONL)
+	}
+	if es != nil {
+		closeCtx, cancelClose := context.WithTimeout(context.Background(), time.Minute)
+		if err := es.Close(closeCtx); err != nil {
+			log.Printf("[!] Elasticsearch: gave up sending the last documents: %v", err)
+		}
+		cancelClose()
+		indexed, failed := es.Stats()
+		log.Printf("[+] Elasticsearch: %d results indexed into %s, %d failed", indexed, s.Config.ESIndex, failed)
+	}
+	if notifier != nil {
+		// Deliver the last batch and the summary even when the scan was interrupted
+		s.ResultMutex.Lock()
+		summary := notify.Summarize(s.Results, startTime, ctx.Err() != nil)
+		s.ResultMutex.Unlock()
+		closeCtx, cancelClose := context.WithTimeout(context.Background(), 30*time.Second)
+		notifier.Close(closeCtx, &summary)
+		cancelClose()
+		log.Printf("[+] Notifications: %s", notifier.Report())
+	}
 
 	endTime := time.Now()
 	duration := endTime.Sub(startTime)
@@ -177,9 +328,80 @@ func (s *Scanner) Run(urls []string) []types.ScanResult {
 	}
 	log.Printf("[+] Total URLs Scanned: %d", len(s.Results))
 	log.Printf("[+] Vulnerable URLs Found: %d", numVulnerable)
+	numInteresting := 0
+	for _, r := range s.Results {
+		if !r.IsVulnerable && len(r.Interesting) > 0 {
+			numInteresting++
+		}
+	}
+	if numInteresting > 0 {
+		log.Printf("[+] Interesting URLs (worth a manual look): %d", numInteresting)
+	}
+	if numFiltered > 0 {
+		log.Printf("[+] Filtered responses (not stored): %d", numFiltered)
+	}
+	if crawler != nil {
+		log.Printf("[+] Pages added by the crawl: %d", crawler.Added())
+	}
+	if len(statusCounts) > 0 {
+		log.Printf("[+] Status codes: %s", FormatStatusCounts(statusCounts))
+	}
+	utilStats := util.Stats()
+	log.Printf("[+] Worker time: %s", utilStats)
+	if utilStats.Advice != "" {
+		log.Printf("[+] Throughput: %s", utilStats.Advice)
+	}
+	numBlocked := 0
+	for _, r := range s.Results {
+		if r.Blocked != "" {
+			numBlocked++
+		}
+	}
+	if numBlocked > 0 {
+		log.Printf("[!] Blocked responses (WAF/rate limit): %d", numBlocked)
+	}
+	numLogin := 0
+	for _, r := range s.Results {
+		if r.LoginRedirect {
+			numLogin++
+		}
+	}
+	if numLogin > 0 {
+		log.Printf("[+] Findings on login pages reached via redirect (%s): %d", s.Config.LoginRedirects, numLogin)
+	}
+	if hosts := s.Client.Evasion.EvadedHosts(); len(hosts) > 0 {
+		log.Printf("[!] Evasion profile applied to: %s", strings.Join(hosts, ", "))
+	}
+	if seen != nil {
+		numDuplicates := 0
+		for _, r := range s.Results {
+			if r.Duplicate {
+				numDuplicates++
+			}
+		}
+		log.Printf("[+] Duplicate responses skipped: %d (%d distinct bodies)", numDuplicates, seen.Len())
+	}
+	if s.Client.Cache != nil {
+		numUnchanged := 0
+		for _, r := range s.Results {
+			if r.Unchanged {
+				numUnchanged++
+			}
+		}
+		log.Printf("[+] Unchanged since last run (304): %d", numUnchanged)
+		if err := s.Client.Cache.Save(); err != nil {
+			log.Printf("[!] Error saving response cache %s: %v", s.Config.CacheFile, err)
+		}
+	}
 
-	// Process results for file output
-	if err := output.WriteResultsToFile(s.Config, s.Results); err != nil {
+	// Process results for file output; an interrupted scan still writes what
+	// it collected, so Ctrl+C doesn't throw the results away
+	writeCtx := ctx
+	if ctx.Err() != nil {
+		log.Printf("[!] Scan interrupted: writing the %d results collected so far", len(s.Results))
+		writeCtx = context.Background()
+	}
+	if err := output.WriteResultsToFile(writeCtx, s.Config, s.Results); err != nil {
 		log.Printf("[!] Error writing output files: %v", err)
 	}
 
@@ -0,0 +1,226 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/nxneeraj/hx-hawks/pkg/config"
+	"github.com/nxneeraj/hx-hawks/pkg/httpclient"
+)
+
+// Limits per host for --sitemap, so a huge sitemap can't swamp the scan.
+const (
+	maxSeedsPerHost    = 1000 // URLs taken from robots.txt and sitemaps
+	maxSitemapsPerHost = 10   // Sitemap documents fetched, nested indexes included
+)
+
+// SeedFromSitemaps fetches robots.txt and sitemap.xml (plus the sitemaps
+// robots.txt lists) of every host among targets and returns the same-host
+// URLs they name that aren't targets already: Allow/Disallow paths and
+// sitemap <loc> entries. Disallowed paths are often exactly the debug and
+// admin pages worth scanning.
+func SeedFromSitemaps(ctx context.Context, cfg *config.Config, targets []string) []string {
+	known := make(map[string]bool, len(targets))
+	var origins []string
+	seenOrigin := make(map[string]bool)
+	for _, target := range targets {
+		known[target] = true
+		plain, _ := SplitVHost(target)
+		u, err := url.Parse(plain)
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			continue
+		}
+		origin := u.Scheme + "://" + u.Host
+		if !seenOrigin[origin] {
+			seenOrigin[origin] = true
+			origins = append(origins, origin)
+		}
+	}
+	if len(origins) == 0 {
+		return nil
+	}
+
+	// Plain GETs: the scan's method, body and validator cache don't apply here
+	client := httpclient.NewClient(cfg)
+	client.Method, client.Body, client.Cache, client.SkipBinary = http.MethodGet, nil, nil, false
+
+	found := make([][]string, len(origins))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < cfg.Threads && w < len(origins); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				found[i] = seedOrigin(ctx, cfg, client, origins[i])
+			}
+		}()
+	}
+	for i := range origins {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	var seeds []string
+	for _, urls := range found {
+		for _, u := range urls {
+			if !known[u] {
+				known[u] = true
+				seeds = append(seeds, u)
+			}
+		}
+	}
+	logger := cfg.Logger
+	if logger == nil {
+		logger = log.Default()
+	}
+	logger.Printf("[+] Seeded %d URLs from robots.txt and sitemaps of %d hosts", len(seeds), len(origins))
+	return seeds
+}
+
+// seedOrigin returns the URLs robots.txt and the sitemaps of one origin
+// name, in the order found.
+func seedOrigin(ctx context.Context, cfg *config.Config, client *httpclient.CustomClient, origin string) []string {
+	host := strings.ToLower(origin[strings.Index(origin, "://")+3:])
+	var urls []string
+	seen := make(map[string]bool)
+	add := func(raw string) {
+		u, err := url.Parse(raw)
+		if err != nil || strings.ToLower(u.Host) != host || len(urls) >= maxSeedsPerHost {
+			return
+		}
+		u.Fragment, u.RawFragment = "", ""
+		if s := u.String(); !seen[s] {
+			seen[s] = true
+			urls = append(urls, s)
+		}
+	}
+
+	sitemaps := []string{origin + "/sitemap.xml"}
+	if body := fetchSeed(ctx, cfg, client, origin+"/robots.txt"); body != nil {
+		paths, listed := ParseRobots(body)
+		for _, p := range paths {
+			add(origin + p)
+		}
+		sitemaps = append(sitemaps, listed...)
+	}
+
+	fetched := make(map[string]bool)
+	for len(sitemaps) > 0 && len(fetched) < maxSitemapsPerHost && len(urls) < maxSeedsPerHost {
+		next := sitemaps[0]
+		sitemaps = sitemaps[1:]
+		if fetched[next] {
+			continue
+		}
+		fetched[next] = true
+		body := fetchSeed(ctx, cfg, client, next)
+		if body == nil {
+			continue
+		}
+		locs, nested := ParseSitemap(body)
+		for _, loc := range locs {
+			add(loc)
+		}
+		sitemaps = append(sitemaps, nested...)
+	}
+	return urls
+}
+
+// fetchSeed GETs a robots.txt or sitemap and returns its body, or nil unless
+// it answered 200.
+func fetchSeed(ctx context.Context, cfg *config.Config, client *httpclient.CustomClient, target string) []byte {
+	reqCtx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+	resp, err := client.Fetch(reqCtx, target, nil)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return nil
+	}
+	return resp.Body
+}
+
+// ParseRobots returns the Allow/Disallow paths of a robots.txt, cut before
+// the first wildcard (* or $), and the URLs of its Sitemap lines. Paths that
+// reduce to "/" are dropped.
+func ParseRobots(body []byte) (paths, sitemaps []string) {
+	seen := make(map[string]bool)
+	sc := bufio.NewScanner(bytes.NewReader(body))
+	for sc.Scan() {
+		line, _, _ := strings.Cut(sc.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "allow", "disallow":
+			if i := strings.IndexAny(value, "*$"); i >= 0 {
+				value = value[:i]
+			}
+			if !strings.HasPrefix(value, "/") || value == "/" || seen[value] {
+				continue
+			}
+			seen[value] = true
+			paths = append(paths, value)
+		case "sitemap":
+			if strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://") {
+				sitemaps = append(sitemaps, value)
+			}
+		}
+	}
+	return paths, sitemaps
+}
+
+// ParseSitemap returns the <loc> entries of a sitemap: page URLs for a
+// <urlset>, nested sitemap URLs for a <sitemapindex>. Gzipped sitemaps
+// (sitemap.xml.gz) are decompressed first.
+func ParseSitemap(body []byte) (urls, sitemaps []string) {
+	var r io.Reader = bytes.NewReader(body)
+	if len(body) > 2 && body[0] == 0x1f && body[1] == 0x8b {
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, nil
+		}
+		defer zr.Close()
+		r = zr
+	}
+	dec := xml.NewDecoder(r)
+	dec.Strict = false
+	index := false
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return urls, sitemaps
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "sitemapindex":
+			index = true
+		case "loc":
+			var loc string
+			if dec.DecodeElement(&loc, &start) != nil {
+				continue
+			}
+			if loc = strings.TrimSpace(loc); loc == "" {
+				continue
+			}
+			if index {
+				sitemaps = append(sitemaps, loc)
+			} else {
+				urls = append(urls, loc)
+			}
+		}
+	}
+}
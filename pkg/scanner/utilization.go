@@ -0,0 +1,106 @@
+package scanner
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// Phase is one of the things a worker spends its time on.
+type Phase int
+
+const (
+	PhaseNetwork  Phase = iota // Fetching (including bodies matched while streaming) and DNS lookups
+	PhaseMatching              // Decoding, fingerprinting and matching downloaded bodies
+	PhaseSending               // Blocked handing the result to the collector
+	PhaseDelay                 // Sleeping for --delay
+	numPhases
+)
+
+// Utilization accumulates worker time per phase, so users can tell whether
+// to raise threads or rate, or simplify rules, to go faster. It is safe for
+// concurrent use; a nil Utilization does nothing.
+type Utilization struct {
+	spent [numPhases]atomic.Int64 // Nanoseconds
+}
+
+// UtilizationStats is a snapshot of a Utilization.
+type UtilizationStats struct {
+	NetworkSeconds  float64 `json:"network_seconds"`
+	MatchingSeconds float64 `json:"matching_seconds"`
+	SendingSeconds  float64 `json:"sending_seconds"`
+	DelaySeconds    float64 `json:"delay_seconds"`
+	Advice          string  `json:"advice,omitempty"`
+}
+
+// NewUtilization creates an empty accumulator.
+func NewUtilization() *Utilization {
+	return &Utilization{}
+}
+
+// Add records d spent in phase p.
+func (u *Utilization) Add(p Phase, d time.Duration) {
+	if u == nil {
+		return
+	}
+	u.spent[p].Add(int64(d))
+}
+
+// Since records the time since start in phase p and returns the current
+// time, so consecutive phases can be chained.
+func (u *Utilization) Since(p Phase, start time.Time) time.Time {
+	now := time.Now()
+	u.Add(p, now.Sub(start))
+	return now
+}
+
+// Stats returns the time spent so far with advice on the bottleneck.
+func (u *Utilization) Stats() UtilizationStats {
+	if u == nil {
+		return UtilizationStats{}
+	}
+	seconds := func(p Phase) float64 { return time.Duration(u.spent[p].Load()).Seconds() }
+	s := UtilizationStats{
+		NetworkSeconds:  seconds(PhaseNetwork),
+		MatchingSeconds: seconds(PhaseMatching),
+		SendingSeconds:  seconds(PhaseSending),
+		DelaySeconds:    seconds(PhaseDelay),
+	}
+	s.Advice = s.advice()
+	return s
+}
+
+// Total returns the worker time recorded across all phases, in seconds.
+func (s UtilizationStats) Total() float64 {
+	return s.NetworkSeconds + s.MatchingSeconds + s.SendingSeconds + s.DelaySeconds
+}
+
+// String renders the shares, e.g. "network 81% (12.3s), matching 12% (1.8s), ...".
+func (s UtilizationStats) String() string {
+	total := s.Total()
+	if total == 0 {
+		return "no worker time recorded"
+	}
+	pct := func(v float64) float64 { return v / total * 100 }
+	return fmt.Sprintf("network %.0f%% (%.1fs), matching %.0f%% (%.1fs), waiting on results %.0f%% (%.1fs), delay %.0f%% (%.1fs)",
+		pct(s.NetworkSeconds), s.NetworkSeconds, pct(s.MatchingSeconds), s.MatchingSeconds,
+		pct(s.SendingSeconds), s.SendingSeconds, pct(s.DelaySeconds), s.DelaySeconds)
+}
+
+// advice names the dominant phase and what to change to go faster.
+func (s UtilizationStats) advice() string {
+	total := s.Total()
+	switch {
+	case total == 0:
+		return ""
+	case s.DelaySeconds/total >= 0.5:
+		return "workers mostly sleep for --delay; lower it if the targets allow"
+	case s.SendingSeconds/total >= 0.2:
+		return "workers block handing off results; output/terminal printing is the bottleneck"
+	case s.MatchingSeconds/total >= 0.4:
+		return "matching is the bottleneck; simplify regex/selector rules or drop unused keywords"
+	case s.NetworkSeconds/total >= 0.7:
+		return "workers mostly wait on the network; raise --threads (or the rate) if the targets allow"
+	}
+	return ""
+}
@@ -0,0 +1,43 @@
+package scanner
+
+import "strings"
+
+// vhostMark starts the URL fragment that carries a target's virtual host.
+// Fragments are never sent, so the mark only selects the Host header, and a
+// result URL fed back as a target keeps its virtual host.
+const vhostMark = "#vhost="
+
+// WithVHost marks target to be requested with "Host: vhost" while the
+// connection still goes to the target's own host.
+func WithVHost(target, vhost string) string {
+	target, _ = SplitVHost(target)
+	if i := strings.Index(target, "#"); i >= 0 {
+		target = target[:i]
+	}
+	return target + vhostMark + vhost
+}
+
+// SplitVHost returns target without its virtual host mark, and the virtual
+// host ("" for unmarked targets).
+func SplitVHost(target string) (string, string) {
+	i := strings.LastIndex(target, vhostMark)
+	if i < 0 {
+		return target, ""
+	}
+	return target[:i], target[i+len(vhostMark):]
+}
+
+// ExpandVHosts returns every target once per virtual host (--vhost-list), or
+// targets unchanged when there are none.
+func ExpandVHosts(targets, vhosts []string) []string {
+	if len(vhosts) == 0 {
+		return targets
+	}
+	out := make([]string, 0, len(targets)*len(vhosts))
+	for _, target := range targets {
+		for _, vhost := range vhosts {
+			out = append(out, WithVHost(target, vhost))
+		}
+	}
+	return out
+}
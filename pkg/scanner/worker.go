@@ -1,14 +1,19 @@
 package scanner
 
 import (
+	"bytes"
 	"context"
 	"log"
-	"strings"
+	"net/url"
 	//"sync"
 	"time"
 
 	
+	"github.com/nxneeraj/hx-hawks/pkg/config"
+	"github.com/nxneeraj/hx-hawks/pkg/fingerprint"
 	"github.com/nxneeraj/hx-hawks/pkg/httpclient"
+	"github.com/nxneeraj/hx-hawks/pkg/matcher"
+	"github.com/nxneeraj/hx-hawks/pkg/rules"
 	"github.com/nxneeraj/hx-hawks/pkg/types"
 	"github.com/nxneeraj/hx-hawks/pkg/utils"
 )
@@ -16,11 +21,22 @@ import (
 // Worker function that processes URLs from the urls channel and sends results to the results channel.
 // Note: Removed wg *sync.WaitGroup from parameters as it's handled in the calling function (scanner.Run)
 // to avoid potential race conditions if not used carefully. The caller waits for completion.
-func Worker(ctx context.Context, id int, client *httpclient.CustomClient, keywords []string, delay time.Duration, urls <-chan string, results chan<- types.ScanResult, verbose bool) {
+// Delay, status-code conditions and verbosity are taken from cfg; engine is
+// built once from cfg.Keywords and cfg.Rules by the caller and shared by all workers.
+// seen is the shared body-hash index for --dedupe-responses (nil when disabled).
+// mon tracks in-flight requests for heartbeats and stall detection (nil = off).
+// util accumulates time spent per phase (nil = off).
+func Worker(ctx context.Context, id int, client *httpclient.CustomClient, cfg *config.Config, engine *rules.Engine, seen *ResponseIndex, mon *Monitor, util *Utilization, urls <-chan string, results chan<- types.ScanResult) {
 	// Removed wg.Done() as wg is not passed anymore
+	delay, verbose := cfg.Delay, cfg.Verbose
+	logger := cfg.Logger
+	if logger == nil {
+		logger = log.Default()
+	}
+	earlyStop := !cfg.FullBody && len(cfg.MatchSizes) == 0 && len(cfg.FilterSizes) == 0 && !engine.NeedsFullBody() && !cfg.Entropy && !cfg.Crawl && !cfg.TechDetect && cfg.StoreResponses == "" && !cfg.DedupeResponses // Dedupe compares hashes of whole bodies, fingerprints look anywhere in them
 
 	if verbose {
-		log.Printf("[Worker %d] Started", id)
+		logger.Printf("[Worker %d] Started", id)
 	}
 
 	for {
@@ -29,76 +45,227 @@ func Worker(ctx context.Context, id int, client *httpclient.CustomClient, keywor
 			if !ok {
 				// Channel closed, no more URLs
 				if verbose {
-					log.Printf("[Worker %d] Finished", id)
+					logger.Printf("[Worker %d] Finished", id)
 				}
 				return
 			}
 
 			if verbose {
-				log.Printf("[Worker %d] Processing: %s", id, urlStr)
+				logger.Printf("[Worker %d] Processing: %s", id, urlStr)
 			}
 
-			// Process the URL
-			scanCtx, cancel := context.WithTimeout(ctx, client.Client.Timeout) // Use client's configured timeout per request
-			finalURL, statusCode, bodyBytes, duration, err := client.Fetch(scanCtx, urlStr)
-			cancel() // Ensure context is cancelled
+			// Process the URL (with the evasion profile if its host keeps blocking)
+			phaseStart := time.Now()
+			reqCtx := mon.Begin(ctx, id, urlStr)
+			_, vhost := SplitVHost(urlStr)
+			if vhost != "" {
+				reqCtx = httpclient.WithHost(reqCtx, vhost)
+			}
+			resp, stream, err := fetchURL(reqCtx, client, engine, earlyStop, urlStr)
+			if ctx.Err() == nil && reqCtx.Err() != nil {
+				// Cancelled by the stall monitor (--stall-abort), record it and move on
+				err = ErrStallAborted
+				if resp == nil {
+					resp = &httpclient.Response{FinalURL: urlStr}
+				}
+			}
+			mon.End(id)
+			err = classifyError(urlStr, err)
+			if resp == nil {
+				if verbose {
+					logger.Printf("[Worker %d] Context cancelled while waiting to fetch %s", id, urlStr)
+				}
+				return
+			}
+			statusCode, bodyBytes := resp.StatusCode, resp.Body
 
 			result := types.ScanResult{
-				URL:             finalURL, // Use final URL after redirects
+				URL:             resp.FinalURL, // Use final URL after redirects
 				Timestamp:       time.Now().UTC(),
 				StatusCode:      statusCode,
-				RequestDuration: duration,
-				IP:              utils.GetIP(finalURL), // Attempt to get IP
+				ContentType:     resp.Header.Get("Content-Type"),
+				RequestDuration: resp.Duration,
+				BodyTruncated:   resp.Truncated,
+				CertSHA256:      resp.CertSHA256,
+				TLSError:        resp.TLSError,
+				RedirectChain:   resp.Redirects,
+				Blocked:         resp.BlockReason,
+				Evasion:         resp.Evasion,
+				RemoteIP:        resp.RemoteIP,
+				RemotePort:      resp.RemotePort,
+				Inputs:          cfg.Inputs,
+				VHost:           vhost,
+				Target:          urlStr,
 			}
+			// Attempt to resolve every IP and the CNAME chain of the final host
+			resolution := utils.ResolveURL(resp.FinalURL)
+			result.IP = resolution.PrimaryIP()
+			result.IPs = resolution.IPs
+			result.CNAMEs = resolution.CNAMEs
+			phaseStart = util.Since(PhaseNetwork, phaseStart)
+
+			// Decode non-UTF-8 bodies (e.g. Shift-JIS, GBK, ISO-8859-1) so keywords
+			// match on international sites, then pull out the page title
+			charsetName := ""
+			if err == nil && len(bodyBytes) > 0 {
+				// Hash the raw body so identical responses can be grouped; a
+				// truncated body would hash wherever the download happened to stop
+				if !resp.Truncated {
+					result.BodySHA256 = utils.SHA256Hex(bodyBytes)
+					result.BodyMMH3 = utils.MMH3(bodyBytes)
+				}
 
+				var decoded []byte
+				decoded, charsetName = httpclient.DecodeToUTF8(result.ContentType, bodyBytes)
+				if charsetName != "" {
+					result.Charset = charsetName
+					bodyBytes = decoded
+				}
+				result.Title = utils.ExtractTitle(bodyBytes)
+				if cfg.Crawl {
+					result.Links = ExtractLinks(result.URL, result.ContentType, bodyBytes)
+				}
+			}
+			// Tag technologies from headers, cookies and whatever body was downloaded
+			if err == nil && cfg.TechDetect {
+				result.Technologies = fingerprint.Detect(cfg.Fingerprints, resp.Header, bodyBytes)
+			}
+
+			filtered := false
 			if err != nil {
 				result.Error = err.Error()
+				result.ErrorClass = ErrorClass(err)
+				if verbose {
+					logger.Printf("[Worker %d] Error fetching %s: %v", id, urlStr, err)
+				}
+			} else if statusFiltered(cfg, statusCode) || sizeFiltered(cfg, resp.Size) {
+				// Discard filtered status codes/sizes entirely (no matching, result not stored)
+				if verbose {
+					logger.Printf("[Worker %d] Filtered %s (Status: %d, Size: %d)", id, urlStr, statusCode, resp.Size)
+				}
+				filtered = true
+			} else if resp.NotModified {
+				// Unchanged since the last run (--cache-file), skip re-matching
+				// and report what the cached response matched
+				result.Unchanged = true
+				if f := resp.Cached; f != nil {
+					result.IsVulnerable, result.MatchedKeywords, result.MatchedRules = f.IsVulnerable, f.MatchedKeywords, f.MatchedRules
+					result.KeywordSeverity, result.Title = f.KeywordSeverity, f.Title
+				}
+				if verbose {
+					logger.Printf("[Worker %d] Unchanged (304): %s", id, urlStr)
+				}
+			} else if resp.Binary {
+				// Non-text content skipped by --skip-binary, nothing to match
+				result.BinarySkipped = true
+				if verbose {
+					logger.Printf("[Worker %d] Skipped binary content (%s) at %s", id, result.ContentType, urlStr)
+				}
+			} else if !statusMatched(cfg, statusCode) || !sizeMatched(cfg, resp.Size) {
+				// Status code or size doesn't satisfy --match-code/--match-size, keep as a safe result
 				if verbose {
-					log.Printf("[Worker %d] Error fetching %s: %v", id, urlStr, err)
+					logger.Printf("[Worker %d] Status %d / size %d not in match list for %s", id, statusCode, resp.Size, urlStr)
+				}
+			} else if firstURL, dup := seen.Check(result.BodySHA256, result.URL); dup {
+				// Same body as an earlier response (--dedupe-responses), don't match or store it again
+				result.Duplicate = true
+				result.DuplicateOf = firstURL
+				if verbose {
+					logger.Printf("[Worker %d] Duplicate response at %s (same body as %s)", id, urlStr, firstURL)
 				}
 			} else {
-				// Successful fetch, now check keywords
-				bodyString := string(bodyBytes) // Convert body to string for searching
-				matched := []string{}
-				isVulnerable := false
+				// Successful fetch, now check keywords in a single pass over the body
+				var found []string
+				if stream != nil && (charsetName == "" || stream.Done()) {
+					found = stream.Matched() // Already matched while downloading
+				} else {
+					found = engine.Automaton.Match(bodyBytes)
+				}
+				matched, ruleHits := engine.Evaluate(urlStr, result.Technologies, found, bodyBytes)
+				isVulnerable := len(matched) > 0 || len(ruleHits) > 0
+				result.MatchedRules = ruleHits
+				if cfg.Entropy {
+					// Possible tokens/keys, reported even when nothing else matched
+					if candidates := FindHighEntropy(bodyBytes, cfg.EntropyThreshold); len(candidates) > 0 {
+						result.HighEntropy = candidates
+						result.MatchedRules = append(result.MatchedRules, highEntropyRule)
+						isVulnerable = true
+					}
+				}
+
+				if lowConfidence(matched, result.MatchedRules) {
+					isVulnerable = false // Reported in the "interesting" tier instead
+				}
 
 				// Store response body *only* if needed for output or vulnerability is found
 				// This saves memory if not using -o-response, -o-all-json, etc.
 				// Decision to store body can be made more granular based on output flags later.
-				includeBody := true // Simplification for now: always include body if fetched successfully
-
-				for _, keyword := range keywords {
-					// Simple case-sensitive check. Use strings.ContainsFold for case-insensitive.
-					if strings.Contains(bodyString, keyword) {
-						// Avoid adding duplicates if keyword appears multiple times
-						found := false
-						for _, m := range matched {
-							if m == keyword {
-								found = true
-								break
-							}
-						}
-						if !found {
-							matched = append(matched, keyword)
-						}
-						isVulnerable = true
-					}
-				}
+				includeBody := cfg.StoreResponses == "" // Stored bodies are referenced by response_file instead
+				bodyString := string(bodyBytes)
 
 				result.IsVulnerable = isVulnerable
 				result.MatchedKeywords = matched
+				for _, k := range matched {
+					if sev := cfg.KeywordSeverity[k]; sev != "" {
+						if result.KeywordSeverity == nil {
+							result.KeywordSeverity = make(map[string]string)
+						}
+						result.KeywordSeverity[k] = sev
+					}
+				}
+				if isVulnerable && cfg.LoginRedirects != "off" && isLoginRedirect(&result, bodyBytes) {
+					// Evidence comes from the login/SSO page we were bounced to, not the target
+					applyLoginRedirect(cfg.LoginRedirects, &result)
+					if verbose {
+						logger.Printf("[Worker %d] %s redirected to login page %s (%s)", id, urlStr, result.URL, cfg.LoginRedirects)
+					}
+				}
 				if includeBody {
 					result.ResponseBody = bodyString // Attach if vulnerable or output requires it
 				}
 			}
 
-			// Send result back to the main goroutine
+			// Remember what a complete response matched for 304s on later runs (--cache-file)
+			if !filtered && err == nil && !resp.NotModified && !result.Duplicate {
+				client.CacheFindings(urlStr, resp, httpclient.CachedFindings{
+					IsVulnerable:    result.IsVulnerable,
+					MatchedKeywords: result.MatchedKeywords,
+					MatchedRules:    result.MatchedRules,
+					KeywordSeverity: result.KeywordSeverity,
+					Title:           result.Title,
+				})
+			}
+
+			// A certificate that differs from the pinned one is a finding on its own
+			if !filtered && err == nil && checkPin(cfg, &result) {
+				logger.Printf("[!] Certificate pin mismatch for %s (got sha256/%s)", result.URL, result.CertSHA256)
+			}
+
+			if !filtered {
+				result.Severity = types.ResultSeverity(result)
+				result.Interesting = interestingReasons(&result)
+			}
+
+			// Keep the full exchange on disk (--store-responses), referenced from the result
+			if !filtered && err == nil && cfg.StoreResponses != "" {
+				if path, storeErr := StoreResponse(cfg.StoreResponses, urlStr, resp); storeErr != nil {
+					logger.Printf("[!] Failed to store response for %s: %v", urlStr, storeErr)
+				} else {
+					result.ResponseFile = path
+				}
+			}
+
+			// Send result back to the main goroutine. Filtered responses are sent
+			// too (marked Filtered) so callers can track progress, but not stored.
 			// Use a select to prevent blocking indefinitely if the receiver stops listening
+			result.Filtered = filtered
+			phaseStart = util.Since(PhaseMatching, phaseStart)
 			select{
 			case results <- result:
+				util.Since(PhaseSending, phaseStart)
 			case <-ctx.Done():
 				if verbose {
-					log.Printf("[Worker %d] Context cancelled while sending result for %s", id, urlStr)
+					logger.Printf("[Worker %d] Context cancelled while sending result for %s", id, urlStr)
 				}
 				return // Exit if context cancelled
 			}
@@ -109,10 +276,11 @@ func Worker(ctx context.Context, id int, client *httpclient.CustomClient, keywor
 				select {
 				case <-time.After(delay):
 					// Delay completed
+					util.Add(PhaseDelay, delay)
 				case <-ctx.Done():
 					// Scan cancelled during delay
 					if verbose {
-						log.Printf("[Worker %d] Scan cancelled during delay", id)
+						logger.Printf("[Worker %d] Scan cancelled during delay", id)
 					}
 					return
 				}
@@ -121,9 +289,87 @@ func Worker(ctx context.Context, id int, client *httpclient.CustomClient, keywor
 		case <-ctx.Done():
 			// Context cancelled (e.g., timeout, signal)
 			if verbose {
-				log.Printf("[Worker %d] Context cancelled, stopping.", id)
+				logger.Printf("[Worker %d] Context cancelled, stopping.", id)
 			}
 			return
 		}
 	}
 }
+
+// fetchURL fetches urlStr with the evasion plan for its host, if any. A block
+// page that pushed the host into evasion mode is retried once under the
+// evasion profile. When earlyStop is set the body is streamed through the
+// keyword matcher so the download can stop once every keyword has matched
+// and the page title has been read (size conditions need the full body). The response is nil only if ctx was
+// cancelled while waiting for the host's evasion delay.
+func fetchURL(ctx context.Context, client *httpclient.CustomClient, engine *rules.Engine, earlyStop bool, urlStr string) (*httpclient.Response, *matcher.Stream, error) {
+	host := ""
+	if u, err := url.Parse(urlStr); err == nil {
+		host = u.Hostname()
+	}
+
+	for attempt := 0; ; attempt++ {
+		plan := client.Evasion.Plan(host)
+		if plan.Wait > 0 {
+			select {
+			case <-time.After(plan.Wait):
+			case <-ctx.Done():
+				return nil, nil, ctx.Err()
+			}
+		}
+
+		// No deadline here: Fetch bounds each attempt (--retries) by the client's timeout
+		scanCtx, cancel := context.WithCancel(httpclient.WithPlan(ctx, plan))
+		var stream *matcher.Stream
+		var sink httpclient.BodySink
+		if earlyStop {
+			stream = engine.Automaton.NewStream()
+			sink = &titleSink{Stream: stream}
+		}
+		resp, err := client.Fetch(scanCtx, urlStr, sink)
+		cancel() // Ensure context is cancelled
+
+		if attempt == 0 && err == nil && resp.BlockReason != "" && len(plan.Applied) == 0 && client.Evasion.Active(host) {
+			continue // Blocked before evasion kicked in, try again with it
+		}
+		if attempt > 0 {
+			resp.Evasion = append([]string{"retry"}, resp.Evasion...)
+		}
+		return resp, stream, err
+	}
+}
+
+// titleSink holds an early stop back until the page title has been read, so
+// results keep their title: it is done once the keyword stream is and the
+// body has passed "</title" (or "<body", for pages without one). Bodies that
+// don't start with a tag have no title to wait for.
+type titleSink struct {
+	*matcher.Stream
+	tail    []byte // Lowercased end of the previous chunk, for tags split across chunks
+	started bool   // The first non-blank byte was seen
+	title   bool   // Past the title, or there is none
+}
+
+func (t *titleSink) Write(p []byte) (int, error) {
+	if !t.title {
+		if !t.started {
+			if trimmed := bytes.TrimLeft(p, " \t\r\n\ufeff"); len(trimmed) > 0 {
+				t.started = true
+				t.title = trimmed[0] != '<'
+			}
+		}
+		buf := append(t.tail, bytes.ToLower(p)...)
+		if bytes.Contains(buf, []byte("</title")) || bytes.Contains(buf, []byte("<body")) {
+			t.title = true
+		}
+		if len(buf) > len("</title")-1 {
+			buf = buf[len(buf)-(len("</title")-1):]
+		}
+		t.tail = append(t.tail[:0], buf...)
+	}
+	return t.Stream.Write(p)
+}
+
+func (t *titleSink) Done() bool {
+	return t.title && t.Stream.Done()
+}
@@ -0,0 +1,115 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// fieldAliases maps the short names accepted by --fields to ScanResult JSON
+// keys. Any JSON key of ScanResult is accepted as-is as well.
+var fieldAliases = map[string]string{
+	"status":     "status_code",
+	"keywords":   "matched_keywords",
+	"rules":      "matched_rules",
+	"body":       "response",
+	"tech":       "technologies",
+	"vulnerable": "is_vulnerable",
+	"duration":   "request_duration_seconds",
+	"sha256":     "body_sha256",
+	"mmh3":       "body_mmh3",
+}
+
+// resultKeys are the JSON keys of ScanResult.
+var resultKeys = []string{
+	"url", "title", "vhost", "blocked", "evasion", "technologies", "redirect_chain",
+	"login_redirect", "is_vulnerable", "interesting", "matched_keywords", "matched_rules", "keyword_severity", "response",
+	"status_code", "content_type", "charset", "binary_skipped", "unchanged",
+	"body_truncated", "body_sha256", "body_mmh3", "cert_sha256", "pin_mismatch", "tls_error",
+	"high_entropy", "duplicate", "duplicate_of", "ip", "ips", "cnames", "remote_ip", "remote_port", "timestamp", "error",
+	"error_class", "request_duration_seconds", "inputs", "severity",
+}
+
+// severityRank orders severities, lowest first.
+var severityRank = map[string]int{"info": 1, "low": 2, "medium": 3, "high": 4, "critical": 5}
+
+// ParseFields resolves a comma-separated --fields value into JSON keys,
+// keeping the given order.
+func ParseFields(raw string) ([]string, error) {
+	var fields []string
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if key, ok := fieldAliases[name]; ok {
+			name = key
+		}
+		known := false
+		for _, k := range resultKeys {
+			if k == name {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown field %q (known: %s)", name, strings.Join(FieldNames(), ", "))
+		}
+		fields = append(fields, name)
+	}
+	return fields, nil
+}
+
+// FieldNames lists every name accepted by ParseFields.
+func FieldNames() []string {
+	names := append([]string{}, resultKeys...)
+	for alias := range fieldAliases {
+		names = append(names, alias)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// HighestSeverity returns the most severe rule severity in matches, or "".
+func HighestSeverity(matches []RuleMatch) string {
+	best := ""
+	for _, m := range matches {
+		sev := strings.ToLower(m.Severity)
+		if best == "" || severityRank[sev] > severityRank[best] {
+			best = sev
+		}
+	}
+	return best
+}
+
+// ResultSeverity returns the most severe severity among a result's matched
+// rules and keywords, or "".
+func ResultSeverity(r ScanResult) string {
+	best := HighestSeverity(r.MatchedRules)
+	for _, sev := range r.KeywordSeverity {
+		best = MaxSeverity(best, sev)
+	}
+	return best
+}
+
+// IsSeverity reports whether s is a known severity: info, low, medium, high
+// or critical (any case).
+func IsSeverity(s string) bool {
+	return severityRank[strings.ToLower(s)] > 0
+}
+
+// SortBySeverity orders results most severe first (see ScanResult.Severity),
+// keeping the original order among equal severities.
+func SortBySeverity(results []ScanResult) {
+	sort.SliceStable(results, func(i, j int) bool {
+		return severityRank[results[i].Severity] > severityRank[results[j].Severity]
+	})
+}
+
+// MaxSeverity returns the more severe of two rule severities ("" is lowest).
+func MaxSeverity(a, b string) string {
+	if severityRank[strings.ToLower(b)] > severityRank[strings.ToLower(a)] {
+		return strings.ToLower(b)
+	}
+	return a
+}
@@ -4,26 +4,263 @@ import "time"
 
 // ScanResult holds the outcome of scanning a single URL.
 type ScanResult struct {
-	URL             string    `json:"url"`
-	IsVulnerable    bool      `json:"is_vulnerable"`
-	MatchedKeywords []string  `json:"matched_keywords,omitempty"`
-	ResponseBody    string    `json:"response,omitempty"` // Can be large, include selectively
-	StatusCode      int       `json:"status_code"`
-	IP              string    `json:"ip,omitempty"` // Requires DNS lookup or parsing headers
-	Timestamp       time.Time `json:"timestamp"`
-	Error           string    `json:"error,omitempty"` // Store any error encountered
-	RequestDuration float64   `json:"request_duration_seconds"` // Time taken for the request
+	URL             string            `json:"url"`
+	Title           string            `json:"title,omitempty"`          // HTML <title> of the response
+	VHost           string            `json:"vhost,omitempty"`          // Host header sent instead of the URL's host (--vhost-list)
+	Blocked         string            `json:"blocked,omitempty"`        // Response looked like a WAF/CDN block or rate limit, and why
+	Evasion         []string          `json:"evasion,omitempty"`        // Evasion applied to get this response (--evasion)
+	Technologies    []string          `json:"technologies,omitempty"`   // Detected technologies, "Name" or "Name/version" (--tech-detect)
+	RedirectChain   []RedirectHop     `json:"redirect_chain,omitempty"` // Hops followed before reaching URL
+	LoginRedirect   bool              `json:"login_redirect,omitempty"` // Redirected to a login/SSO page; findings downgraded or suppressed (--login-redirects)
+	IsVulnerable    bool              `json:"is_vulnerable"`
+	MatchedKeywords []string          `json:"matched_keywords,omitempty"`
+	MatchedRules    []RuleMatch       `json:"matched_rules,omitempty"`
+	KeywordSeverity map[string]string `json:"keyword_severity,omitempty"` // Severity of matched keywords that have one (--ck "keyword:severity")
+	Severity        string            `json:"severity,omitempty"`         // Worst severity among matched keywords and rules
+	ResponseBody    string            `json:"response,omitempty"`         // Can be large, include selectively
+	ResponseFile    string            `json:"response_file,omitempty"`    // Stored request/response (--store-responses)
+	StatusCode      int               `json:"status_code"`
+	ContentType     string            `json:"content_type,omitempty"`
+	Charset         string            `json:"charset,omitempty"`        // Source charset if the body was decoded to UTF-8
+	BinarySkipped   bool              `json:"binary_skipped,omitempty"` // Body not read/matched (--skip-binary)
+	Unchanged       bool              `json:"unchanged,omitempty"`      // 304 to a conditional request (--cache-file)
+	BodyTruncated   bool              `json:"body_truncated,omitempty"` // Download stopped before the end of the body
+	BodySHA256      string            `json:"body_sha256,omitempty"`    // SHA-256 of the raw body (not when body_truncated)
+	BodyMMH3        int32             `json:"body_mmh3,omitempty"`      // MurmurHash3 (x86_32) of the raw body (not when body_truncated)
+	CertSHA256      string            `json:"cert_sha256,omitempty"`    // SHA-256 of the leaf TLS certificate
+	PinMismatch     bool              `json:"pin_mismatch,omitempty"`   // Certificate differs from the one pinned for the host (--pin)
+	TLSError        string            `json:"tls_error,omitempty"`      // Why the certificate fails verification; the scan went ahead (no --tls-verify)
+	Interesting     []string          `json:"interesting,omitempty"`    // Why a non-vulnerable result is worth a manual look ("interesting" tier)
+	HighEntropy     []string          `json:"high_entropy,omitempty"`   // Possible tokens/keys found by --entropy (low confidence)
+	Duplicate       bool              `json:"duplicate,omitempty"`      // Same body as an earlier response (--dedupe-responses)
+	DuplicateOf     string            `json:"duplicate_of,omitempty"`   // URL of the first response with this body
+	IP              string            `json:"ip,omitempty"`             // Requires DNS lookup or parsing headers
+	IPs             []string          `json:"ips,omitempty"`            // Every resolved IPv4/IPv6 address
+	CNAMEs          []string          `json:"cnames,omitempty"`         // CNAME chain of the host, in resolution order
+	RemoteIP        string            `json:"remote_ip,omitempty"`      // Peer address of the connection that served the response (IPv4 or IPv6)
+	RemotePort      int               `json:"remote_port,omitempty"`    // Peer port of that connection
+	Timestamp       time.Time         `json:"timestamp"`
+	Links           []string          `json:"-"`                        // Links found on the page (--crawl)
+	Target          string            `json:"-"`                        // Queued target the result is for (before redirects), for --resume
+	Filtered        bool              `json:"-"`                        // Dropped by --filter-code/--filter-size; reported for progress only, never stored
+	Error           string            `json:"error,omitempty"`          // Store any error encountered
+	ErrorClass      string            `json:"error_class,omitempty"`    // Failure class: timeout, dns, refused, tls, network or aborted
+	RequestDuration float64           `json:"request_duration_seconds"` // Time taken for the request
+	Inputs          *InputDigest      `json:"inputs,omitempty"`         // Digest of the targets and rules of the scan that produced the result
+}
+
+// RedirectHop is one redirect followed while fetching a URL.
+type RedirectHop struct {
+	URL        string `json:"url"`         // URL that answered with the redirect
+	StatusCode int    `json:"status_code"` // Redirect status (301, 302, 307, ...)
+}
+
+// RuleMatch identifies a rule (from a rules file or recipe) that matched a response.
+type RuleMatch struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name,omitempty"`
+	Severity    string   `json:"severity,omitempty"`
+	Confidence  string   `json:"confidence,omitempty"` // "low": a lead for manual review, not a finding on its own
+	Remediation string   `json:"remediation,omitempty"`
+	References  []string `json:"references,omitempty"`
 }
 
 // JobStatus represents the state of an API-triggered scan job.
 type JobStatus struct {
-	JobID          string        `json:"job_id"`
-	Status         string        `json:"status"` // e.g., "Pending", "Running", "Completed", "Error"
-	TotalURLs      int           `json:"total_urls"`
-	ProcessedURLs  int           `json:"processed_urls"`
-	VulnerableURLs int           `json:"vulnerable_urls"`
-	StartTime      time.Time     `json:"start_time"`
-	EndTime        *time.Time    `json:"end_time,omitempty"`
-	Error          string        `json:"error,omitempty"`
-	Results        []ScanResult  `json:"-"` // Keep results associated, but maybe not always in status response
+	JobID           string         `json:"job_id"`
+	Status          string         `json:"status"` // e.g., "Pending", "Queued", "Running", "Completed", "Error", "Cancelled"
+	TotalURLs       int            `json:"total_urls"`
+	Threads         int            `json:"threads,omitempty"`  // Workers used by the job
+	Campaign        string         `json:"campaign,omitempty"` // Campaign the job belongs to
+	Label           string         `json:"label,omitempty"`    // Run label within the campaign
+	Priority        int            `json:"priority,omitempty"` // Start order among queued jobs, higher first
+	ProcessedURLs   int            `json:"processed_urls"`
+	VulnerableURLs  int            `json:"vulnerable_urls"`
+	InterestingURLs int            `json:"interesting_urls"`       // Results in the "interesting" tier
+	StatusCodes     map[string]int `json:"status_codes,omitempty"` // Responses per status code ("error" for failed requests), filtered ones included
+	StartTime       time.Time      `json:"start_time"`
+	EndTime         *time.Time     `json:"end_time,omitempty"`
+	Error           string         `json:"error,omitempty"`
+	Inputs          *InputDigest   `json:"inputs,omitempty"`           // Digest of the job's targets and rules
+	DownloadURL     string         `json:"download_url,omitempty"`     // Signed, expiring link to the results of a finished job (--api-link-ttl)
+	DownloadExpires *time.Time     `json:"download_expires,omitempty"` // When DownloadURL stops working
+	Hosts           []HostSummary  `json:"hosts,omitempty"`            // Per-host rollup, with ?hosts=true on the status endpoint
+	Results         []ScanResult   `json:"results,omitempty"`          // Only populated by the result endpoint
+	ResultsMatched  *int           `json:"results_matched,omitempty"`  // Result endpoint: results passing its filters, across all pages
+	NextOffset      int            `json:"next_offset,omitempty"`      // Result endpoint: ?offset= of the next page (0 = last page)
+	Partial         bool           `json:"partial,omitempty"`          // Result endpoint: the job is still running, more results may follow
+}
+
+// HostSummary rolls up a job's results for one host (scheme-less host[:port]).
+type HostSummary struct {
+	Host       string `json:"host"`
+	Scanned    int    `json:"scanned"`            // Results so far, filtered ones included
+	Vulnerable int    `json:"vulnerable"`         // Vulnerable results
+	Errors     int    `json:"errors"`             // Failed requests
+	Severity   string `json:"severity,omitempty"` // Worst severity among matched rules
+}
+
+// ScanRequest is the JSON body accepted by POST /scan/start.
+type ScanRequest struct {
+	URLs              []string          `json:"urls"`
+	TargetList        string            `json:"target_list,omitempty"` // ID of a list from POST /scan/upload, scanned before urls
+	Keywords          []string          `json:"keywords"`
+	KeywordHosts      []string          `json:"keyword_hosts,omitempty"` // Host patterns where keywords count (default: every host)
+	KeywordTech       []string          `json:"keyword_tech,omitempty"`  // Technologies where keywords count (enables tech_detect)
+	TimeoutSec        int               `json:"timeout_sec,omitempty"`
+	ConnectTimeoutSec int               `json:"connect_timeout_sec,omitempty"` // TCP connect budget (default: bounded by timeout_sec)
+	TLSTimeoutSec     int               `json:"tls_timeout_sec,omitempty"`     // TLS handshake budget (default 10)
+	HeaderTimeoutSec  int               `json:"header_timeout_sec,omitempty"`  // Wait for response headers (default: bounded by timeout_sec)
+	ReadTimeoutSec    int               `json:"read_timeout_sec,omitempty"`    // Body read once headers arrived (default: bounded by timeout_sec)
+	Threads           int               `json:"threads,omitempty"`
+	DelayMs           int               `json:"delay_ms,omitempty"`
+	Verbose           bool              `json:"verbose,omitempty"`           // Allow setting verbose for API scan
+	MatchCodes        []int             `json:"match_codes,omitempty"`       // Status codes required for a match
+	FilterCodes       []int             `json:"filter_codes,omitempty"`      // Status codes discarded before matching
+	MatchSize         string            `json:"match_size,omitempty"`        // Size expressions, same syntax as --match-size
+	FilterSize        string            `json:"filter_size,omitempty"`       // Size expressions, same syntax as --filter-size
+	SkipBinary        bool              `json:"skip_binary,omitempty"`       // Skip matching on non-text content types
+	TLSVerify         bool              `json:"tls_verify,omitempty"`        // Fail requests whose certificate does not verify
+	Entropy           bool              `json:"entropy,omitempty"`           // Report high-entropy strings as low-severity findings
+	EntropyThreshold  float64           `json:"entropy_threshold,omitempty"` // Bits per character, default 4.5
+	LoginRedirects    string            `json:"login_redirects,omitempty"`   // Findings on login pages reached via redirect: off, downgrade (default) or suppress
+	FullBody          bool              `json:"full_body,omitempty"`         // Always download complete bodies
+	MaxBodySize       int64             `json:"max_body_size,omitempty"`     // Body size cap in bytes (default 10MB)
+	Selectors         []string          `json:"selectors,omitempty"`         // CSS selectors that mark a response vulnerable
+	JSONPaths         []string          `json:"jsonpaths,omitempty"`         // JSONPath expressions that mark a JSON response vulnerable
+	Recipes           []string          `json:"recipes,omitempty"`           // Built-in recipes, e.g. "exposed-git"
+	Crawl             bool              `json:"crawl,omitempty"`             // Follow links found in HTML responses
+	CrawlDepth        int               `json:"crawl_depth,omitempty"`       // Maximum link depth from each seed (default 2)
+	CrawlMaxPages     int               `json:"crawl_max_pages,omitempty"`   // Maximum pages added by the crawl (default 500)
+	CrawlScope        string            `json:"crawl_scope,omitempty"`       // host (default) or subdomains
+	CrawlExclude      string            `json:"crawl_exclude,omitempty"`     // Regex of links not to follow
+	Sitemap           bool              `json:"sitemap,omitempty"`           // Also scan the paths listed in robots.txt and sitemap.xml of each host
+	Shuffle           bool              `json:"shuffle,omitempty"`           // Scan the URLs in random order
+	Interleave        bool              `json:"interleave,omitempty"`        // Round-robin the URLs across hosts
+	Evasion           bool              `json:"evasion,omitempty"`           // Adaptive evasion for hosts that keep blocking (server-side proxies only)
+	TechDetect        bool              `json:"tech_detect,omitempty"`       // Tag results with detected technologies (built-in fingerprints)
+	DedupeResponses   bool              `json:"dedupe_responses,omitempty"`  // Skip matching/storing bodies identical to an earlier response
+	Pins              []string          `json:"pins,omitempty"`              // Certificate pins, e.g. "*.example.com=sha256/<hex>"
+	StallTimeoutSec   int               `json:"stall_timeout_sec,omitempty"` // Seconds without results before a stall is logged (default 300)
+	StallAbort        bool              `json:"stall_abort,omitempty"`       // Abort in-flight requests to stalled hosts
+	Headers           map[string]string `json:"headers,omitempty"`           // Extra headers sent with every request
+	VHosts            []string          `json:"vhosts,omitempty"`            // Host names tried against every URL (virtual host mode)
+	Campaign          string            `json:"campaign,omitempty"`          // Group the job into this campaign (GET /campaigns/{id})
+	Label             string            `json:"label,omitempty"`             // Run label within the campaign, e.g. the profile
+	AuthBasic         string            `json:"auth_basic,omitempty"`        // "user:pass" for HTTP Basic auth
+	AuthBearer        string            `json:"auth_bearer,omitempty"`       // Bearer token
+	Cookies           string            `json:"cookies,omitempty"`           // Cookie header sent with every request, e.g. "a=b; c=d"
+	CookieJar         bool              `json:"cookie_jar,omitempty"`        // Keep cookies set by targets during the scan
+	Method            string            `json:"method,omitempty"`            // HTTP method (default GET, or POST with data)
+	Data              string            `json:"data,omitempty"`              // Request body sent to every target
+	ContentType       string            `json:"content_type,omitempty"`      // Content-Type of data (inferred when empty)
+	Proxy             string            `json:"proxy,omitempty"`             // http, https or socks5 proxy for every request (default: the server's environment)
+	Retries           int               `json:"retries,omitempty"`           // Retries of requests that got no response, at most 10
+	MaxRedirects      int               `json:"max_redirects,omitempty"`     // Redirects followed per request (default 10, at most 50)
+	NoRedirects       bool              `json:"no_redirects,omitempty"`      // Scan redirect responses instead of following them
+	ESURL             string            `json:"es_url,omitempty"`            // Bulk-index every result into this Elasticsearch/OpenSearch cluster as it arrives
+	ESIndex           string            `json:"es_index,omitempty"`          // Index of es_url documents (default hx-hawks)
+	Fields            string            `json:"fields,omitempty"`            // Comma-separated result fields sent to es_url, like --fields
+	Notify            []NotifyTarget    `json:"notify,omitempty"`            // Told about findings and the finished scan, like --notify-config providers
+	NotifyOn          []string          `json:"notify_on,omitempty"`         // Events notified: findings, complete (default both)
+	Priority          int               `json:"priority,omitempty"`          // Queued jobs with a higher priority start first (default 0)
+	CallbackURL       string            `json:"callback_url,omitempty"`      // POSTed a "complete" event when the job finishes
+	CallbackResults   string            `json:"callback_results,omitempty"`  // Also POST each result: vulnerable or all
+	CallbackSecret    string            `json:"callback_secret,omitempty"`   // HMAC key of X-Hawks-Signature (default --api-callback-secret)
+}
+
+// NotifyTarget is a notification provider of a scan request, laid out like a
+// --notify-config provider.
+type NotifyTarget struct {
+	Type   string `json:"type"`              // slack, discord, telegram or webhook
+	URL    string `json:"url,omitempty"`     // Incoming webhook URL (slack, discord, webhook)
+	Token  string `json:"token,omitempty"`   // Bot token (telegram)
+	ChatID string `json:"chat_id,omitempty"` // Chat or channel ID (telegram)
+}
+
+// CallbackPayload is POSTed to a job's callback_url.
+type CallbackPayload struct {
+	Event   string      `json:"event"` // "result" or "complete"
+	JobID   string      `json:"job_id"`
+	SentAt  time.Time   `json:"sent_at"`
+	Index   int         `json:"index,omitempty"`           // "result": 1-based position in the job's results
+	Result  *ScanResult `json:"result,omitempty"`          // "result": without the response body
+	Status  *JobStatus  `json:"status,omitempty"`          // "complete": final status, with download_url under --api-link-ttl
+	Dropped int         `json:"dropped_results,omitempty"` // "complete": result events dropped because the receiver fell behind
+}
+
+// JobSchedule is a recurring scan (POST /schedules): whenever its cron
+// expression matches, a job is started from its template or request.
+type JobSchedule struct {
+	ID         string       `json:"schedule_id"`
+	Name       string       `json:"name,omitempty"`
+	Cron       string       `json:"cron"`                  // "minute hour day-of-month month day-of-week" in UTC, or @hourly, @daily, @weekly, @monthly
+	TemplateID string       `json:"template_id,omitempty"` // Start this template (POST /scan/templates)...
+	Request    *ScanRequest `json:"request,omitempty"`     // ...or this request; a target_list is inlined when the schedule is created
+	Created    time.Time    `json:"created"`
+	NextRun    time.Time    `json:"next_run"`
+	LastRun    *time.Time   `json:"last_run,omitempty"`
+	LastJobID  string       `json:"last_job_id,omitempty"`
+	LastError  string       `json:"last_error,omitempty"` // Why the last run started no job
+	Runs       int          `json:"runs,omitempty"`       // Jobs started so far
+}
+
+// TargetListInfo is returned by POST /scan/upload.
+type TargetListInfo struct {
+	TargetListID string    `json:"target_list_id"` // For the target_list field of /scan/start
+	URLs         int       `json:"urls"`
+	Skipped      int       `json:"skipped,omitempty"` // Bare hosts, which API scans don't probe
+	Expires      time.Time `json:"expires"`
+}
+
+// AddTargetsResponse is returned by POST /scan/{id}/targets.
+type AddTargetsResponse struct {
+	JobID     string `json:"job_id"`
+	Added     int    `json:"added"`      // URLs queued, including rule probe paths
+	TotalURLs int    `json:"total_urls"` // Job total after the addition
+}
+
+// JobList is one page of GET /scan/jobs.
+type JobList struct {
+	Jobs    []JobStatus `json:"jobs"`  // Without results, oldest first
+	Total   int         `json:"total"` // Jobs matching the status filter, across all pages
+	Page    int         `json:"page"`
+	PerPage int         `json:"per_page"`
+}
+
+// WSMessage is a message sent to /ws/scan/{id} clients.
+type WSMessage struct {
+	Type   string      `json:"type"`             // "progress", "result", "complete", "error" or "keep-alive"
+	Index  int         `json:"index,omitempty"`  // "result": 1-based position of the result in the job
+	Result *ScanResult `json:"result,omitempty"` // "result"
+	Status *JobStatus  `json:"status,omitempty"` // "progress" and "complete": job status without results
+	Error  string      `json:"error,omitempty"`  // "error"
+}
+
+// WSCommand is a message a /ws/scan/{id} client sends to control the job.
+type WSCommand struct {
+	Action string `json:"action"` // "cancel"
+}
+
+// InputDigest identifies the exact inputs of a scan, so a report can be
+// checked against them later (hx-hawks verify).
+type InputDigest struct {
+	TargetsSHA256 string `json:"targets_sha256"` // Target list as read, before probe-path expansion
+	RulesSHA256   string `json:"rules_sha256"`   // Keywords and rules, in order
+}
+
+// CampaignInfo is one entry of GET /campaigns.
+type CampaignInfo struct {
+	Campaign string   `json:"campaign"`
+	Jobs     []string `json:"jobs"` // Job IDs, oldest first
+}
+
+// JobTemplate is a stored job definition (POST /scan/templates), launched
+// with POST /scan/templates/{id}/run instead of re-sending the full request.
+type JobTemplate struct {
+	ID      string      `json:"template_id"`
+	Name    string      `json:"name,omitempty"`
+	Request ScanRequest `json:"request"` // Same body as POST /scan/start
+	Created time.Time   `json:"created"`
+	Runs    []string    `json:"runs,omitempty"` // Job IDs started from the template, oldest first
 }
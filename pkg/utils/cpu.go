@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"bufio"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// AvailableCPUs returns how many CPUs the process may use: runtime.NumCPU,
+// lowered to the CPU quota of the container (cgroup v2 cpu.max or v1
+// cpu.cfs_quota_us), rounded up.
+func AvailableCPUs() int {
+	cpus := runtime.NumCPU()
+	if quota, ok := cgroupCPUQuota(); ok {
+		if limit := int(math.Ceil(quota)); limit >= 1 && limit < cpus {
+			cpus = limit
+		}
+	}
+	return cpus
+}
+
+// cgroupCPUQuota returns the CPU quota of the process's cgroup in CPUs, if one is set.
+func cgroupCPUQuota() (float64, bool) {
+	v1, v2 := cgroupPaths()
+	for _, dir := range v2 {
+		data, err := os.ReadFile(filepath.Join(dir, "cpu.max"))
+		if err != nil {
+			continue
+		}
+		// "<quota> <period>", quota "max" = unlimited
+		fields := strings.Fields(string(data))
+		if len(fields) != 2 || fields[0] == "max" {
+			return 0, false
+		}
+		return quotaRatio(fields[0], fields[1])
+	}
+	for _, dir := range v1 {
+		quota, err := os.ReadFile(filepath.Join(dir, "cpu.cfs_quota_us"))
+		if err != nil {
+			continue
+		}
+		period, err := os.ReadFile(filepath.Join(dir, "cpu.cfs_period_us"))
+		if err != nil {
+			continue
+		}
+		return quotaRatio(strings.TrimSpace(string(quota)), strings.TrimSpace(string(period)))
+	}
+	return 0, false
+}
+
+// quotaRatio divides a quota by its period; a negative quota means unlimited.
+func quotaRatio(quota, period string) (float64, bool) {
+	q, err1 := strconv.ParseFloat(quota, 64)
+	p, err2 := strconv.ParseFloat(period, 64)
+	if err1 != nil || err2 != nil || q <= 0 || p <= 0 {
+		return 0, false
+	}
+	return q / p, true
+}
+
+// cgroupPaths returns the candidate cgroup v1 (cpu controller) and v2
+// directories of the process, most specific first.
+func cgroupPaths() (v1, v2 []string) {
+	const root = "/sys/fs/cgroup"
+	f, err := os.Open("/proc/self/cgroup")
+	if err == nil {
+		defer f.Close()
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			// "<id>:<controllers>:<path>"
+			parts := strings.SplitN(scanner.Text(), ":", 3)
+			if len(parts) != 3 || parts[2] == "/" {
+				continue
+			}
+			if parts[0] == "0" && parts[1] == "" {
+				v2 = append(v2, filepath.Join(root, parts[2]))
+				continue
+			}
+			for _, controller := range strings.Split(parts[1], ",") {
+				if controller == "cpu" {
+					v1 = append(v1, filepath.Join(root, parts[1], parts[2]))
+				}
+			}
+		}
+	}
+	// Inside a cgroup namespace the process's own cgroup is the root
+	return append(v1, filepath.Join(root, "cpu")), append(v2, root)
+}
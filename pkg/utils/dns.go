@@ -0,0 +1,165 @@
+package utils
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// ErrDNSReplyMismatch is returned when a nameserver's reply does not match
+// the query ID (a stray or spoofed packet).
+var ErrDNSReplyMismatch = errors.New("dns reply ID mismatch")
+
+// Resolution is the full DNS picture for a host.
+type Resolution struct {
+	IPs    []string // Every resolved address, IPv4 first
+	CNAMEs []string // CNAME chain in resolution order (empty if none)
+}
+
+// PrimaryIP returns the first resolved address (IPv4 preferred), or "".
+func (r Resolution) PrimaryIP() string {
+	if len(r.IPs) == 0 {
+		return ""
+	}
+	return r.IPs[0]
+}
+
+// ResolveURL resolves the host of a URL. See ResolveHost.
+func ResolveURL(targetURL string) Resolution {
+	u, err := url.Parse(targetURL)
+	if err != nil {
+		return Resolution{}
+	}
+	return ResolveHost(u.Hostname())
+}
+
+// ResolveHost looks up every IPv4/IPv6 address of a host and its CNAME chain,
+// for reporting. Results are cached, so each host is resolved once per scan.
+func ResolveHost(host string) Resolution {
+	var res Resolution
+	if host == "" {
+		return res
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		res.IPs = []string{ip.String()}
+		return res
+	}
+	if ip := overrideFor(host); ip != "" {
+		res.IPs = []string{ip} // Pinned with --resolve, DNS is not asked
+		return res
+	}
+	ctx := context.Background()
+	res.IPs, _ = lookupIPs(ctx, host)
+	res.CNAMEs, _ = cachedLookup(ctx, "cname "+host, func(context.Context) ([]string, error) {
+		return cnameChain(host), nil
+	})
+	return res
+}
+
+// lookupIPs returns the cached addresses of a hostname, IPv4 first, looking
+// them up through DNS within ctx on a miss.
+func lookupIPs(ctx context.Context, host string) ([]string, error) {
+	return cachedLookup(ctx, "ip "+host, func(ctx context.Context) ([]string, error) {
+		ips, err := Resolver().LookupIP(ctx, "ip", host)
+		if err != nil {
+			return nil, err
+		}
+		var v4, v6 []string
+		for _, ip := range ips {
+			if ip.To4() != nil {
+				v4 = append(v4, ip.String())
+			} else {
+				v6 = append(v6, ip.String())
+			}
+		}
+		return append(v4, v6...), nil
+	})
+}
+
+// cnameChain queries the nameservers (--resolvers or the system ones) directly
+// so that every CNAME hop is visible (LookupCNAME only reports the final
+// canonical name).
+func cnameChain(host string) []string {
+	fqdn := strings.TrimSuffix(host, ".") + "."
+	for _, server := range activeNameservers() {
+		answers, err := queryA(server, fqdn)
+		if err != nil {
+			continue
+		}
+		var chain []string
+		for _, a := range answers {
+			if a.Header.Type == dnsmessage.TypeCNAME {
+				if body, ok := a.Body.(*dnsmessage.CNAMEResource); ok {
+					chain = append(chain, strings.TrimSuffix(body.CNAME.String(), "."))
+				}
+			}
+		}
+		return chain
+	}
+
+	// No usable nameserver: fall back to the final canonical name only
+	if cname, err := Resolver().LookupCNAME(context.Background(), host); err == nil {
+		cname = strings.TrimSuffix(cname, ".")
+		if cname != "" && !strings.EqualFold(cname, strings.TrimSuffix(host, ".")) {
+			return []string{cname}
+		}
+	}
+	return nil
+}
+
+// queryA sends a single A query (over UDP, or HTTPS for DoH servers) and
+// returns the answer records.
+func queryA(server, fqdn string) ([]dnsmessage.Resource, error) {
+	name, err := dnsmessage.NewName(fqdn)
+	if err != nil {
+		return nil, err
+	}
+	id := uint16(rand.Intn(1 << 16))
+	msg := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: id, RecursionDesired: true},
+		Questions: []dnsmessage.Question{{Name: name, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET}},
+	}
+	packed, err := msg.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := exchange(server, packed)
+	if err != nil {
+		return nil, err
+	}
+	var reply dnsmessage.Message
+	if err := reply.Unpack(raw); err != nil {
+		return nil, err
+	}
+	if reply.Header.ID != id {
+		return nil, ErrDNSReplyMismatch
+	}
+	return reply.Answers, nil
+}
+
+// systemNameservers returns the nameservers from /etc/resolv.conf as host:port.
+func systemNameservers() []string {
+	f, err := os.Open("/etc/resolv.conf")
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var servers []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "nameserver" {
+			servers = append(servers, net.JoinHostPort(fields[1], "53"))
+		}
+	}
+	return servers
+}
@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	dnsCacheTTL         = 10 * time.Minute // Lifetime of a resolution (long-running API servers)
+	dnsNegativeCacheTTL = 30 * time.Second // Lifetime of a lookup that found no address
+	dnsCacheMaxEntries  = 10000            // Entries kept before expired ones are swept early
+	dnsCacheSweep       = time.Minute      // Interval between sweeps of expired entries
+)
+
+// dnsCache remembers lookups so every host is resolved once per scan,
+// however many of its URLs are scanned. Concurrent lookups of the same key
+// wait for the first one instead of querying again. Expired entries are
+// swept every dnsCacheSweep, and whenever dnsCacheMaxEntries is reached.
+var dnsCache = struct {
+	sync.Mutex
+	entries   map[string]*dnsEntry
+	nextSweep time.Time
+}{entries: make(map[string]*dnsEntry)}
+
+// dnsEntry is a cached (or in-flight) lookup.
+type dnsEntry struct {
+	done      chan struct{} // Closed once the lookup finished
+	values    []string
+	err       error
+	cancelled bool // The looking-up caller gave up; waiters look up themselves
+	expires   time.Time
+}
+
+// cachedLookup returns the cached values of key ("<kind> <host>"), calling
+// lookup on a miss. A lookup cut short by its caller's ctx is not cached.
+func cachedLookup(ctx context.Context, key string, lookup func(context.Context) ([]string, error)) ([]string, error) {
+	key = strings.ToLower(strings.TrimSuffix(key, "."))
+	for {
+		now := time.Now()
+		dnsCache.Lock()
+		e, ok := dnsCache.entries[key]
+		if !ok || (isClosed(e.done) && now.After(e.expires)) {
+			sweepDNSCache(now)
+			e = &dnsEntry{done: make(chan struct{})}
+			dnsCache.entries[key] = e
+			dnsCache.Unlock()
+
+			e.values, e.err = lookup(ctx)
+			if e.err != nil && ctx.Err() != nil {
+				e.cancelled = true
+				dnsCache.Lock()
+				if dnsCache.entries[key] == e {
+					delete(dnsCache.entries, key)
+				}
+				dnsCache.Unlock()
+				close(e.done)
+				return nil, e.err
+			}
+			ttl := dnsCacheTTL
+			if len(e.values) == 0 {
+				ttl = dnsNegativeCacheTTL
+			}
+			e.expires = time.Now().Add(ttl)
+			close(e.done)
+			return append([]string(nil), e.values...), e.err
+		}
+		dnsCache.Unlock()
+
+		select {
+		case <-e.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if !e.cancelled {
+			return append([]string(nil), e.values...), e.err
+		}
+	}
+}
+
+// sweepDNSCache drops expired entries when a sweep is due or the cache is
+// full; if it is still full, finished entries are dropped until it isn't.
+// Callers hold dnsCache.
+func sweepDNSCache(now time.Time) {
+	if now.Before(dnsCache.nextSweep) && len(dnsCache.entries) < dnsCacheMaxEntries {
+		return
+	}
+	dnsCache.nextSweep = now.Add(dnsCacheSweep)
+	for key, e := range dnsCache.entries {
+		if isClosed(e.done) && now.After(e.expires) {
+			delete(dnsCache.entries, key)
+		}
+	}
+	for key, e := range dnsCache.entries {
+		if len(dnsCache.entries) < dnsCacheMaxEntries {
+			break
+		}
+		if isClosed(e.done) {
+			delete(dnsCache.entries, key)
+		}
+	}
+}
+
+// resetDNSCache forgets every resolution, e.g. after the resolvers changed.
+func resetDNSCache() {
+	dnsCache.Lock()
+	defer dnsCache.Unlock()
+	dnsCache.entries = make(map[string]*dnsEntry)
+}
+
+// isClosed reports whether ch has been closed.
+func isClosed(ch chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
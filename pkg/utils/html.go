@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"bytes"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// maxTitleLength caps extracted titles so odd pages can't flood outputs.
+const maxTitleLength = 256
+
+// ExtractTitle returns the text of the first <title> element in an HTML
+// body, with whitespace collapsed, or "" if there is none.
+func ExtractTitle(body []byte) string {
+	if !bytes.Contains(bytes.ToLower(body), []byte("<title")) {
+		return "" // Cheap check before tokenizing
+	}
+
+	z := html.NewTokenizer(bytes.NewReader(body))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return ""
+		case html.StartTagToken:
+			name, _ := z.TagName()
+			if string(name) != "title" {
+				continue
+			}
+			if z.Next() != html.TextToken {
+				return ""
+			}
+			title := strings.Join(strings.Fields(string(z.Text())), " ")
+			if len(title) > maxTitleLength {
+				title = title[:maxTitleLength]
+			}
+			return title
+		}
+	}
+}
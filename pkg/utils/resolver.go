@@ -0,0 +1,237 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Nameservers set with SetResolvers (--resolvers). When empty, lookups use the
+// system resolver and the nameservers of /etc/resolv.conf.
+var (
+	resolverMu  sync.RWMutex
+	nameservers []string          // "ip:port" (UDP/TCP DNS) or "https://..." (DNS over HTTPS)
+	netResolver *net.Resolver     // Resolver that only talks to nameservers
+	nextServer  uint32            // Round-robin index, so retries move to the next server
+	overrides   map[string]string // Lowercase host -> IP pinned with SetOverrides (--resolve)
+)
+
+// dohClient sends DNS-over-HTTPS queries.
+var dohClient = &http.Client{Timeout: 5 * time.Second}
+
+// SetResolvers makes every lookup (ResolveHost, GetIP and DialContext) use these
+// nameservers instead of the system resolver; nil restores the system one.
+// Servers are "ip:port" or DNS-over-HTTPS URLs, as returned by
+// config.ParseResolvers, and are used in turn.
+func SetResolvers(servers []string) {
+	resolverMu.Lock()
+	defer resolverMu.Unlock()
+	nameservers = servers
+	resetDNSCache()
+	if len(servers) == 0 {
+		netResolver = nil
+		return
+	}
+	netResolver = &net.Resolver{PreferGo: true, Dial: dialNameserver}
+}
+
+// SetOverrides pins hosts to IPs for every lookup and connection, like
+// /etc/hosts entries scoped to the scan (--resolve). Keys are lowercase hosts.
+func SetOverrides(hosts map[string]string) {
+	resolverMu.Lock()
+	defer resolverMu.Unlock()
+	overrides = hosts
+}
+
+// overrideFor returns the IP pinned for host, or "".
+func overrideFor(host string) string {
+	resolverMu.RLock()
+	defer resolverMu.RUnlock()
+	return overrides[strings.ToLower(strings.TrimSuffix(host, "."))]
+}
+
+// Resolver returns the resolver lookups go through: the one built by
+// SetResolvers, or net.DefaultResolver.
+func Resolver() *net.Resolver {
+	resolverMu.RLock()
+	defer resolverMu.RUnlock()
+	if netResolver == nil {
+		return net.DefaultResolver
+	}
+	return netResolver
+}
+
+// DialContext dials like http.DefaultTransport, but connects pinned hosts
+// (SetOverrides) to their IP and takes the addresses of the rest from the
+// DNS cache, looked up within ctx. Addresses of the family network asks for
+// (tcp4, tcp6) are tried in order.
+func DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	d := net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Resolver: Resolver()}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || net.ParseIP(host) != nil {
+		return d.DialContext(ctx, network, addr)
+	}
+	var ips []string
+	if ip := overrideFor(host); ip != "" {
+		ips = []string{ip}
+	} else if ips, err = lookupIPs(ctx, host); err != nil {
+		return nil, &net.OpError{Op: "dial", Net: network, Err: err}
+	}
+	ips = filterFamily(ips, network)
+	if len(ips) == 0 {
+		return nil, &net.OpError{Op: "dial", Net: network, Err: &net.AddrError{Err: "no suitable address found", Addr: host}}
+	}
+	for _, ip := range ips {
+		var conn net.Conn
+		if conn, err = d.DialContext(ctx, network, net.JoinHostPort(ip, port)); err == nil {
+			return conn, nil
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, err
+}
+
+// filterFamily keeps the addresses network can reach: IPv4 ones for tcp4,
+// IPv6 ones for tcp6, all otherwise.
+func filterFamily(ips []string, network string) []string {
+	if !strings.HasSuffix(network, "4") && !strings.HasSuffix(network, "6") {
+		return ips
+	}
+	var kept []string
+	for _, s := range ips {
+		ip := net.ParseIP(s)
+		if ip != nil && (ip.To4() != nil) == strings.HasSuffix(network, "4") {
+			kept = append(kept, s)
+		}
+	}
+	return kept
+}
+
+// activeNameservers returns the configured nameservers, or the system ones.
+func activeNameservers() []string {
+	resolverMu.RLock()
+	servers := nameservers
+	resolverMu.RUnlock()
+	if len(servers) > 0 {
+		return servers
+	}
+	return systemNameservers()
+}
+
+// dialNameserver connects the Go resolver to the next configured nameserver,
+// ignoring the (system) address it asks for.
+func dialNameserver(ctx context.Context, network, _ string) (net.Conn, error) {
+	resolverMu.RLock()
+	servers := nameservers
+	resolverMu.RUnlock()
+	if len(servers) == 0 {
+		return nil, fmt.Errorf("no nameservers configured")
+	}
+	server := servers[int(atomic.AddUint32(&nextServer, 1)-1)%len(servers)]
+	if strings.HasPrefix(server, "https://") {
+		return &dohConn{ctx: ctx, url: server}, nil
+	}
+	var d net.Dialer
+	return d.DialContext(ctx, network, server)
+}
+
+// exchange sends a packed DNS query to a nameserver and returns the packed reply.
+func exchange(server string, query []byte) ([]byte, error) {
+	if strings.HasPrefix(server, "https://") {
+		return dohExchange(context.Background(), server, query)
+	}
+	conn, err := net.DialTimeout("udp", server, 3*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(3 * time.Second))
+	if _, err := conn.Write(query); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 4096)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf[:n], nil
+}
+
+// dohExchange sends a DNS query as an RFC 8484 POST request.
+func dohExchange(ctx context.Context, endpoint string, query []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(query))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/dns-message")
+	req.Header.Set("Accept", "application/dns-message")
+	resp, err := dohClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("DoH server %s answered %s", endpoint, resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+}
+
+// dohConn lets the Go resolver talk to a DoH server. The resolver treats it
+// as a stream connection: every length-prefixed query written is sent as one
+// HTTPS request and the length-prefixed reply is queued for reading.
+type dohConn struct {
+	ctx context.Context
+	url string
+	out bytes.Buffer // Pending query bytes
+	in  bytes.Buffer // Replies not read yet
+}
+
+func (c *dohConn) Write(b []byte) (int, error) {
+	c.out.Write(b)
+	for c.out.Len() >= 2 {
+		size := int(binary.BigEndian.Uint16(c.out.Bytes()))
+		if c.out.Len() < 2+size {
+			break
+		}
+		query := c.out.Next(2 + size)[2:]
+		reply, err := dohExchange(c.ctx, c.url, query)
+		if err != nil {
+			return 0, err
+		}
+		var prefix [2]byte
+		binary.BigEndian.PutUint16(prefix[:], uint16(len(reply)))
+		c.in.Write(prefix[:])
+		c.in.Write(reply)
+	}
+	return len(b), nil
+}
+
+func (c *dohConn) Read(b []byte) (int, error) {
+	if c.in.Len() == 0 {
+		return 0, io.EOF
+	}
+	return c.in.Read(b)
+}
+
+func (c *dohConn) Close() error                       { return nil }
+func (c *dohConn) LocalAddr() net.Addr                { return dohAddr(c.url) }
+func (c *dohConn) RemoteAddr() net.Addr               { return dohAddr(c.url) }
+func (c *dohConn) SetDeadline(t time.Time) error      { return nil } // Bounded by ctx and dohClient's timeout
+func (c *dohConn) SetReadDeadline(t time.Time) error  { return nil }
+func (c *dohConn) SetWriteDeadline(t time.Time) error { return nil }
+
+// dohAddr is the net.Addr of a DoH endpoint.
+type dohAddr string
+
+func (a dohAddr) Network() string { return "https" }
+func (a dohAddr) String() string  { return string(a) }
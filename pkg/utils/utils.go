@@ -2,23 +2,48 @@ package utils
 
 import (
 	"bufio"
+	"errors"
+	"fmt"
+	"io"
 	"log"
-	"net"
 	"net/url"
 	"os"
 	"strings"
 )
 
+// ErrNoTargets is wrapped by ReadLines when a file holds no valid http(s) URLs or hosts.
+var ErrNoTargets = errors.New("no valid URLs found")
+
+// InputError reports a target list that could not be used; Err is the
+// underlying I/O error or ErrNoTargets.
+type InputError struct {
+	Path string
+	Err  error
+}
+
+func (e *InputError) Error() string {
+	return fmt.Sprintf("input file %s: %v", e.Path, e.Err)
+}
+
+func (e *InputError) Unwrap() error { return e.Err }
+
 // ReadLines reads a file line by line and returns a slice of strings.
+// Lines are http(s) URLs or bare hosts (see IsBareHost), which are kept
+// scheme-less for the scanner to probe. Failures are returned as *InputError.
 func ReadLines(filePath string) ([]string, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
-		return nil, err
+		return nil, &InputError{Path: filePath, Err: err}
 	}
 	defer file.Close()
+	return ReadLinesFrom(file, filePath)
+}
 
+// ReadLinesFrom is ReadLines for a stream, e.g. an uploaded file; name
+// identifies it in errors.
+func ReadLinesFrom(r io.Reader, name string) ([]string, error) {
 	var lines []string
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
 		if line != "" && (strings.HasPrefix(line, "http://") || strings.HasPrefix(line, "https://")) {
@@ -29,34 +54,37 @@ func ReadLines(filePath string) ([]string, error) {
 			} else {
 				log.Printf("[!] Skipping invalid URL format: %s", line)
 			}
+		} else if IsBareHost(line) {
+			lines = append(lines, line) // Scheme is probed before the scan (https first, then http)
 		} else if line != "" {
-			log.Printf("[!] Skipping line (missing http/https prefix): %s", line)
+			log.Printf("[!] Skipping line (not an http(s) URL or host): %s", line)
 		}
 	}
 
 	if err := scanner.Err(); err != nil {
-		return nil, err
+		return nil, &InputError{Path: name, Err: err}
+	}
+	if len(lines) == 0 {
+		return nil, &InputError{Path: name, Err: ErrNoTargets}
 	}
 
 	return lines, nil
 }
 
+// IsBareHost reports whether line is a scheme-less target such as
+// "example.com", "10.0.0.5:8080" or "example.com/admin", as printed by most
+// recon tools.
+func IsBareHost(line string) bool {
+	if line == "" || strings.Contains(line, "://") || strings.ContainsAny(line, " \t") {
+		return false
+	}
+	u, err := url.Parse("//" + line)
+	return err == nil && u.Hostname() != ""
+}
+
 // GetIP attempts to resolve the IP address for a given URL's host.
+// It returns the first resolved IP, preferring IPv4; see ResolveURL for
+// every address and the CNAME chain.
 func GetIP(targetURL string) string {
-	u, err := url.Parse(targetURL)
-	if err != nil {
-		return "" // Cannot parse URL
-	}
-	host := u.Hostname()
-	ips, err := net.LookupIP(host)
-	if err != nil || len(ips) == 0 {
-		return "" // Cannot resolve IP
-	}
-	// Return the first resolved IP (prefer IPv4 if available)
-	for _, ip := range ips {
-		if ip.To4() != nil {
-			return ip.String()
-		}
-	}
-	return ips[0].String() // Fallback to the first IP (likely IPv6)
+	return ResolveURL(targetURL).PrimaryIP()
 }